@@ -0,0 +1,47 @@
+package delta
+
+// CloneSlice returns a copy of s, preserving nil
+func CloneSlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	cp := make([]T, len(s))
+	copy(cp, s)
+	return cp
+}
+
+// CloneSliceFunc returns a deep copy of s, copying each element with clone
+func CloneSliceFunc[T any](s []T, clone func(T) T) []T {
+	if s == nil {
+		return nil
+	}
+	cp := make([]T, len(s))
+	for i, v := range s {
+		cp[i] = clone(v)
+	}
+	return cp
+}
+
+// CloneMap returns a copy of m, preserving nil
+func CloneMap[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return nil
+	}
+	cp := make(map[K]V, len(m))
+	for k, v := range m {
+		cp[k] = v
+	}
+	return cp
+}
+
+// CloneMapFunc returns a deep copy of m, copying each value with clone
+func CloneMapFunc[K comparable, V any](m map[K]V, clone func(V) V) map[K]V {
+	if m == nil {
+		return nil
+	}
+	cp := make(map[K]V, len(m))
+	for k, v := range m {
+		cp[k] = clone(v)
+	}
+	return cp
+}
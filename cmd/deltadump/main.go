@@ -0,0 +1,196 @@
+// Command deltadump decodes a captured delta using a schema exported by
+// deltagen -schema, printing each field with its byte offset and size.
+//
+//	deltadump -schema schema.json -entity GameState capture.bin
+//	echo 0300000000000000... | deltadump -schema schema.json -entity GameState -hex
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/cbodonnell/delta/gen"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs deltadump with the command line arguments args, reading the
+// capture from stdin when no file is given and printing the delta to stdout,
+// and returns the exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("deltadump", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	schemaFile := flags.String("schema", "", "schema file written by deltagen -schema")
+	entity := flags.String("entity", "", "name of the entity the delta belongs to, qualified by import path if ambiguous")
+	hexInput := flags.Bool("hex", false, "the capture is hex encoded text rather than raw bytes")
+	jsonOutput := flags.Bool("json", false, "print the decoded delta as JSON")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: deltadump -schema file -entity name [-hex] [-json] [capture]\n\nThe capture is read from standard input when no file is given.\n\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *schemaFile == "" || *entity == "" || flags.NArg() > 1 {
+		flags.Usage()
+		return 2
+	}
+
+	schema, err := readSchema(*schemaFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "schema error: %v\n", err)
+		return 1
+	}
+	data, err := readCapture(flags.Arg(0), *hexInput, stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "capture error: %v\n", err)
+		return 1
+	}
+
+	d, decodeErr := gen.DecodeDelta(schema, *entity, data)
+	if d != nil {
+		if *jsonOutput {
+			enc := json.NewEncoder(stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(d); err != nil {
+				fmt.Fprintf(stderr, "output error: %v\n", err)
+				return 1
+			}
+		} else {
+			fmt.Fprintf(stdout, "%6s %5s\n", "offset", "size")
+			printDelta(stdout, d, "", "")
+		}
+	}
+	if decodeErr != nil {
+		fmt.Fprintf(stderr, "decode error: %v\n", decodeErr)
+		return 1
+	}
+	if trailing := len(data) - d.Size; trailing > 0 {
+		fmt.Fprintf(stderr, "%d bytes follow the delta\n", trailing)
+	}
+	return 0
+}
+
+// readSchema reads a schema written by deltagen -schema
+func readSchema(path string) (gen.Schema, error) {
+	var schema gen.Schema
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return schema, err
+	}
+	if err := json.Unmarshal(b, &schema); err != nil {
+		return schema, fmt.Errorf("%s: %w", path, err)
+	}
+	if schema.Version != gen.SchemaVersion {
+		return schema, fmt.Errorf("%s: schema version %d is not supported, want %d", path, schema.Version, gen.SchemaVersion)
+	}
+	return schema, nil
+}
+
+// readCapture reads the captured delta from path, or stdin if path is empty or
+// "-", decoding it from hex when isHex is set. Whitespace between hex digits
+// is ignored.
+func readCapture(path string, isHex bool, stdin io.Reader) ([]byte, error) {
+	var b []byte
+	var err error
+	if path == "" || path == "-" {
+		b, err = io.ReadAll(stdin)
+	} else {
+		b, err = os.ReadFile(path)
+	}
+	if err != nil || !isHex {
+		return b, err
+	}
+
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, string(b))
+	return hex.DecodeString(strings.TrimPrefix(digits, "0x"))
+}
+
+// printDelta prints the fields of d, one per line preceded by their offset and
+// size, indenting nested deltas
+func printDelta(w io.Writer, d *gen.DecodedDelta, indent, label string) {
+	fmt.Fprintf(w, "%6d %5d  %s%s%s delta, mask %#x\n", d.Offset, d.Size, indent, label, d.Entity, d.Mask)
+	indent += "  "
+	for _, f := range d.Fields {
+		prefix := fmt.Sprintf("%6d %5d  %s#%d %s %s", f.Offset, f.Size, indent, f.Number, f.Name, f.Type)
+		switch v := f.Value.(type) {
+		case *gen.CollectionValue:
+			if v == nil {
+				fmt.Fprintf(w, "%s\n", prefix)
+				continue
+			}
+			fmt.Fprintf(w, "%s: %d added, %d changed, removed %s", prefix, len(v.Added), len(v.Changed), formatValue(v.Removed))
+			if v.Order != nil {
+				fmt.Fprintf(w, ", order %s", formatValue(v.Order))
+			}
+			fmt.Fprintln(w)
+			for _, e := range v.Added {
+				printDelta(w, e.Delta, indent+"  ", fmt.Sprintf("added %s: ", formatValue(e.Key)))
+			}
+			for _, e := range v.Changed {
+				printDelta(w, e.Delta, indent+"  ", fmt.Sprintf("changed %s: ", formatValue(e.Key)))
+			}
+		case *gen.InterfaceValue:
+			switch {
+			case v == nil:
+				fmt.Fprintf(w, "%s\n", prefix)
+			case v.TypeID == 0:
+				fmt.Fprintf(w, "%s = nil\n", prefix)
+			case v.Delta == nil:
+				fmt.Fprintf(w, "%s: type %d\n", prefix, v.TypeID)
+			default:
+				label := "changed "
+				if v.Full {
+					label = "replaced by "
+				}
+				fmt.Fprintf(w, "%s: type %d\n", prefix, v.TypeID)
+				printDelta(w, v.Delta, indent+"  ", label)
+			}
+		default:
+			fmt.Fprintf(w, "%s = %s\n", prefix, formatValue(v))
+		}
+	}
+}
+
+// formatValue formats a decoded value on a single line
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return fmt.Sprintf("%q", v)
+	case []byte:
+		return "0x" + hex.EncodeToString(v)
+	case []any:
+		parts := make([]string, len(v))
+		for i, e := range v {
+			parts[i] = formatValue(e)
+		}
+		return "[" + strings.Join(parts, " ") + "]"
+	case []gen.MapEntry:
+		parts := make([]string, len(v))
+		for i, e := range v {
+			parts[i] = formatValue(e.Key) + ":" + formatValue(e.Value)
+		}
+		return "map[" + strings.Join(parts, " ") + "]"
+	case *gen.SetValue:
+		if v == nil {
+			return "?"
+		}
+		return fmt.Sprintf("added %s, removed %s", formatValue(v.Added), formatValue(v.Removed))
+	case nil:
+		return "?"
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const schemaFile = "../../example/schema.json"
+
+// sword is the delta of example.Sword{ID: 1, Sharpness: 10} from its zero value
+var sword = []byte{0x03, 0, 0, 0, 0, 0, 0, 0, 0x01, 0, 0, 0, 0, 0, 0, 0, 0x0a, 0, 0, 0}
+
+func TestReadCapture(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture")
+	tests := []struct {
+		name  string
+		file  string // written to path and read from it when not empty
+		stdin string
+		isHex bool
+	}{
+		{"raw file", string(sword), "", false},
+		{"hex file with whitespace", "0300 0000 0000 0000\n\t0100000000000000 0a000000\n", "", true},
+		{"hex file with 0x prefix", "0x" + hex.EncodeToString(sword), "", true},
+		{"raw stdin", "", string(sword), false},
+		{"hex stdin", "", " 0x" + hex.EncodeToString(sword) + "\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arg := ""
+			if tt.file != "" {
+				if err := os.WriteFile(path, []byte(tt.file), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				arg = path
+			}
+			got, err := readCapture(arg, tt.isHex, strings.NewReader(tt.stdin))
+			if err != nil {
+				t.Fatalf("readCapture() error: %v", err)
+			}
+			if !bytes.Equal(got, sword) {
+				t.Errorf("readCapture() = %x, want %x", got, sword)
+			}
+		})
+	}
+
+	if _, err := readCapture("", true, strings.NewReader("03zz")); err == nil {
+		t.Errorf("readCapture() of invalid hex did not fail")
+	}
+	if _, err := readCapture(filepath.Join(t.TempDir(), "missing"), false, nil); err == nil {
+		t.Errorf("readCapture() of a missing file did not fail")
+	}
+}
+
+func TestRun_PrintDelta(t *testing.T) {
+	// A lobby whose player 1 now holds a sword and which added player 2
+	capture := "04000000000000000102000000000000000900000000000000020000000000000002626f01010000000000000050" +
+		"00000000000000090000000101030000000000000003000000000000000200000000010201000000000000000200000000000000"
+	want := `offset  size
+     0    98  Lobby delta, mask 0x4
+     8    90    #2 Players []Player: 1 added, 1 changed, removed [], order [1 2]
+    17    19      added 2: Player delta, mask 0x9
+    25     8        #0 ID int64 = 2
+    33     3        #3 Name string = "bo"
+    45    34      changed 1: Player delta, mask 0x50
+    53     4        #4 Health int32 = 9
+    57    22        #6 Weapon Weapon: type 1
+    59    20          replaced by Sword delta, mask 0x3
+    67     8            #0 ID int64 = 3
+    75     4            #1 Sharpness int32 = 2
+`
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-schema", schemaFile, "-entity", "Lobby", "-hex"}
+	if code := run(args, strings.NewReader(capture), &stdout, &stderr); code != 0 {
+		t.Fatalf("run() = %d: %s", code, stderr.String())
+	}
+	if got := stdout.String(); got != want {
+		t.Errorf("run() printed\n%s\nwant\n%s", got, want)
+	}
+	if stderr.Len() > 0 {
+		t.Errorf("run() reported %q", stderr.String())
+	}
+}
+
+func TestRun_TrailingBytes(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	args := []string{"-schema", schemaFile, "-entity", "Sword", "-json"}
+	if code := run(args, bytes.NewReader(append(sword, 0xff, 0xff)), &stdout, &stderr); code != 0 {
+		t.Fatalf("run() = %d: %s", code, stderr.String())
+	}
+	if got := stderr.String(); got != "2 bytes follow the delta\n" {
+		t.Errorf("run() reported %q, want the 2 trailing bytes", got)
+	}
+	if !strings.Contains(stdout.String(), `"entity": "Sword"`) {
+		t.Errorf("run() printed %s, want the Sword delta as JSON", stdout.String())
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		stdin  []byte
+		code   int
+		stderr string
+	}{
+		{"missing entity", []string{"-schema", schemaFile}, nil, 2, "usage: deltadump"},
+		{"unknown flag", []string{"-nope"}, nil, 2, "flag provided but not defined"},
+		{"missing schema", []string{"-schema", "missing.json", "-entity", "Sword"}, nil, 1, "schema error"},
+		{"truncated capture", []string{"-schema", schemaFile, "-entity", "Sword"}, sword[:12], 1, "decode error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, bytes.NewReader(tt.stdin), &stdout, &stderr); code != tt.code {
+				t.Errorf("run() = %d, want %d", code, tt.code)
+			}
+			if !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("run() reported %q, want %q", stderr.String(), tt.stderr)
+			}
+		})
+	}
+}
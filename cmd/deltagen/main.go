@@ -1,26 +1,118 @@
 package main
 
 import (
+	"bytes"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cbodonnell/delta/gen"
 )
 
 func main() {
-	input := flag.String("input", ".", "path to Go source file or directory")
-	flag.Parse()
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
 
-	structs, err := Parse(*input)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "parse error: %v\n", err)
-		os.Exit(1)
+// run runs deltagen with the command line arguments args, reporting problems
+// to stderr, and returns the exit code
+func run(args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("deltagen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	input := flags.String("input", ".", "path to Go source file or package directory; append /... to include subdirectories")
+	output := flags.String("output", "", "output directory or .go file (default <package>_deltagen.go in each package directory)")
+	typeNames := flags.String("type", "", "comma-separated list of struct names to generate (default all annotated structs); files per package keep the package's other entities")
+	check := flags.Bool("check", false, "report generated files that are missing or out of date instead of writing them")
+	templateFiles := flags.String("templates", "", "comma-separated list of template files or glob patterns adding to or overriding the built-in templates")
+	lang := flags.String("lang", "go", "language of the generated code: go, typescript or csharp")
+	schema := flags.String("schema", "", "also write a JSON description of the entities' wire format to this file")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := gen.Config{
+		Input:  *input,
+		Output: *output,
+		Lang:   *lang,
+		Schema: *schema,
+	}
+	if *typeNames != "" {
+		cfg.Types = strings.Split(*typeNames, ",")
+	}
+	if *templateFiles != "" {
+		cfg.Templates = strings.Split(*templateFiles, ",")
+	}
+	// when run by go generate without -input, only generate the package of the
+	// file holding the directive
+	if pkg := os.Getenv("GOPACKAGE"); pkg != "" && os.Getenv("GOFILE") != "" && !isFlagSet(flags, "input") {
+		cfg.Package = pkg
 	}
 
-	// write the output to the same directory as the input
-	outputPath := *input
-	err = Generate(outputPath, structs)
+	files, err := gen.Generate(cfg)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "generate error: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	if *check {
+		stale, err := staleFiles(files)
+		if err != nil {
+			fmt.Fprintf(stderr, "check error: %v\n", err)
+			return 1
+		}
+		for _, path := range stale {
+			fmt.Fprintf(stderr, "%s is out of date, run deltagen to regenerate it\n", path)
+		}
+		if len(stale) > 0 {
+			return 1
+		}
+		return 0
+	}
+
+	for _, f := range files {
+		if err := writeFile(f); err != nil {
+			fmt.Fprintf(stderr, "generate error: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// isFlagSet returns true if the named flag was given on the command line
+func isFlagSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// writeFile writes f, creating its directory if needed
+func writeFile(f gen.File) error {
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(f.Path, f.Content, 0o644)
+}
+
+// staleFiles compares the generated files with those on disk and returns the
+// paths of the files that are missing or out of date
+func staleFiles(files []gen.File) ([]string, error) {
+	var stale []string
+	for _, f := range files {
+		existing, err := os.ReadFile(f.Path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		if !bytes.Equal(existing, f.Content) {
+			stale = append(stale, f.Path)
+		}
 	}
+	return stale, nil
 }
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_Check(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "ships_deltagen.go")
+	args := []string{"-input", "../../gen/testdata/fleet/ships", "-output", output}
+	check := append([]string{"-check"}, args...)
+
+	// A missing file is reported without being written
+	var stderr bytes.Buffer
+	if code := run(check, &stderr); code != 1 {
+		t.Fatalf("run(-check) of a missing file = %d, want 1", code)
+	}
+	if !strings.Contains(stderr.String(), output+" is out of date") {
+		t.Errorf("run(-check) reported %q, want %s", stderr.String(), output)
+	}
+	if _, err := os.Stat(output); err == nil {
+		t.Errorf("run(-check) wrote %s", output)
+	}
+
+	stderr.Reset()
+	if code := run(args, &stderr); code != 0 {
+		t.Fatalf("run() = %d: %s", code, stderr.String())
+	}
+	if code := run(check, &stderr); code != 0 {
+		t.Errorf("run(-check) of an up to date file = %d: %s", code, stderr.String())
+	}
+
+	// A stale file is reported and left as it is
+	stale := []byte("package ships\n")
+	if err := os.WriteFile(output, stale, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stderr.Reset()
+	if code := run(check, &stderr); code != 1 {
+		t.Errorf("run(-check) of a stale file = %d, want 1", code)
+	}
+	if !strings.Contains(stderr.String(), output+" is out of date") {
+		t.Errorf("run(-check) reported %q, want %s", stderr.String(), output)
+	}
+	if got, _ := os.ReadFile(output); !bytes.Equal(got, stale) {
+		t.Errorf("run(-check) rewrote %s", output)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	var stderr bytes.Buffer
+	if code := run([]string{"-input", "../../gen/testdata/invalid", "-check"}, &stderr); code != 1 {
+		t.Errorf("run() of unsupported fields = %d, want 1", code)
+	}
+	if !strings.Contains(stderr.String(), "field Speed has unsupported type int") {
+		t.Errorf("run() reported %q, want the diagnostics", stderr.String())
+	}
+	if code := run([]string{"-unknown"}, &stderr); code != 2 {
+		t.Errorf("run() with an unknown flag = %d, want 2", code)
+	}
+}
+
+func TestRun_Types(t *testing.T) {
+	// Each directive regenerates the package's file with all of its entities
+	dir := t.TempDir()
+	var stderr bytes.Buffer
+	for _, name := range []string{"Ship", "Dock"} {
+		if code := run([]string{"-input", "../../gen/testdata/fleet/ships", "-output", dir, "-type", name}, &stderr); code != 0 {
+			t.Fatalf("run(-type %s) = %d: %s", name, code, stderr.String())
+		}
+	}
+	src, err := os.ReadFile(filepath.Join(dir, "ships_deltagen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"type ShipDelta struct", "type DockDelta struct"} {
+		if !bytes.Contains(src, []byte(want)) {
+			t.Errorf("ships_deltagen.go does not contain %q after both runs", want)
+		}
+	}
+}
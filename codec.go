@@ -0,0 +1,103 @@
+package delta
+
+import (
+	"fmt"
+	"sync"
+)
+
+// FieldCodec compares, clones and encodes values of a field type that deltagen
+// does not support natively, such as a vector type from another library.
+// Codecs are registered by name with RegisterCodec and selected with a field
+// tag, e.g. `delta:"codec=vec3"`.
+type FieldCodec[T any] interface {
+	Equal(a, b T) bool
+	Clone(v T) T
+	Encode(bw *BinaryWriter, v T) error
+	Decode(br *BinaryReader) (T, error)
+}
+
+// DeltaMarshaler is implemented by field types that compare, clone and encode
+// themselves. Deltagen uses these methods for fields of a type that declares
+// Equal, Clone, Encode and Decode, with Decode on the pointer receiver.
+type DeltaMarshaler[T any] interface {
+	Equal(other T) bool
+	Clone() T
+	Encode(bw *BinaryWriter) error
+}
+
+// DeltaUnmarshaler decodes a value encoded by DeltaMarshaler.Encode into its
+// receiver
+type DeltaUnmarshaler interface {
+	Decode(br *BinaryReader) error
+}
+
+var codecs = struct {
+	sync.RWMutex
+	byName map[string]any
+}{
+	byName: make(map[string]any),
+}
+
+// RegisterCodec registers c under name for fields tagged with
+// `delta:"codec=name"`. Registering a name twice panics.
+func RegisterCodec[T any](name string, c FieldCodec[T]) {
+	if name == "" {
+		panic("delta: codec name is empty")
+	}
+
+	codecs.Lock()
+	defer codecs.Unlock()
+	if _, ok := codecs.byName[name]; ok {
+		panic(fmt.Sprintf("delta: codec %q registered twice", name))
+	}
+	codecs.byName[name] = c
+}
+
+// MustCodec returns the codec registered under name. It panics if no codec is
+// registered under name or if it encodes a type other than T.
+func MustCodec[T any](name string) FieldCodec[T] {
+	codecs.RLock()
+	c, ok := codecs.byName[name]
+	codecs.RUnlock()
+	if !ok {
+		panic(fmt.Sprintf("delta: codec %q is not registered", name))
+	}
+	fc, ok := c.(FieldCodec[T])
+	if !ok {
+		var zero T
+		panic(fmt.Sprintf("delta: codec %q does not encode %T", name, zero))
+	}
+	return fc
+}
+
+// MarshalerCodec returns a FieldCodec that calls the DeltaMarshaler and
+// DeltaUnmarshaler methods of T
+func MarshalerCodec[T DeltaMarshaler[T], PT interface {
+	*T
+	DeltaUnmarshaler
+}]() FieldCodec[T] {
+	return marshalerCodec[T, PT]{}
+}
+
+type marshalerCodec[T DeltaMarshaler[T], PT interface {
+	*T
+	DeltaUnmarshaler
+}] struct{}
+
+func (marshalerCodec[T, PT]) Equal(a, b T) bool {
+	return a.Equal(b)
+}
+
+func (marshalerCodec[T, PT]) Clone(v T) T {
+	return v.Clone()
+}
+
+func (marshalerCodec[T, PT]) Encode(bw *BinaryWriter, v T) error {
+	return v.Encode(bw)
+}
+
+func (marshalerCodec[T, PT]) Decode(br *BinaryReader) (T, error) {
+	var v T
+	err := PT(&v).Decode(br)
+	return v, err
+}
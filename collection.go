@@ -0,0 +1,276 @@
+package delta
+
+// ElementDelta is implemented by generated deltas, which can report whether
+// they carry any changes
+type ElementDelta interface {
+	Delta
+	IsEmpty() bool
+}
+
+// EntityPtr is satisfied by a pointer to an entity struct E identified by K.
+// It lets collections hold entities by value while calling their generated
+// pointer methods.
+type EntityPtr[E any, K comparable] interface {
+	*E
+	EntityOf[K]
+}
+
+// CollectionDelta describes the changes to a slice or map of entities keyed by
+// K. Added entities are sent in full, as a delta from their zero value, while
+// entities present on both sides are sent as a nested delta only if they
+// changed. For slices the key is the entity ID and Order holds the IDs in their
+// new order whenever membership or order changed; for maps it is the map key
+// and Order is unused.
+type CollectionDelta[K comparable, D ElementDelta] struct {
+	Added   map[K]D
+	Changed map[K]D
+	Removed []K
+	Order   []K
+}
+
+// DiffSlice returns the changes that turn the entities in to into those in
+// from, matching elements by ID, or nil if there are none. IDs must be unique
+// within each slice.
+func DiffSlice[E any, PE EntityPtr[E, K], K comparable, D ElementDelta](from, to []E) *CollectionDelta[K, D] {
+	old := make(map[K]*E, len(to))
+	for i := range to {
+		old[PE(&to[i]).GetID()] = &to[i]
+	}
+
+	cd := &CollectionDelta[K, D]{}
+	sameOrder := len(from) == len(to)
+	for i := range from {
+		id := PE(&from[i]).GetID()
+		if sameOrder && PE(&to[i]).GetID() != id {
+			sameOrder = false
+		}
+		diffElement[E, PE](cd, id, &from[i], old[id])
+		delete(old, id)
+	}
+	for i := range to {
+		if id := PE(&to[i]).GetID(); old[id] != nil {
+			cd.Removed = append(cd.Removed, id)
+		}
+	}
+
+	if !sameOrder {
+		cd.Order = make([]K, len(from))
+		for i := range from {
+			cd.Order[i] = PE(&from[i]).GetID()
+		}
+	}
+	if cd.isEmpty() {
+		return nil
+	}
+	return cd
+}
+
+// ApplySlice applies the changes in cd to the entities in s
+func ApplySlice[E any, PE EntityPtr[E, K], K comparable, D ElementDelta](s *[]E, cd *CollectionDelta[K, D]) {
+	if cd == nil {
+		return
+	}
+
+	index := make(map[K]int, len(*s))
+	for i := range *s {
+		index[PE(&(*s)[i]).GetID()] = i
+	}
+	for id, d := range cd.Changed {
+		if i, ok := index[id]; ok {
+			d.ApplyTo(PE(&(*s)[i]))
+		}
+	}
+	if cd.Order == nil {
+		return
+	}
+
+	result := make([]E, 0, len(cd.Order))
+	for _, id := range cd.Order {
+		if d, ok := cd.Added[id]; ok {
+			var e E
+			d.ApplyTo(PE(&e))
+			result = append(result, e)
+		} else if i, ok := index[id]; ok {
+			result = append(result, (*s)[i])
+		}
+	}
+	*s = result
+}
+
+// DiffMap returns the changes that turn the entities in to into those in from,
+// matching elements by map key, or nil if there are none
+func DiffMap[K comparable, E any, PE EntityPtr[E, ID], ID comparable, D ElementDelta](from, to map[K]E) *CollectionDelta[K, D] {
+	cd := &CollectionDelta[K, D]{}
+	for k, v := range from {
+		if o, ok := to[k]; ok {
+			diffElement[E, PE](cd, k, &v, &o)
+		} else {
+			diffElement[E, PE](cd, k, &v, nil)
+		}
+	}
+	for k := range to {
+		if _, ok := from[k]; !ok {
+			cd.Removed = append(cd.Removed, k)
+		}
+	}
+	sortKeys(cd.Removed)
+	if cd.isEmpty() {
+		return nil
+	}
+	return cd
+}
+
+// ApplyMap applies the changes in cd to the entities in m, allocating the map
+// if entities are added to a nil map
+func ApplyMap[K comparable, E any, PE EntityPtr[E, ID], ID comparable, D ElementDelta](m *map[K]E, cd *CollectionDelta[K, D]) {
+	if cd == nil {
+		return
+	}
+
+	for _, k := range cd.Removed {
+		delete(*m, k)
+	}
+	for k, d := range cd.Changed {
+		if v, ok := (*m)[k]; ok {
+			d.ApplyTo(PE(&v))
+			(*m)[k] = v
+		}
+	}
+	if len(cd.Added) > 0 && *m == nil {
+		*m = make(map[K]E, len(cd.Added))
+	}
+	for k, d := range cd.Added {
+		var v E
+		d.ApplyTo(PE(&v))
+		(*m)[k] = v
+	}
+}
+
+// diffElement records e as added when it has no counterpart o, or its nested
+// delta from o when it changed
+func diffElement[E any, PE EntityPtr[E, ID], ID comparable, K comparable, D ElementDelta](cd *CollectionDelta[K, D], key K, e, o *E) {
+	if o == nil {
+		var zero E
+		if d, ok := PE(e).Delta(PE(&zero)).(D); ok {
+			if cd.Added == nil {
+				cd.Added = make(map[K]D)
+			}
+			cd.Added[key] = d
+		}
+		return
+	}
+
+	if d, ok := PE(e).Delta(PE(o)).(D); ok && !d.IsEmpty() {
+		if cd.Changed == nil {
+			cd.Changed = make(map[K]D)
+		}
+		cd.Changed[key] = d
+	}
+}
+
+func (cd *CollectionDelta[K, D]) isEmpty() bool {
+	return len(cd.Added) == 0 && len(cd.Changed) == 0 && len(cd.Removed) == 0 && cd.Order == nil
+}
+
+// WriteCollection writes cd as the added entries, changed entries and removed
+// keys, each preceded by a varint count, followed by a presence byte and the
+// varint-counted order for slices. Added and changed entries are written in
+// ascending key order, keys with writeKey and nested deltas with their own
+// Serialize.
+func WriteCollection[K comparable, D ElementDelta](bw *BinaryWriter, cd *CollectionDelta[K, D], writeKey func(K) error) error {
+	for _, entries := range []map[K]D{cd.Added, cd.Changed} {
+		if err := bw.WriteVarUint32(uint32(len(entries))); err != nil {
+			return err
+		}
+		for _, k := range SortedKeys(entries) {
+			if err := writeKey(k); err != nil {
+				return err
+			}
+			if err := entries[k].Serialize(bw); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := writeKeys(bw, cd.Removed, writeKey); err != nil {
+		return err
+	}
+	if err := bw.WriteBool(cd.Order != nil); err != nil {
+		return err
+	}
+	if cd.Order != nil {
+		return writeKeys(bw, cd.Order, writeKey)
+	}
+	return nil
+}
+
+// ReadCollection reads a collection delta written by WriteCollection, reading
+// keys with readKey and nested deltas into values returned by newDelta
+func ReadCollection[K comparable, D ElementDelta](br *BinaryReader, readKey func() (K, error), newDelta func() D) (*CollectionDelta[K, D], error) {
+	cd := &CollectionDelta[K, D]{}
+	for _, entries := range []*map[K]D{&cd.Added, &cd.Changed} {
+		length, err := br.ReadVarUint32()
+		if err != nil {
+			return nil, err
+		}
+		if length == 0 {
+			continue
+		}
+		*entries = make(map[K]D)
+		for i := uint32(0); i < length; i++ {
+			k, err := readKey()
+			if err != nil {
+				return nil, err
+			}
+			d := newDelta()
+			if err := d.Deserialize(br); err != nil {
+				return nil, keyDecodeError(k, err)
+			}
+			(*entries)[k] = d
+		}
+	}
+
+	var err error
+	if cd.Removed, err = readKeys(br, readKey); err != nil {
+		return nil, err
+	}
+	hasOrder, err := br.ReadBool()
+	if err != nil {
+		return nil, err
+	}
+	if hasOrder {
+		if cd.Order, err = readKeys(br, readKey); err != nil {
+			return nil, err
+		}
+		if cd.Order == nil {
+			cd.Order = []K{}
+		}
+	}
+	return cd, nil
+}
+
+func writeKeys[K comparable](bw *BinaryWriter, keys []K, writeKey func(K) error) error {
+	if err := bw.WriteVarUint32(uint32(len(keys))); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := writeKey(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readKeys[K comparable](br *BinaryReader, readKey func() (K, error)) ([]K, error) {
+	length, err := br.ReadVarUint32()
+	if err != nil || length == 0 {
+		return nil, err
+	}
+	keys := make([]K, length)
+	for i := range keys {
+		if keys[i], err = readKey(); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,123 @@
+package delta
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrVarintOverflow is returned when a varint does not fit in 32 bits
+	ErrVarintOverflow = errors.New("varint overflow")
+
+	// ErrUnknownFields is returned when a presence mask has bits set beyond
+	// the fields of the entity, in Strict mode or for a mask whose unknown
+	// fields cannot be skipped
+	ErrUnknownFields = errors.New("presence mask has bits set for unknown fields")
+
+	// ErrTrailingBytes is returned by Decode in Strict mode when data holds
+	// bytes after the delta
+	ErrTrailingBytes = errors.New("trailing bytes after delta")
+)
+
+// DecodeMode selects how a BinaryReader treats input that a delta does not
+// account for
+type DecodeMode int
+
+const (
+	// IgnoreTrailing accepts input holding more than the delta: bytes left
+	// after it, and presence bits beyond the fields of the entity whose delta
+	// starts the input, such as those of fields a newer sender appended to
+	// it. Unknown bits in any other mask, that of a nested delta or of a
+	// delta following an envelope, are still ErrUnknownFields, since the
+	// values of their fields cannot be told apart from the data that follows.
+	IgnoreTrailing DecodeMode = iota
+
+	// Strict rejects unknown presence bits with ErrUnknownFields and leftover
+	// bytes with ErrTrailingBytes, so corrupted or mismatched packets fail
+	Strict
+)
+
+func (m DecodeMode) String() string {
+	switch m {
+	case IgnoreTrailing:
+		return "ignore-trailing"
+	case Strict:
+		return "strict"
+	}
+	return fmt.Sprintf("DecodeMode(%d)", int(m))
+}
+
+// Decode deserializes d from data in the given mode. In Strict mode data must
+// hold exactly one delta.
+func Decode(data []byte, d Delta, mode DecodeMode) error {
+	br := NewBinaryReader(bytes.NewReader(data))
+	br.SetMode(mode)
+	if err := d.Deserialize(br); err != nil {
+		return err
+	}
+	if n := int64(len(data)) - br.Offset(); mode == Strict && n > 0 {
+		return &DecodeError{Offset: br.Offset(), Err: fmt.Errorf("%w: %d bytes", ErrTrailingBytes, n)}
+	}
+	return nil
+}
+
+// DecodeError reports where a delta could not be decoded. BinaryReader
+// returns it with only the offset set, and generated Deserialize methods fill
+// in the entity and field. Err is the cause, such as io.ErrUnexpectedEOF for a
+// truncated delta or ErrVarintOverflow.
+type DecodeError struct {
+	Entity string // type of the entity whose delta failed to decode
+	Field  string // path of the field, such as Players[3].Health, or empty for the presence mask
+	Offset int64  // offset of the value that could not be read, from where decoding started
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	switch {
+	case e.Entity == "":
+		return fmt.Sprintf("delta: decoding at offset %d: %v", e.Offset, e.Err)
+	case e.Field == "":
+		return fmt.Sprintf("delta: decoding %s at offset %d: %v", e.Entity, e.Offset, e.Err)
+	}
+	return fmt.Sprintf("delta: decoding %s.%s at offset %d: %v", e.Entity, e.Field, e.Offset, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// WrapDecodeError returns err as a *DecodeError for field of entity. The
+// offset and cause of a *DecodeError from a BinaryReader or nested delta are
+// kept, and the field path of a nested delta is appended to field. Other
+// errors, such as those of codecs, are reported at offset, where the field
+// started.
+func WrapDecodeError(entity, field string, offset int64, err error) error {
+	var de *DecodeError
+	if !errors.As(err, &de) {
+		return &DecodeError{Entity: entity, Field: field, Offset: offset, Err: err}
+	}
+	return &DecodeError{Entity: entity, Field: joinFieldPath(field, de.Field), Offset: de.Offset, Err: de.Err}
+}
+
+// keyDecodeError prefixes the field path of err, a *DecodeError of the nested
+// delta of a collection element, with the element's key
+func keyDecodeError(key any, err error) error {
+	var de *DecodeError
+	if !errors.As(err, &de) {
+		return err
+	}
+	return &DecodeError{Entity: de.Entity, Field: joinFieldPath(fmt.Sprintf("[%v]", key), de.Field), Offset: de.Offset, Err: de.Err}
+}
+
+// joinFieldPath appends the path of a nested field to field
+func joinFieldPath(field, nested string) string {
+	if nested == "" || field == "" {
+		return field + nested
+	}
+	if strings.HasPrefix(nested, "[") {
+		return field + nested
+	}
+	return field + "." + nested
+}
@@ -4,15 +4,41 @@ import (
 	"io"
 )
 
+// Entity is implemented by generated entities. It does not include GetID,
+// whose result has the type of the identity field: use EntityOf or
+// Identifiable to get the ID of an entity.
 type Entity interface {
-	GetID() int64
 	Clone() Entity
 	Delta(other Entity) Delta
 	ApplyDelta(d Delta)
 }
 
+// Identifiable is implemented by entities whose identity field has type ID
+type Identifiable[ID comparable] interface {
+	GetID() ID
+}
+
+// EntityOf is an Entity identified by a value of type ID. Generated entities
+// with the default ID int64 field implement EntityOf[int64].
+type EntityOf[ID comparable] interface {
+	Entity
+	Identifiable[ID]
+}
+
 type Delta interface {
 	ApplyTo(e Entity)
 	Serialize(w io.Writer) error
 	Deserialize(r io.Reader) error
 }
+
+// Diffable is implemented by generated entities, giving access to their deltas
+// without the type assertions of Entity. T is the entity pointer type and D
+// its delta type.
+type Diffable[T any, D Delta] interface {
+	// Diff returns the changes that turn other into the entity. A nil other
+	// is treated as the zero value.
+	Diff(other T) D
+
+	// Apply applies d to the entity. A nil d is ignored.
+	Apply(d D) error
+}
@@ -0,0 +1,145 @@
+package delta
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrUnknownEntity is returned when a delta computed from a baseline targets
+// an entity the receiver does not hold, so it cannot be applied. The receiver
+// needs the full state of the entity, sent with a zero baseline tick.
+var ErrUnknownEntity = errors.New("delta targets an unknown entity")
+
+// Envelope identifies the entity a delta targets and the server ticks it spans.
+// It is written before the delta, since the delta itself only carries the
+// entity ID when it changed. K is the type of the entity's identity field.
+//
+// The envelope is encoded as the type ID as a varint, the entity ID as written
+// by WriteValue, then the tick and the baseline tick as uint64s.
+type Envelope[K Primitive] struct {
+	TypeID       uint32 // type ID the entity's type is registered under
+	EntityID     K      // ID the receiver holds the entity under, its ID at the baseline tick
+	Tick         uint64 // server tick of the state the delta produces
+	BaselineTick uint64 // server tick of the state the delta was computed from, 0 for the zero value
+}
+
+// EntityKey identifies an entity among those of all registered types
+type EntityKey[K comparable] struct {
+	TypeID   uint32
+	EntityID K
+}
+
+// NewEnvelope returns the envelope for a delta of e, whose type must be
+// registered, from the state at baseline to the state at tick. The envelope of
+// a delta that changes the ID of e needs EntityID set to the previous ID.
+func NewEnvelope[K Primitive](e EntityOf[K], tick, baseline uint64) (Envelope[K], error) {
+	id, ok := TypeIDOf(e)
+	if !ok {
+		return Envelope[K]{}, fmt.Errorf("delta: type %T is not registered", e)
+	}
+	return Envelope[K]{TypeID: id, EntityID: e.GetID(), Tick: tick, BaselineTick: baseline}, nil
+}
+
+// Key returns the key of the entity the envelope targets
+func (env Envelope[K]) Key() EntityKey[K] {
+	return EntityKey[K]{TypeID: env.TypeID, EntityID: env.EntityID}
+}
+
+// WriteEnvelope writes env followed by d
+func WriteEnvelope[K Primitive](w io.Writer, env Envelope[K], d Delta) error {
+	bw := NewBinaryWriter(w)
+	if err := bw.WriteVarUint32(env.TypeID); err != nil {
+		return err
+	}
+	if err := WriteValue(bw, env.EntityID); err != nil {
+		return err
+	}
+	if err := bw.WriteUint64(env.Tick); err != nil {
+		return err
+	}
+	if err := bw.WriteUint64(env.BaselineTick); err != nil {
+		return err
+	}
+	return d.Serialize(bw)
+}
+
+// ReadEnvelope reads an envelope written by WriteEnvelope and the delta that
+// follows it, which is decoded with the delta type registered under the
+// envelope's type ID
+func ReadEnvelope[K Primitive](r io.Reader) (Envelope[K], Delta, error) {
+	br := NewBinaryReader(r)
+	var env Envelope[K]
+	var err error
+	if env.TypeID, err = br.ReadVarUint32(); err != nil {
+		return env, nil, err
+	}
+	if env.EntityID, err = ReadValue[K](br); err != nil {
+		return env, nil, err
+	}
+	if env.Tick, err = br.ReadUint64(); err != nil {
+		return env, nil, err
+	}
+	if env.BaselineTick, err = br.ReadUint64(); err != nil {
+		return env, nil, err
+	}
+
+	d, err := NewDelta(env.TypeID)
+	if err != nil {
+		return env, nil, err
+	}
+	if err := d.Deserialize(br); err != nil {
+		return env, nil, err
+	}
+	return env, d, nil
+}
+
+// Route applies d to the entity env targets in entities and returns it. A
+// delta from the zero value, with a zero baseline tick, is a full state: it is
+// applied to a new entity from the registry, which is added to entities or
+// replaces the one held, so fields the sender reset to zero are reset too. A
+// delta from another baseline to an entity that is not in entities returns
+// ErrUnknownEntity. Entities implementing CheckedEntity validate the delta
+// first. An entity whose ID the delta changes moves to the key of its new ID.
+//
+// Route does not compare ticks: callers that may receive deltas out of order
+// should check env.BaselineTick against the tick they last applied.
+func Route[K Primitive](entities map[EntityKey[K]]Entity, env Envelope[K], d Delta) (Entity, error) {
+	key := env.Key()
+	if env.BaselineTick == 0 {
+		e, err := NewEntity(env.TypeID)
+		if err != nil {
+			return nil, err
+		}
+		if err := apply(e, d); err != nil {
+			return nil, err
+		}
+		if ie, ok := e.(Identifiable[K]); ok && ie.GetID() != env.EntityID {
+			return nil, fmt.Errorf("delta: type %d entity %v: %w: delta creates entity %v", env.TypeID, env.EntityID, ErrIDMismatch, ie.GetID())
+		}
+		entities[key] = e
+		return e, nil
+	}
+
+	e, ok := entities[key]
+	if !ok {
+		return nil, fmt.Errorf("delta: type %d entity %v at tick %d: %w", env.TypeID, env.EntityID, env.BaselineTick, ErrUnknownEntity)
+	}
+	if err := apply(e, d); err != nil {
+		return e, err
+	}
+	if ie, ok := e.(Identifiable[K]); ok && ie.GetID() != env.EntityID {
+		delete(entities, key)
+		entities[EntityKey[K]{TypeID: env.TypeID, EntityID: ie.GetID()}] = e
+	}
+	return e, nil
+}
+
+// apply applies d to e, validating it first when e supports it
+func apply(e Entity, d Delta) error {
+	if ce, ok := e.(CheckedEntity); ok {
+		return ce.ApplyDeltaChecked(d)
+	}
+	e.ApplyDelta(d)
+	return nil
+}
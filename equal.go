@@ -25,3 +25,37 @@ func MapsEqual[K, V comparable](a, b map[K]V) bool {
 	}
 	return true
 }
+
+// Equal reports whether a and b are equal, for use as the element comparison
+// of nested collections
+func Equal[T comparable](a, b T) bool {
+	return a == b
+}
+
+// SlicesEqualFunc compares slices whose elements are not comparable, such as
+// nested slices, using eq
+func SlicesEqualFunc[T any](a, b []T, eq func(a, b T) bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !eq(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// MapsEqualFunc compares maps whose values are not comparable, such as nested
+// maps, using eq
+func MapsEqualFunc[K comparable, V any](a, b map[K]V, eq func(a, b V) bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || !eq(v, bv) {
+			return false
+		}
+	}
+	return true
+}
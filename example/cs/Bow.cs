@@ -0,0 +1,74 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+#nullable enable
+
+using System;
+using System.Collections.Generic;
+using System.IO;
+using Delta;
+
+namespace Example
+{
+    public partial class Bow
+    {
+        public long ID;
+        public int Arrows;
+        public float Range;
+
+        public long GetID()
+        {
+            return ID;
+        }
+
+        /// <summary>Registers Bow under type ID 2 so interface fields holding it can be decoded.</summary>
+        public static void RegisterType()
+        {
+            TypeRegistry.Register(2, new RegisteredType(
+                () => new Bow(),
+                r => BowDelta.Deserialize(r),
+                (e, d) => ((BowDelta)d).ApplyTo((Bow)e)));
+        }
+    }
+
+    public partial class BowDelta
+    {
+        public long? ID;
+        public int? Arrows;
+        public float? Range;
+
+        public static BowDelta Deserialize(BinaryReader r)
+        {
+            var d = new BowDelta();
+            var mask = r.ReadUInt64();
+            if (DeltaReader.HasField(mask, 0))
+            {
+                d.ID = r.ReadInt64();
+            }
+            if (DeltaReader.HasField(mask, 1))
+            {
+                d.Arrows = r.ReadInt32();
+            }
+            if (DeltaReader.HasField(mask, 2))
+            {
+                d.Range = r.ReadSingle();
+            }
+            return d;
+        }
+
+        public void ApplyTo(Bow e)
+        {
+            if (ID != null)
+            {
+                e.ID = ID.Value;
+            }
+            if (Arrows != null)
+            {
+                e.Arrows = Arrows.Value;
+            }
+            if (Range != null)
+            {
+                e.Range = Range.Value;
+            }
+        }
+    }
+}
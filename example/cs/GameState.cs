@@ -0,0 +1,205 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+#nullable enable
+
+using System;
+using System.Collections.Generic;
+using System.IO;
+using Delta;
+
+namespace Example
+{
+    public partial class GameState
+    {
+        public long ID;
+        public short Round;
+        public int Score;
+        public sbyte Lives;
+        public ushort MaxHP;
+        public double X;
+        public double Y;
+        public float Speed;
+        public string PlayerName = "";
+        public bool IsActive;
+        public List<string> Inventory = new List<string>();
+        public List<double> Positions = new List<double>();
+        public List<long> PlayerIDs = new List<long>();
+        public byte[] Data = new byte[0];
+        public Dictionary<string, short> PlayerScores = new Dictionary<string, short>();
+        public Dictionary<sbyte, int> ItemCounts = new Dictionary<sbyte, int>();
+        public Dictionary<string, string> Metadata = new Dictionary<string, string>();
+
+        public long GetID()
+        {
+            return ID;
+        }
+    }
+
+    public partial class GameStateDelta
+    {
+        public long? ID;
+        public short? Round;
+        public int? Score;
+        public sbyte? Lives;
+        public ushort? MaxHP;
+        public double? X;
+        public double? Y;
+        public float? Speed;
+        public string? PlayerName;
+        public bool? IsActive;
+        public List<string>? Inventory;
+        public List<double>? Positions;
+        public List<long>? PlayerIDs;
+        public byte[]? Data;
+        public Dictionary<string, short>? PlayerScores;
+        public Dictionary<sbyte, int>? ItemCounts;
+        public Dictionary<string, string>? Metadata;
+
+        public static GameStateDelta Deserialize(BinaryReader r)
+        {
+            var d = new GameStateDelta();
+            var mask = r.ReadUInt64();
+            if (DeltaReader.HasField(mask, 0))
+            {
+                d.ID = r.ReadInt64();
+            }
+            if (DeltaReader.HasField(mask, 1))
+            {
+                d.Round = r.ReadInt16();
+            }
+            if (DeltaReader.HasField(mask, 2))
+            {
+                d.Score = r.ReadInt32();
+            }
+            if (DeltaReader.HasField(mask, 3))
+            {
+                d.Lives = r.ReadSByte();
+            }
+            if (DeltaReader.HasField(mask, 4))
+            {
+                d.MaxHP = r.ReadUInt16();
+            }
+            if (DeltaReader.HasField(mask, 5))
+            {
+                d.X = r.ReadDouble();
+            }
+            if (DeltaReader.HasField(mask, 6))
+            {
+                d.Y = r.ReadDouble();
+            }
+            if (DeltaReader.HasField(mask, 7))
+            {
+                d.Speed = r.ReadSingle();
+            }
+            if (DeltaReader.HasField(mask, 8))
+            {
+                d.PlayerName = r.ReadDeltaString();
+            }
+            if (DeltaReader.HasField(mask, 9))
+            {
+                d.IsActive = r.ReadBoolean();
+            }
+            if (DeltaReader.HasField(mask, 10))
+            {
+                d.Inventory = DeltaReader.ReadSlice(r, r1 => r1.ReadDeltaString());
+            }
+            if (DeltaReader.HasField(mask, 11))
+            {
+                d.Positions = DeltaReader.ReadSlice(r, r1 => r1.ReadDouble());
+            }
+            if (DeltaReader.HasField(mask, 12))
+            {
+                d.PlayerIDs = DeltaReader.ReadSlice(r, r1 => r1.ReadInt64());
+            }
+            if (DeltaReader.HasField(mask, 13))
+            {
+                d.Data = r.ReadDeltaBytes();
+            }
+            if (DeltaReader.HasField(mask, 14))
+            {
+                d.PlayerScores = DeltaReader.ReadMap(r, r1 => r1.ReadDeltaString(), r1 => r1.ReadInt16());
+            }
+            if (DeltaReader.HasField(mask, 15))
+            {
+                d.ItemCounts = DeltaReader.ReadMap(r, r1 => r1.ReadSByte(), r1 => r1.ReadInt32());
+            }
+            if (DeltaReader.HasField(mask, 16))
+            {
+                d.Metadata = DeltaReader.ReadMap(r, r1 => r1.ReadDeltaString(), r1 => r1.ReadDeltaString());
+            }
+            return d;
+        }
+
+        public void ApplyTo(GameState e)
+        {
+            if (ID != null)
+            {
+                e.ID = ID.Value;
+            }
+            if (Round != null)
+            {
+                e.Round = Round.Value;
+            }
+            if (Score != null)
+            {
+                e.Score = Score.Value;
+            }
+            if (Lives != null)
+            {
+                e.Lives = Lives.Value;
+            }
+            if (MaxHP != null)
+            {
+                e.MaxHP = MaxHP.Value;
+            }
+            if (X != null)
+            {
+                e.X = X.Value;
+            }
+            if (Y != null)
+            {
+                e.Y = Y.Value;
+            }
+            if (Speed != null)
+            {
+                e.Speed = Speed.Value;
+            }
+            if (PlayerName != null)
+            {
+                e.PlayerName = PlayerName;
+            }
+            if (IsActive != null)
+            {
+                e.IsActive = IsActive.Value;
+            }
+            if (Inventory != null)
+            {
+                e.Inventory = Inventory;
+            }
+            if (Positions != null)
+            {
+                e.Positions = Positions;
+            }
+            if (PlayerIDs != null)
+            {
+                e.PlayerIDs = PlayerIDs;
+            }
+            if (Data != null)
+            {
+                e.Data = Data;
+            }
+            if (PlayerScores != null)
+            {
+                e.PlayerScores = PlayerScores;
+            }
+            if (ItemCounts != null)
+            {
+                e.ItemCounts = ItemCounts;
+            }
+            if (Metadata != null)
+            {
+                e.Metadata = Metadata;
+            }
+        }
+    }
+}
@@ -0,0 +1,75 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+#nullable enable
+
+using System;
+using System.Collections.Generic;
+using System.IO;
+using Delta;
+
+namespace Inventory
+{
+    public partial class Item
+    {
+        public long ID;
+        public string Name = "";
+        public int Count;
+        public byte Rarity;
+
+        public long GetID()
+        {
+            return ID;
+        }
+    }
+
+    public partial class ItemDelta
+    {
+        public long? ID;
+        public string? Name;
+        public int? Count;
+        public byte? Rarity;
+
+        public static ItemDelta Deserialize(BinaryReader r)
+        {
+            var d = new ItemDelta();
+            var mask = r.ReadUInt64();
+            if (DeltaReader.HasField(mask, 0))
+            {
+                d.ID = r.ReadInt64();
+            }
+            if (DeltaReader.HasField(mask, 1))
+            {
+                d.Name = r.ReadDeltaString();
+            }
+            if (DeltaReader.HasField(mask, 2))
+            {
+                d.Count = r.ReadInt32();
+            }
+            if (DeltaReader.HasField(mask, 3))
+            {
+                d.Rarity = r.ReadByte();
+            }
+            return d;
+        }
+
+        public void ApplyTo(Item e)
+        {
+            if (ID != null)
+            {
+                e.ID = ID.Value;
+            }
+            if (Name != null)
+            {
+                e.Name = Name;
+            }
+            if (Count != null)
+            {
+                e.Count = Count.Value;
+            }
+            if (Rarity != null)
+            {
+                e.Rarity = Rarity.Value;
+            }
+        }
+    }
+}
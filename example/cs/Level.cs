@@ -0,0 +1,95 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+#nullable enable
+
+using System;
+using System.Collections.Generic;
+using System.IO;
+using Delta;
+
+namespace Example
+{
+    public partial class Level
+    {
+        public long ID;
+        public List<List<byte>> Tiles = new List<List<byte>>();
+        public Dictionary<string, List<int>> Spawns = new Dictionary<string, List<int>>();
+        public Dictionary<string, Dictionary<int, ushort>> Loot = new Dictionary<string, Dictionary<int, ushort>>();
+        public List<List<List<float>>> Heights = new List<List<List<float>>>();
+        public List<byte[]> Chunks = new List<byte[]>();
+
+        public long GetID()
+        {
+            return ID;
+        }
+    }
+
+    public partial class LevelDelta
+    {
+        public long? ID;
+        public List<List<byte>>? Tiles;
+        public Dictionary<string, List<int>>? Spawns;
+        public Dictionary<string, Dictionary<int, ushort>>? Loot;
+        public List<List<List<float>>>? Heights;
+        public List<byte[]>? Chunks;
+
+        public static LevelDelta Deserialize(BinaryReader r)
+        {
+            var d = new LevelDelta();
+            var mask = r.ReadUInt64();
+            if (DeltaReader.HasField(mask, 0))
+            {
+                d.ID = r.ReadInt64();
+            }
+            if (DeltaReader.HasField(mask, 1))
+            {
+                d.Tiles = DeltaReader.ReadSlice(r, r1 => DeltaReader.ReadSlice(r1, r2 => r2.ReadByte()));
+            }
+            if (DeltaReader.HasField(mask, 2))
+            {
+                d.Spawns = DeltaReader.ReadMap(r, r1 => r1.ReadDeltaString(), r1 => DeltaReader.ReadSlice(r1, r2 => r2.ReadInt32()));
+            }
+            if (DeltaReader.HasField(mask, 3))
+            {
+                d.Loot = DeltaReader.ReadMap(r, r1 => r1.ReadDeltaString(), r1 => DeltaReader.ReadMap(r1, r2 => r2.ReadInt32(), r2 => r2.ReadUInt16()));
+            }
+            if (DeltaReader.HasField(mask, 4))
+            {
+                d.Heights = DeltaReader.ReadSlice(r, r1 => DeltaReader.ReadSlice(r1, r2 => DeltaReader.ReadSlice(r2, r3 => r3.ReadSingle())));
+            }
+            if (DeltaReader.HasField(mask, 5))
+            {
+                d.Chunks = DeltaReader.ReadSlice(r, r1 => r1.ReadDeltaBytes());
+            }
+            return d;
+        }
+
+        public void ApplyTo(Level e)
+        {
+            if (ID != null)
+            {
+                e.ID = ID.Value;
+            }
+            if (Tiles != null)
+            {
+                e.Tiles = Tiles;
+            }
+            if (Spawns != null)
+            {
+                e.Spawns = Spawns;
+            }
+            if (Loot != null)
+            {
+                e.Loot = Loot;
+            }
+            if (Heights != null)
+            {
+                e.Heights = Heights;
+            }
+            if (Chunks != null)
+            {
+                e.Chunks = Chunks;
+            }
+        }
+    }
+}
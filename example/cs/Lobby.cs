@@ -0,0 +1,85 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+#nullable enable
+
+using System;
+using System.Collections.Generic;
+using System.IO;
+using Delta;
+
+namespace Example
+{
+    public partial class Lobby
+    {
+        public long ID;
+        public string Name = "";
+        public List<Player> Players = new List<Player>();
+        public Dictionary<long, Unit> Units = new Dictionary<long, Unit>();
+        public List<Projectile> Projectiles = new List<Projectile>();
+
+        public long GetID()
+        {
+            return ID;
+        }
+    }
+
+    public partial class LobbyDelta
+    {
+        public long? ID;
+        public string? Name;
+        public CollectionDelta<long, PlayerDelta>? Players;
+        public CollectionDelta<long, UnitDelta>? Units;
+        public CollectionDelta<uint, ProjectileDelta>? Projectiles;
+
+        public static LobbyDelta Deserialize(BinaryReader r)
+        {
+            var d = new LobbyDelta();
+            var mask = r.ReadUInt64();
+            if (DeltaReader.HasField(mask, 0))
+            {
+                d.ID = r.ReadInt64();
+            }
+            if (DeltaReader.HasField(mask, 1))
+            {
+                d.Name = r.ReadDeltaString();
+            }
+            if (DeltaReader.HasField(mask, 2))
+            {
+                d.Players = DeltaReader.ReadCollection(r, r1 => r1.ReadInt64(), PlayerDelta.Deserialize);
+            }
+            if (DeltaReader.HasField(mask, 3))
+            {
+                d.Units = DeltaReader.ReadCollection(r, r1 => r1.ReadInt64(), UnitDelta.Deserialize);
+            }
+            if (DeltaReader.HasField(mask, 4))
+            {
+                d.Projectiles = DeltaReader.ReadCollection(r, r1 => r1.ReadUInt32(), ProjectileDelta.Deserialize);
+            }
+            return d;
+        }
+
+        public void ApplyTo(Lobby e)
+        {
+            if (ID != null)
+            {
+                e.ID = ID.Value;
+            }
+            if (Name != null)
+            {
+                e.Name = Name;
+            }
+            if (Players != null)
+            {
+                e.Players = Players.ApplyTo(e.Players, x => x.GetID(), () => new Player(), (x, xd) => xd.ApplyTo(x));
+            }
+            if (Units != null)
+            {
+                Units.ApplyTo(e.Units, () => new Unit(), (x, xd) => xd.ApplyTo(x));
+            }
+            if (Projectiles != null)
+            {
+                e.Projectiles = Projectiles.ApplyTo(e.Projectiles, x => x.GetID(), () => new Projectile(), (x, xd) => xd.ApplyTo(x));
+            }
+        }
+    }
+}
@@ -0,0 +1,125 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+#nullable enable
+
+using System;
+using System.Collections.Generic;
+using System.IO;
+using Delta;
+
+namespace Example
+{
+    public partial class Player
+    {
+        public long ID;
+        public double X;
+        public double Y;
+        public string Name = "";
+        public int Health;
+        public List<string> Tags = new List<string>();
+        public object? Weapon;
+        public int level;
+        public int Score;
+
+        public long GetID()
+        {
+            return ID;
+        }
+    }
+
+    public partial class PlayerDelta
+    {
+        public long? ID;
+        public double? X;
+        public double? Y;
+        public string? Name;
+        public int? Health;
+        public List<string>? Tags;
+        public InterfaceDelta? Weapon;
+        public int? level;
+        public int? Score;
+
+        public static PlayerDelta Deserialize(BinaryReader r)
+        {
+            var d = new PlayerDelta();
+            var mask = r.ReadUInt64();
+            if (DeltaReader.HasField(mask, 0))
+            {
+                d.ID = r.ReadInt64();
+            }
+            if (DeltaReader.HasField(mask, 1))
+            {
+                d.X = r.ReadDouble();
+            }
+            if (DeltaReader.HasField(mask, 2))
+            {
+                d.Y = r.ReadDouble();
+            }
+            if (DeltaReader.HasField(mask, 3))
+            {
+                d.Name = r.ReadDeltaString();
+            }
+            if (DeltaReader.HasField(mask, 4))
+            {
+                d.Health = r.ReadInt32();
+            }
+            if (DeltaReader.HasField(mask, 5))
+            {
+                d.Tags = DeltaReader.ReadSlice(r, r1 => r1.ReadDeltaString());
+            }
+            if (DeltaReader.HasField(mask, 6))
+            {
+                d.Weapon = InterfaceDelta.Read(r);
+            }
+            if (DeltaReader.HasField(mask, 7))
+            {
+                d.level = r.ReadInt32();
+            }
+            if (DeltaReader.HasField(mask, 8))
+            {
+                d.Score = r.ReadInt32();
+            }
+            return d;
+        }
+
+        public void ApplyTo(Player e)
+        {
+            if (ID != null)
+            {
+                e.ID = ID.Value;
+            }
+            if (X != null)
+            {
+                e.X = X.Value;
+            }
+            if (Y != null)
+            {
+                e.Y = Y.Value;
+            }
+            if (Name != null)
+            {
+                e.Name = Name;
+            }
+            if (Health != null)
+            {
+                e.Health = Health.Value;
+            }
+            if (Tags != null)
+            {
+                e.Tags = Tags;
+            }
+            if (Weapon != null)
+            {
+                e.Weapon = Weapon.ApplyTo(e.Weapon);
+            }
+            if (level != null)
+            {
+                e.level = level.Value;
+            }
+            if (Score != null)
+            {
+                e.Score = Score.Value;
+            }
+        }
+    }
+}
@@ -0,0 +1,75 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+#nullable enable
+
+using System;
+using System.Collections.Generic;
+using System.IO;
+using Delta;
+
+namespace Example
+{
+    public partial class Projectile
+    {
+        public uint Handle;
+        public long OwnerID;
+        public float X;
+        public float Y;
+
+        public uint GetID()
+        {
+            return Handle;
+        }
+    }
+
+    public partial class ProjectileDelta
+    {
+        public uint? Handle;
+        public long? OwnerID;
+        public float? X;
+        public float? Y;
+
+        public static ProjectileDelta Deserialize(BinaryReader r)
+        {
+            var d = new ProjectileDelta();
+            var mask = r.ReadUInt64();
+            if (DeltaReader.HasField(mask, 0))
+            {
+                d.Handle = r.ReadUInt32();
+            }
+            if (DeltaReader.HasField(mask, 1))
+            {
+                d.OwnerID = r.ReadInt64();
+            }
+            if (DeltaReader.HasField(mask, 2))
+            {
+                d.X = r.ReadSingle();
+            }
+            if (DeltaReader.HasField(mask, 3))
+            {
+                d.Y = r.ReadSingle();
+            }
+            return d;
+        }
+
+        public void ApplyTo(Projectile e)
+        {
+            if (Handle != null)
+            {
+                e.Handle = Handle.Value;
+            }
+            if (OwnerID != null)
+            {
+                e.OwnerID = OwnerID.Value;
+            }
+            if (X != null)
+            {
+                e.X = X.Value;
+            }
+            if (Y != null)
+            {
+                e.Y = Y.Value;
+            }
+        }
+    }
+}
@@ -0,0 +1,136 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+#nullable enable
+
+using System;
+using System.Collections.Generic;
+using System.IO;
+using Delta;
+using Inventory;
+
+namespace Example
+{
+    public partial class Stash
+    {
+        public long ID;
+        public long Owner;
+        public List<Item> Items = new List<Item>();
+        public Dictionary<string, Item> Slots = new Dictionary<string, Item>();
+        public byte Best;
+        public int Grade;
+        public Dictionary<byte, int> Grades = new Dictionary<byte, int>();
+        public List<int> History = new List<int>();
+        public HashSet<string> Locks = new HashSet<string>();
+        public long Cooldown;
+
+        public long GetID()
+        {
+            return ID;
+        }
+    }
+
+    public partial class StashDelta
+    {
+        public long? ID;
+        public long? Owner;
+        public CollectionDelta<long, ItemDelta>? Items;
+        public CollectionDelta<string, ItemDelta>? Slots;
+        public byte? Best;
+        public int? Grade;
+        public Dictionary<byte, int>? Grades;
+        public List<int>? History;
+        public SetDelta<string>? Locks;
+        public long? Cooldown;
+
+        public static StashDelta Deserialize(BinaryReader r)
+        {
+            var d = new StashDelta();
+            var mask = r.ReadUInt64();
+            if (DeltaReader.HasField(mask, 0))
+            {
+                d.ID = r.ReadInt64();
+            }
+            if (DeltaReader.HasField(mask, 1))
+            {
+                d.Owner = r.ReadInt64();
+            }
+            if (DeltaReader.HasField(mask, 2))
+            {
+                d.Items = DeltaReader.ReadCollection(r, r1 => r1.ReadInt64(), ItemDelta.Deserialize);
+            }
+            if (DeltaReader.HasField(mask, 3))
+            {
+                d.Slots = DeltaReader.ReadCollection(r, r1 => r1.ReadDeltaString(), ItemDelta.Deserialize);
+            }
+            if (DeltaReader.HasField(mask, 4))
+            {
+                d.Best = r.ReadByte();
+            }
+            if (DeltaReader.HasField(mask, 5))
+            {
+                d.Grade = r.ReadInt32();
+            }
+            if (DeltaReader.HasField(mask, 6))
+            {
+                d.Grades = DeltaReader.ReadMap(r, r1 => r1.ReadByte(), r1 => r1.ReadInt32());
+            }
+            if (DeltaReader.HasField(mask, 7))
+            {
+                d.History = DeltaReader.ReadSlice(r, r1 => r1.ReadInt32());
+            }
+            if (DeltaReader.HasField(mask, 8))
+            {
+                d.Locks = DeltaReader.ReadSet(r, r1 => r1.ReadDeltaString());
+            }
+            if (DeltaReader.HasField(mask, 9))
+            {
+                d.Cooldown = r.ReadInt64();
+            }
+            return d;
+        }
+
+        public void ApplyTo(Stash e)
+        {
+            if (ID != null)
+            {
+                e.ID = ID.Value;
+            }
+            if (Owner != null)
+            {
+                e.Owner = Owner.Value;
+            }
+            if (Items != null)
+            {
+                e.Items = Items.ApplyTo(e.Items, x => x.GetID(), () => new Item(), (x, xd) => xd.ApplyTo(x));
+            }
+            if (Slots != null)
+            {
+                Slots.ApplyTo(e.Slots, () => new Item(), (x, xd) => xd.ApplyTo(x));
+            }
+            if (Best != null)
+            {
+                e.Best = Best.Value;
+            }
+            if (Grade != null)
+            {
+                e.Grade = Grade.Value;
+            }
+            if (Grades != null)
+            {
+                e.Grades = Grades;
+            }
+            if (History != null)
+            {
+                e.History = History;
+            }
+            if (Locks != null)
+            {
+                Locks.ApplyTo(e.Locks);
+            }
+            if (Cooldown != null)
+            {
+                e.Cooldown = Cooldown.Value;
+            }
+        }
+    }
+}
@@ -0,0 +1,105 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+#nullable enable
+
+using System;
+using System.Collections.Generic;
+using System.IO;
+using Delta;
+
+namespace Example
+{
+    public partial class Stats<T> where T : notnull
+    {
+        public long ID;
+        public T Current = default!;
+        public T Max = default!;
+        public List<T> History = new List<T>();
+        public Dictionary<string, T> ByName = new Dictionary<string, T>();
+        public Dictionary<T, int> Counts = new Dictionary<T, int>();
+        public Dictionary<string, int> Bonus = new Dictionary<string, int>();
+
+        public long GetID()
+        {
+            return ID;
+        }
+    }
+
+    public partial class StatsDelta<T> where T : notnull
+    {
+        public long? ID;
+        public Optional<T>? Current;
+        public Optional<T>? Max;
+        public List<T>? History;
+        public Dictionary<string, T>? ByName;
+        public Dictionary<T, int>? Counts;
+        public Dictionary<string, int>? Bonus;
+
+        public static StatsDelta<T> Deserialize(BinaryReader r, Func<BinaryReader, T> readT)
+        {
+            var d = new StatsDelta<T>();
+            var mask = r.ReadUInt64();
+            if (DeltaReader.HasField(mask, 0))
+            {
+                d.ID = r.ReadInt64();
+            }
+            if (DeltaReader.HasField(mask, 1))
+            {
+                d.Current = new Optional<T>(readT(r));
+            }
+            if (DeltaReader.HasField(mask, 2))
+            {
+                d.Max = new Optional<T>(readT(r));
+            }
+            if (DeltaReader.HasField(mask, 3))
+            {
+                d.History = DeltaReader.ReadSlice(r, readT);
+            }
+            if (DeltaReader.HasField(mask, 4))
+            {
+                d.ByName = DeltaReader.ReadMap(r, r1 => r1.ReadDeltaString(), readT);
+            }
+            if (DeltaReader.HasField(mask, 5))
+            {
+                d.Counts = DeltaReader.ReadMap(r, readT, r1 => r1.ReadInt32());
+            }
+            if (DeltaReader.HasField(mask, 6))
+            {
+                d.Bonus = DeltaReader.ReadMap(r, r1 => r1.ReadDeltaString(), r1 => r1.ReadInt32());
+            }
+            return d;
+        }
+
+        public void ApplyTo(Stats<T> e)
+        {
+            if (ID != null)
+            {
+                e.ID = ID.Value;
+            }
+            if (Current != null)
+            {
+                e.Current = Current.Value.Value;
+            }
+            if (Max != null)
+            {
+                e.Max = Max.Value.Value;
+            }
+            if (History != null)
+            {
+                e.History = History;
+            }
+            if (ByName != null)
+            {
+                e.ByName = ByName;
+            }
+            if (Counts != null)
+            {
+                e.Counts = Counts;
+            }
+            if (Bonus != null)
+            {
+                e.Bonus = Bonus;
+            }
+        }
+    }
+}
@@ -0,0 +1,64 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+#nullable enable
+
+using System;
+using System.Collections.Generic;
+using System.IO;
+using Delta;
+
+namespace Example
+{
+    public partial class Sword
+    {
+        public long ID;
+        public int Sharpness;
+
+        public long GetID()
+        {
+            return ID;
+        }
+
+        /// <summary>Registers Sword under type ID 1 so interface fields holding it can be decoded.</summary>
+        public static void RegisterType()
+        {
+            TypeRegistry.Register(1, new RegisteredType(
+                () => new Sword(),
+                r => SwordDelta.Deserialize(r),
+                (e, d) => ((SwordDelta)d).ApplyTo((Sword)e)));
+        }
+    }
+
+    public partial class SwordDelta
+    {
+        public long? ID;
+        public int? Sharpness;
+
+        public static SwordDelta Deserialize(BinaryReader r)
+        {
+            var d = new SwordDelta();
+            var mask = r.ReadUInt64();
+            if (DeltaReader.HasField(mask, 0))
+            {
+                d.ID = r.ReadInt64();
+            }
+            if (DeltaReader.HasField(mask, 1))
+            {
+                d.Sharpness = r.ReadInt32();
+            }
+            return d;
+        }
+
+        public void ApplyTo(Sword e)
+        {
+            if (ID != null)
+            {
+                e.ID = ID.Value;
+            }
+            if (Sharpness != null)
+            {
+                e.Sharpness = Sharpness.Value;
+            }
+        }
+    }
+}
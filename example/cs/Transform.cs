@@ -0,0 +1,75 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+#nullable enable
+
+using System;
+using System.Collections.Generic;
+using System.IO;
+using Delta;
+
+namespace Example
+{
+    public partial class Transform
+    {
+        public long ID;
+        public object? Position;
+        public object? Velocity;
+        public object? Updated;
+
+        public long GetID()
+        {
+            return ID;
+        }
+    }
+
+    public partial class TransformDelta
+    {
+        public long? ID;
+        public object? Position;
+        public object? Velocity;
+        public object? Updated;
+
+        public static TransformDelta Deserialize(BinaryReader r)
+        {
+            var d = new TransformDelta();
+            var mask = r.ReadUInt64();
+            if (DeltaReader.HasField(mask, 0))
+            {
+                d.ID = r.ReadInt64();
+            }
+            if (DeltaReader.HasField(mask, 1))
+            {
+                d.Position = Codecs.Read("Vec3", r);
+            }
+            if (DeltaReader.HasField(mask, 2))
+            {
+                d.Velocity = Codecs.Read("Vec3", r);
+            }
+            if (DeltaReader.HasField(mask, 3))
+            {
+                d.Updated = Codecs.Read("time", r);
+            }
+            return d;
+        }
+
+        public void ApplyTo(Transform e)
+        {
+            if (ID != null)
+            {
+                e.ID = ID.Value;
+            }
+            if (Position != null)
+            {
+                e.Position = Position;
+            }
+            if (Velocity != null)
+            {
+                e.Velocity = Velocity;
+            }
+            if (Updated != null)
+            {
+                e.Updated = Updated;
+            }
+        }
+    }
+}
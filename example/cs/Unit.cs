@@ -0,0 +1,85 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+#nullable enable
+
+using System;
+using System.Collections.Generic;
+using System.IO;
+using Delta;
+
+namespace Example
+{
+    public partial class Unit
+    {
+        public long ID;
+        public string Kind = "";
+        public int HP;
+        public HashSet<string> Buffs = new HashSet<string>();
+        public HashSet<int> Flags = new HashSet<int>();
+
+        public long GetID()
+        {
+            return ID;
+        }
+    }
+
+    public partial class UnitDelta
+    {
+        public long? ID;
+        public string? Kind;
+        public int? HP;
+        public SetDelta<string>? Buffs;
+        public SetDelta<int>? Flags;
+
+        public static UnitDelta Deserialize(BinaryReader r)
+        {
+            var d = new UnitDelta();
+            var mask = r.ReadUInt64();
+            if (DeltaReader.HasField(mask, 0))
+            {
+                d.ID = r.ReadInt64();
+            }
+            if (DeltaReader.HasField(mask, 1))
+            {
+                d.Kind = r.ReadDeltaString();
+            }
+            if (DeltaReader.HasField(mask, 2))
+            {
+                d.HP = r.ReadInt32();
+            }
+            if (DeltaReader.HasField(mask, 3))
+            {
+                d.Buffs = DeltaReader.ReadSet(r, r1 => r1.ReadDeltaString());
+            }
+            if (DeltaReader.HasField(mask, 4))
+            {
+                d.Flags = DeltaReader.ReadSet(r, r1 => r1.ReadInt32());
+            }
+            return d;
+        }
+
+        public void ApplyTo(Unit e)
+        {
+            if (ID != null)
+            {
+                e.ID = ID.Value;
+            }
+            if (Kind != null)
+            {
+                e.Kind = Kind;
+            }
+            if (HP != null)
+            {
+                e.HP = HP.Value;
+            }
+            if (Buffs != null)
+            {
+                Buffs.ApplyTo(e.Buffs);
+            }
+            if (Flags != null)
+            {
+                Flags.ApplyTo(e.Flags);
+            }
+        }
+    }
+}
@@ -0,0 +1,139 @@
+package example
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/cbodonnell/delta"
+)
+
+func TestEnvelope_Route(t *testing.T) {
+	sword := &Sword{ID: 1, Sharpness: 5}
+	bow := &Bow{ID: 1, Arrows: 10, Range: 30}
+
+	// The server sends full states at tick 1, then a change to the sword at
+	// tick 2, all in one stream
+	var stream bytes.Buffer
+	send := func(e delta.EntityOf[int64], d delta.Delta, tick, baseline uint64) {
+		t.Helper()
+		env, err := delta.NewEnvelope(e, tick, baseline)
+		if err != nil {
+			t.Fatalf("NewEnvelope() error: %v", err)
+		}
+		if err := delta.WriteEnvelope(&stream, env, d); err != nil {
+			t.Fatalf("WriteEnvelope() error: %v", err)
+		}
+	}
+	send(sword, sword.Delta(&Sword{}), 1, 0)
+	send(bow, bow.Delta(&Bow{}), 1, 0)
+	sharpened := &Sword{ID: 1, Sharpness: 8}
+	send(sharpened, sharpened.Delta(sword), 2, 1)
+
+	// The client routes each delta to its copy, keyed by type and ID, which
+	// keeps the sword and bow with the same ID apart
+	entities := make(map[delta.EntityKey[int64]]delta.Entity)
+	var last delta.Envelope[int64]
+	for stream.Len() > 0 {
+		env, d, err := delta.ReadEnvelope[int64](&stream)
+		if err != nil {
+			t.Fatalf("ReadEnvelope() error: %v", err)
+		}
+		if _, err := delta.Route(entities, env, d); err != nil {
+			t.Fatalf("Route() error: %v", err)
+		}
+		last = env
+	}
+
+	if len(entities) != 2 {
+		t.Fatalf("Route() created %d entities, want 2", len(entities))
+	}
+	if got := entities[delta.EntityKey[int64]{TypeID: 1, EntityID: 1}]; !reflect.DeepEqual(got, sharpened) {
+		t.Errorf("sword = %+v, want %+v", got, sharpened)
+	}
+	if got := entities[delta.EntityKey[int64]{TypeID: 2, EntityID: 1}]; !reflect.DeepEqual(got, bow) {
+		t.Errorf("bow = %+v, want %+v", got, bow)
+	}
+	if want := (delta.Envelope[int64]{TypeID: 1, EntityID: 1, Tick: 2, BaselineTick: 1}); last != want {
+		t.Errorf("last envelope = %+v, want %+v", last, want)
+	}
+
+	// A change to an entity the client never received cannot be applied
+	unknown := delta.Envelope[int64]{TypeID: 1, EntityID: 9, Tick: 2, BaselineTick: 1}
+	if _, err := delta.Route(entities, unknown, (&Sword{ID: 9, Sharpness: 1}).Delta(&Sword{ID: 9})); !errors.Is(err, delta.ErrUnknownEntity) {
+		t.Errorf("Route() of an unknown entity error = %v, want ErrUnknownEntity", err)
+	}
+
+	// A full state carrying another ID than its envelope is rejected
+	created := delta.Envelope[int64]{TypeID: 1, EntityID: 3, Tick: 3}
+	if _, err := delta.Route(entities, created, (&Sword{ID: 4}).Delta(&Sword{})); !errors.Is(err, delta.ErrIDMismatch) {
+		t.Errorf("Route() of a misrouted full state error = %v, want ErrIDMismatch", err)
+	}
+	if len(entities) != 2 {
+		t.Errorf("Route() added entities for rejected deltas: %v", entities)
+	}
+
+	// A delta changing the ID moves the entity to its new key; its envelope
+	// carries the previous ID
+	renamed := &Sword{ID: 7, Sharpness: 8}
+	env, err := delta.NewEnvelope(renamed, 3, 2)
+	if err != nil {
+		t.Fatalf("NewEnvelope() error: %v", err)
+	}
+	env.EntityID = sharpened.ID
+	if _, err := delta.Route(entities, env, renamed.Delta(sharpened)); err != nil {
+		t.Fatalf("Route() of an ID change error: %v", err)
+	}
+	if got := entities[delta.EntityKey[int64]{TypeID: 1, EntityID: 7}]; !reflect.DeepEqual(got, renamed) {
+		t.Errorf("renamed sword = %+v, want %+v", got, renamed)
+	}
+	if _, ok := entities[delta.EntityKey[int64]{TypeID: 1, EntityID: 1}]; ok || len(entities) != 2 {
+		t.Errorf("Route() kept the sword under its previous ID: %v", entities)
+	}
+
+	// A full state resent to recover replaces the entity held, resetting
+	// fields that went back to zero
+	blunt := &Sword{ID: 7}
+	env, err = delta.NewEnvelope(blunt, 4, 0)
+	if err != nil {
+		t.Fatalf("NewEnvelope() error: %v", err)
+	}
+	if _, err := delta.Route(entities, env, blunt.Delta(&Sword{})); err != nil {
+		t.Fatalf("Route() of a resent full state error: %v", err)
+	}
+	if got := entities[delta.EntityKey[int64]{TypeID: 1, EntityID: 7}]; !reflect.DeepEqual(got, blunt) {
+		t.Errorf("resent sword = %+v, want %+v", got, blunt)
+	}
+
+	if _, err := delta.NewEnvelope[int64](&Player{}, 1, 0); err == nil {
+		t.Errorf("NewEnvelope() of an unregistered type did not fail")
+	}
+}
+
+func TestEnvelope_UnknownFields(t *testing.T) {
+	sword := &Sword{ID: 1, Sharpness: 5}
+	env, err := delta.NewEnvelope(sword, 1, 0)
+	if err != nil {
+		t.Fatalf("NewEnvelope() error: %v", err)
+	}
+	var stream bytes.Buffer
+	for range 2 {
+		if err := delta.WriteEnvelope(&stream, env, sword.Delta(&Sword{})); err != nil {
+			t.Fatalf("WriteEnvelope() error: %v", err)
+		}
+	}
+
+	// The sword's mask follows the type ID, entity ID and ticks. Its unknown
+	// field cannot be skipped, so the next envelope could not be found.
+	const swordMask = 1 + 8 + 8 + 8
+	data := stream.Bytes()
+	data[swordMask+7] |= 0x80
+	for _, mode := range []delta.DecodeMode{delta.IgnoreTrailing, delta.Strict} {
+		br := delta.NewBinaryReader(bytes.NewReader(data))
+		br.SetMode(mode)
+		if _, _, err := delta.ReadEnvelope[int64](br); !errors.Is(err, delta.ErrUnknownFields) {
+			t.Errorf("ReadEnvelope() in %v mode error = %v, want ErrUnknownFields", mode, err)
+		}
+	}
+}
@@ -0,0 +1,3763 @@
+// Code generated by deltagen. DO NOT EDIT.
+package example
+
+import (
+	"io"
+	"time"
+
+	"github.com/cbodonnell/delta"
+	"github.com/cbodonnell/delta/example/inventory"
+)
+
+var (
+	_ delta.EntityOf[int64]                       = (*GameState)(nil)
+	_ delta.Diffable[*GameState, *GameStateDelta] = (*GameState)(nil)
+	_ delta.CheckedEntity                         = (*GameState)(nil)
+)
+
+func (e *GameState) GetID() int64 {
+	return e.ID
+}
+
+func (e *GameState) Clone() delta.Entity {
+	cp := *e
+	if e.Inventory != nil {
+		cp.Inventory = make([]string, len(e.Inventory))
+		copy(cp.Inventory, e.Inventory)
+	}
+	if e.Positions != nil {
+		cp.Positions = make([]float64, len(e.Positions))
+		copy(cp.Positions, e.Positions)
+	}
+	if e.PlayerIDs != nil {
+		cp.PlayerIDs = make([]int64, len(e.PlayerIDs))
+		copy(cp.PlayerIDs, e.PlayerIDs)
+	}
+	if e.Data != nil {
+		cp.Data = make([]byte, len(e.Data))
+		copy(cp.Data, e.Data)
+	}
+	if e.PlayerScores != nil {
+		cp.PlayerScores = make(map[string]int16)
+		for k, v := range e.PlayerScores {
+			cp.PlayerScores[k] = v
+		}
+	}
+	if e.ItemCounts != nil {
+		cp.ItemCounts = make(map[int8]int32)
+		for k, v := range e.ItemCounts {
+			cp.ItemCounts[k] = v
+		}
+	}
+	if e.Metadata != nil {
+		cp.Metadata = make(map[string]string)
+		for k, v := range e.Metadata {
+			cp.Metadata[k] = v
+		}
+	}
+	return &cp
+}
+
+func (e *GameState) Delta(o delta.Entity) delta.Delta {
+	if o == nil {
+		return nil
+	}
+	other, ok := o.(*GameState)
+	if !ok {
+		return nil // or panic
+	}
+	return e.Diff(other)
+}
+
+// Diff returns the changes that turn other into e, or all of e when other is
+// nil
+func (e *GameState) Diff(other *GameState) *GameStateDelta {
+	if other == nil {
+		other = &GameState{}
+	}
+	d := &GameStateDelta{}
+	if e.ID != other.ID {
+		v := e.ID
+		d.ID = &v
+	}
+	if e.Round != other.Round {
+		v := e.Round
+		d.Round = &v
+	}
+	if e.Score != other.Score {
+		v := e.Score
+		d.Score = &v
+	}
+	if e.Lives != other.Lives {
+		v := e.Lives
+		d.Lives = &v
+	}
+	if e.MaxHP != other.MaxHP {
+		v := e.MaxHP
+		d.MaxHP = &v
+	}
+	if e.X != other.X {
+		v := e.X
+		d.X = &v
+	}
+	if e.Y != other.Y {
+		v := e.Y
+		d.Y = &v
+	}
+	if e.Speed != other.Speed {
+		v := e.Speed
+		d.Speed = &v
+	}
+	if e.PlayerName != other.PlayerName {
+		v := e.PlayerName
+		d.PlayerName = &v
+	}
+	if e.IsActive != other.IsActive {
+		v := e.IsActive
+		d.IsActive = &v
+	}
+	if !delta.SlicesEqual(e.Inventory, other.Inventory) {
+		if e.Inventory != nil {
+			v := make([]string, len(e.Inventory))
+			copy(v, e.Inventory)
+			d.Inventory = &v
+		} else {
+			d.Inventory = &[]string{}
+		}
+	}
+	if !delta.SlicesEqual(e.Positions, other.Positions) {
+		if e.Positions != nil {
+			v := make([]float64, len(e.Positions))
+			copy(v, e.Positions)
+			d.Positions = &v
+		} else {
+			d.Positions = &[]float64{}
+		}
+	}
+	if !delta.SlicesEqual(e.PlayerIDs, other.PlayerIDs) {
+		if e.PlayerIDs != nil {
+			v := make([]int64, len(e.PlayerIDs))
+			copy(v, e.PlayerIDs)
+			d.PlayerIDs = &v
+		} else {
+			d.PlayerIDs = &[]int64{}
+		}
+	}
+	if !delta.SlicesEqual(e.Data, other.Data) {
+		if e.Data != nil {
+			v := make([]byte, len(e.Data))
+			copy(v, e.Data)
+			d.Data = &v
+		} else {
+			d.Data = &[]byte{}
+		}
+	}
+	if !delta.MapsEqual(e.PlayerScores, other.PlayerScores) {
+		if e.PlayerScores != nil {
+			v := make(map[string]int16)
+			for k, val := range e.PlayerScores {
+				v[k] = val
+			}
+			d.PlayerScores = &v
+		} else {
+			v := make(map[string]int16)
+			d.PlayerScores = &v
+		}
+	}
+	if !delta.MapsEqual(e.ItemCounts, other.ItemCounts) {
+		if e.ItemCounts != nil {
+			v := make(map[int8]int32)
+			for k, val := range e.ItemCounts {
+				v[k] = val
+			}
+			d.ItemCounts = &v
+		} else {
+			v := make(map[int8]int32)
+			d.ItemCounts = &v
+		}
+	}
+	if !delta.MapsEqual(e.Metadata, other.Metadata) {
+		if e.Metadata != nil {
+			v := make(map[string]string)
+			for k, val := range e.Metadata {
+				v[k] = val
+			}
+			d.Metadata = &v
+		} else {
+			v := make(map[string]string)
+			d.Metadata = &v
+		}
+	}
+	return d
+}
+
+func (e *GameState) ApplyDelta(d delta.Delta) {
+	if d == nil {
+		return
+	}
+	dt, ok := d.(*GameStateDelta)
+	if !ok {
+		return // or panic
+	}
+	dt.ApplyTo(e)
+}
+
+// ApplyDeltaChecked is like ApplyDelta, but reports a delta of another type or
+// one that fails validation instead of ignoring it
+func (e *GameState) ApplyDeltaChecked(d delta.Delta) error {
+	if d == nil {
+		return nil
+	}
+	dt, ok := d.(*GameStateDelta)
+	if !ok {
+		return delta.TypeMismatch("GameState", d)
+	}
+	return dt.ApplyToChecked(e)
+}
+
+// Apply applies d to e if it passes validation
+func (e *GameState) Apply(d *GameStateDelta) error {
+	if d == nil {
+		return nil
+	}
+	return d.ApplyToChecked(e)
+}
+
+var _ delta.ValidatingDelta = (*GameStateDelta)(nil)
+
+type GameStateDelta struct {
+	ID           *int64
+	Round        *int16
+	Score        *int32
+	Lives        *int8
+	MaxHP        *uint16
+	X            *float64
+	Y            *float64
+	Speed        *float32
+	PlayerName   *string
+	IsActive     *bool
+	Inventory    *[]string
+	Positions    *[]float64
+	PlayerIDs    *[]int64
+	Data         *[]byte
+	PlayerScores *map[string]int16
+	ItemCounts   *map[int8]int32
+	Metadata     *map[string]string
+}
+
+// IsEmpty returns true if the delta carries no changes
+func (d *GameStateDelta) IsEmpty() bool {
+	return d.ID == nil && d.Round == nil && d.Score == nil && d.Lives == nil && d.MaxHP == nil && d.X == nil && d.Y == nil && d.Speed == nil && d.PlayerName == nil && d.IsActive == nil && d.Inventory == nil && d.Positions == nil && d.PlayerIDs == nil && d.Data == nil && d.PlayerScores == nil && d.ItemCounts == nil && d.Metadata == nil
+}
+
+// Validate returns a *delta.ApplyError if d cannot be applied to e, because e
+// has another type or a field would leave the bounds of its tags, without
+// changing e. Deltas do not carry the ID of the entity they were computed for,
+// so a full state applied to another entity is only reported by delta.Route.
+func (d *GameStateDelta) Validate(e delta.Entity) error {
+	_, ok := e.(*GameState)
+	if !ok {
+		return delta.TypeMismatch("GameState", e)
+	}
+	return nil
+}
+
+// ApplyToChecked applies d to e only if it passes Validate, so either all of d
+// is applied or none of it
+func (d *GameStateDelta) ApplyToChecked(e delta.Entity) error {
+	if err := d.Validate(e); err != nil {
+		return err
+	}
+	d.ApplyTo(e)
+	return nil
+}
+
+func (d *GameStateDelta) ApplyTo(e delta.Entity) {
+	et, ok := e.(*GameState)
+	if !ok {
+		return // or panic
+	}
+	if d.ID != nil {
+		et.ID = *d.ID
+	}
+	if d.Round != nil {
+		et.Round = *d.Round
+	}
+	if d.Score != nil {
+		et.Score = *d.Score
+	}
+	if d.Lives != nil {
+		et.Lives = *d.Lives
+	}
+	if d.MaxHP != nil {
+		et.MaxHP = *d.MaxHP
+	}
+	if d.X != nil {
+		et.X = *d.X
+	}
+	if d.Y != nil {
+		et.Y = *d.Y
+	}
+	if d.Speed != nil {
+		et.Speed = *d.Speed
+	}
+	if d.PlayerName != nil {
+		et.PlayerName = *d.PlayerName
+	}
+	if d.IsActive != nil {
+		et.IsActive = *d.IsActive
+	}
+	if d.Inventory != nil {
+		if *d.Inventory != nil {
+			et.Inventory = make([]string, len(*d.Inventory))
+			copy(et.Inventory, *d.Inventory)
+		} else {
+			et.Inventory = nil
+		}
+	}
+	if d.Positions != nil {
+		if *d.Positions != nil {
+			et.Positions = make([]float64, len(*d.Positions))
+			copy(et.Positions, *d.Positions)
+		} else {
+			et.Positions = nil
+		}
+	}
+	if d.PlayerIDs != nil {
+		if *d.PlayerIDs != nil {
+			et.PlayerIDs = make([]int64, len(*d.PlayerIDs))
+			copy(et.PlayerIDs, *d.PlayerIDs)
+		} else {
+			et.PlayerIDs = nil
+		}
+	}
+	if d.Data != nil {
+		if *d.Data != nil {
+			et.Data = make([]byte, len(*d.Data))
+			copy(et.Data, *d.Data)
+		} else {
+			et.Data = nil
+		}
+	}
+	if d.PlayerScores != nil {
+		if *d.PlayerScores != nil {
+			et.PlayerScores = make(map[string]int16)
+			for k, v := range *d.PlayerScores {
+				et.PlayerScores[k] = v
+			}
+		} else {
+			et.PlayerScores = nil
+		}
+	}
+	if d.ItemCounts != nil {
+		if *d.ItemCounts != nil {
+			et.ItemCounts = make(map[int8]int32)
+			for k, v := range *d.ItemCounts {
+				et.ItemCounts[k] = v
+			}
+		} else {
+			et.ItemCounts = nil
+		}
+	}
+	if d.Metadata != nil {
+		if *d.Metadata != nil {
+			et.Metadata = make(map[string]string)
+			for k, v := range *d.Metadata {
+				et.Metadata[k] = v
+			}
+		} else {
+			et.Metadata = nil
+		}
+	}
+}
+
+func (d *GameStateDelta) Serialize(w io.Writer) error {
+	bw := delta.NewBinaryWriter(w)
+
+	// Write field presence bitmask
+	var fieldMask uint64
+	if d.ID != nil {
+		fieldMask |= 1 << 0
+	}
+	if d.Round != nil {
+		fieldMask |= 1 << 1
+	}
+	if d.Score != nil {
+		fieldMask |= 1 << 2
+	}
+	if d.Lives != nil {
+		fieldMask |= 1 << 3
+	}
+	if d.MaxHP != nil {
+		fieldMask |= 1 << 4
+	}
+	if d.X != nil {
+		fieldMask |= 1 << 5
+	}
+	if d.Y != nil {
+		fieldMask |= 1 << 6
+	}
+	if d.Speed != nil {
+		fieldMask |= 1 << 7
+	}
+	if d.PlayerName != nil {
+		fieldMask |= 1 << 8
+	}
+	if d.IsActive != nil {
+		fieldMask |= 1 << 9
+	}
+	if d.Inventory != nil {
+		fieldMask |= 1 << 10
+	}
+	if d.Positions != nil {
+		fieldMask |= 1 << 11
+	}
+	if d.PlayerIDs != nil {
+		fieldMask |= 1 << 12
+	}
+	if d.Data != nil {
+		fieldMask |= 1 << 13
+	}
+	if d.PlayerScores != nil {
+		fieldMask |= 1 << 14
+	}
+	if d.ItemCounts != nil {
+		fieldMask |= 1 << 15
+	}
+	if d.Metadata != nil {
+		fieldMask |= 1 << 16
+	}
+	if err := bw.WriteUint64(fieldMask); err != nil {
+		return err
+	}
+
+	// Write field values for present fields
+	if d.ID != nil {
+		// Serialize primitive
+		if err := bw.WriteInt64(*d.ID); err != nil {
+			return err
+		}
+	}
+	if d.Round != nil {
+		// Serialize primitive
+		if err := bw.WriteInt16(*d.Round); err != nil {
+			return err
+		}
+	}
+	if d.Score != nil {
+		// Serialize primitive
+		if err := bw.WriteInt32(*d.Score); err != nil {
+			return err
+		}
+	}
+	if d.Lives != nil {
+		// Serialize primitive
+		if err := bw.WriteInt8(*d.Lives); err != nil {
+			return err
+		}
+	}
+	if d.MaxHP != nil {
+		// Serialize primitive
+		if err := bw.WriteUint16(*d.MaxHP); err != nil {
+			return err
+		}
+	}
+	if d.X != nil {
+		// Serialize primitive
+		if err := bw.WriteFloat64(*d.X); err != nil {
+			return err
+		}
+	}
+	if d.Y != nil {
+		// Serialize primitive
+		if err := bw.WriteFloat64(*d.Y); err != nil {
+			return err
+		}
+	}
+	if d.Speed != nil {
+		// Serialize primitive
+		if err := bw.WriteFloat32(*d.Speed); err != nil {
+			return err
+		}
+	}
+	if d.PlayerName != nil {
+		// Serialize primitive
+		if err := bw.WriteString(*d.PlayerName); err != nil {
+			return err
+		}
+	}
+	if d.IsActive != nil {
+		// Serialize primitive
+		if err := bw.WriteBool(*d.IsActive); err != nil {
+			return err
+		}
+	}
+	if d.Inventory != nil {
+		// Serialize slice
+		if err := bw.WriteVarUint32(uint32(len(*d.Inventory))); err != nil {
+			return err
+		}
+		for _, item := range *d.Inventory {
+			if err := bw.WriteString(item); err != nil {
+				return err
+			}
+		}
+	}
+	if d.Positions != nil {
+		// Serialize slice
+		if err := bw.WriteVarUint32(uint32(len(*d.Positions))); err != nil {
+			return err
+		}
+		for _, item := range *d.Positions {
+			if err := bw.WriteFloat64(item); err != nil {
+				return err
+			}
+		}
+	}
+	if d.PlayerIDs != nil {
+		// Serialize slice
+		if err := bw.WriteVarUint32(uint32(len(*d.PlayerIDs))); err != nil {
+			return err
+		}
+		for _, item := range *d.PlayerIDs {
+			if err := bw.WriteInt64(item); err != nil {
+				return err
+			}
+		}
+	}
+	if d.Data != nil {
+		// Serialize slice
+		if err := bw.WriteVarUint32(uint32(len(*d.Data))); err != nil {
+			return err
+		}
+		for _, item := range *d.Data {
+			if err := bw.WriteUint8(item); err != nil {
+				return err
+			}
+		}
+	}
+	if d.PlayerScores != nil {
+		// Serialize map
+		if err := bw.WriteVarUint32(uint32(len(*d.PlayerScores))); err != nil {
+			return err
+		}
+		for _, k := range delta.SortedKeys(*d.PlayerScores) {
+			v := (*d.PlayerScores)[k]
+			if err := bw.WriteString(k); err != nil {
+				return err
+			}
+			if err := bw.WriteInt16(v); err != nil {
+				return err
+			}
+		}
+	}
+	if d.ItemCounts != nil {
+		// Serialize map
+		if err := bw.WriteVarUint32(uint32(len(*d.ItemCounts))); err != nil {
+			return err
+		}
+		for _, k := range delta.SortedKeys(*d.ItemCounts) {
+			v := (*d.ItemCounts)[k]
+			if err := bw.WriteInt8(k); err != nil {
+				return err
+			}
+			if err := bw.WriteInt32(v); err != nil {
+				return err
+			}
+		}
+	}
+	if d.Metadata != nil {
+		// Serialize map
+		if err := bw.WriteVarUint32(uint32(len(*d.Metadata))); err != nil {
+			return err
+		}
+		for _, k := range delta.SortedKeys(*d.Metadata) {
+			v := (*d.Metadata)[k]
+			if err := bw.WriteString(k); err != nil {
+				return err
+			}
+			if err := bw.WriteString(v); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (d *GameStateDelta) Deserialize(r io.Reader) error {
+	br := delta.NewBinaryReader(r)
+
+	// Read field presence bitmask
+	fieldMask, err := br.ReadFieldMask(17)
+	if err != nil {
+		return delta.WrapDecodeError("GameState", "", br.Offset(), err)
+	}
+
+	// Read field values for present fields
+	if fieldMask&(1<<0) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt64()
+		if err != nil {
+			return delta.WrapDecodeError("GameState", "ID", offset, err)
+		}
+		d.ID = &val
+	}
+	if fieldMask&(1<<1) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt16()
+		if err != nil {
+			return delta.WrapDecodeError("GameState", "Round", offset, err)
+		}
+		d.Round = &val
+	}
+	if fieldMask&(1<<2) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt32()
+		if err != nil {
+			return delta.WrapDecodeError("GameState", "Score", offset, err)
+		}
+		d.Score = &val
+	}
+	if fieldMask&(1<<3) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt8()
+		if err != nil {
+			return delta.WrapDecodeError("GameState", "Lives", offset, err)
+		}
+		d.Lives = &val
+	}
+	if fieldMask&(1<<4) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadUint16()
+		if err != nil {
+			return delta.WrapDecodeError("GameState", "MaxHP", offset, err)
+		}
+		d.MaxHP = &val
+	}
+	if fieldMask&(1<<5) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadFloat64()
+		if err != nil {
+			return delta.WrapDecodeError("GameState", "X", offset, err)
+		}
+		d.X = &val
+	}
+	if fieldMask&(1<<6) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadFloat64()
+		if err != nil {
+			return delta.WrapDecodeError("GameState", "Y", offset, err)
+		}
+		d.Y = &val
+	}
+	if fieldMask&(1<<7) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadFloat32()
+		if err != nil {
+			return delta.WrapDecodeError("GameState", "Speed", offset, err)
+		}
+		d.Speed = &val
+	}
+	if fieldMask&(1<<8) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadString()
+		if err != nil {
+			return delta.WrapDecodeError("GameState", "PlayerName", offset, err)
+		}
+		d.PlayerName = &val
+	}
+	if fieldMask&(1<<9) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadBool()
+		if err != nil {
+			return delta.WrapDecodeError("GameState", "IsActive", offset, err)
+		}
+		d.IsActive = &val
+	}
+	if fieldMask&(1<<10) != 0 {
+		offset := br.Offset()
+		// Deserialize slice
+		length, err := br.ReadVarUint32()
+		if err != nil {
+			return delta.WrapDecodeError("GameState", "Inventory", offset, err)
+		}
+		slice := make([]string, length)
+		for i := range slice {
+			item, err := br.ReadString()
+			if err != nil {
+				return delta.WrapDecodeError("GameState", "Inventory", offset, err)
+			}
+			slice[i] = item
+		}
+		d.Inventory = &slice
+	}
+	if fieldMask&(1<<11) != 0 {
+		offset := br.Offset()
+		// Deserialize slice
+		length, err := br.ReadVarUint32()
+		if err != nil {
+			return delta.WrapDecodeError("GameState", "Positions", offset, err)
+		}
+		slice := make([]float64, length)
+		for i := range slice {
+			item, err := br.ReadFloat64()
+			if err != nil {
+				return delta.WrapDecodeError("GameState", "Positions", offset, err)
+			}
+			slice[i] = item
+		}
+		d.Positions = &slice
+	}
+	if fieldMask&(1<<12) != 0 {
+		offset := br.Offset()
+		// Deserialize slice
+		length, err := br.ReadVarUint32()
+		if err != nil {
+			return delta.WrapDecodeError("GameState", "PlayerIDs", offset, err)
+		}
+		slice := make([]int64, length)
+		for i := range slice {
+			item, err := br.ReadInt64()
+			if err != nil {
+				return delta.WrapDecodeError("GameState", "PlayerIDs", offset, err)
+			}
+			slice[i] = item
+		}
+		d.PlayerIDs = &slice
+	}
+	if fieldMask&(1<<13) != 0 {
+		offset := br.Offset()
+		// Deserialize slice
+		length, err := br.ReadVarUint32()
+		if err != nil {
+			return delta.WrapDecodeError("GameState", "Data", offset, err)
+		}
+		slice := make([]byte, length)
+		for i := range slice {
+			item, err := br.ReadUint8()
+			if err != nil {
+				return delta.WrapDecodeError("GameState", "Data", offset, err)
+			}
+			slice[i] = item
+		}
+		d.Data = &slice
+	}
+	if fieldMask&(1<<14) != 0 {
+		offset := br.Offset()
+		// Deserialize map
+		length, err := br.ReadVarUint32()
+		if err != nil {
+			return delta.WrapDecodeError("GameState", "PlayerScores", offset, err)
+		}
+		m := make(map[string]int16)
+		for i := uint32(0); i < length; i++ {
+			k, err := br.ReadString()
+			if err != nil {
+				return delta.WrapDecodeError("GameState", "PlayerScores", offset, err)
+			}
+			v, err := br.ReadInt16()
+			if err != nil {
+				return delta.WrapDecodeError("GameState", "PlayerScores", offset, err)
+			}
+			m[k] = v
+		}
+		d.PlayerScores = &m
+	}
+	if fieldMask&(1<<15) != 0 {
+		offset := br.Offset()
+		// Deserialize map
+		length, err := br.ReadVarUint32()
+		if err != nil {
+			return delta.WrapDecodeError("GameState", "ItemCounts", offset, err)
+		}
+		m := make(map[int8]int32)
+		for i := uint32(0); i < length; i++ {
+			k, err := br.ReadInt8()
+			if err != nil {
+				return delta.WrapDecodeError("GameState", "ItemCounts", offset, err)
+			}
+			v, err := br.ReadInt32()
+			if err != nil {
+				return delta.WrapDecodeError("GameState", "ItemCounts", offset, err)
+			}
+			m[k] = v
+		}
+		d.ItemCounts = &m
+	}
+	if fieldMask&(1<<16) != 0 {
+		offset := br.Offset()
+		// Deserialize map
+		length, err := br.ReadVarUint32()
+		if err != nil {
+			return delta.WrapDecodeError("GameState", "Metadata", offset, err)
+		}
+		m := make(map[string]string)
+		for i := uint32(0); i < length; i++ {
+			k, err := br.ReadString()
+			if err != nil {
+				return delta.WrapDecodeError("GameState", "Metadata", offset, err)
+			}
+			v, err := br.ReadString()
+			if err != nil {
+				return delta.WrapDecodeError("GameState", "Metadata", offset, err)
+			}
+			m[k] = v
+		}
+		d.Metadata = &m
+	}
+
+	return nil
+}
+
+var (
+	_ delta.EntityOf[int64]               = (*Level)(nil)
+	_ delta.Diffable[*Level, *LevelDelta] = (*Level)(nil)
+	_ delta.CheckedEntity                 = (*Level)(nil)
+)
+
+func (e *Level) GetID() int64 {
+	return e.ID
+}
+
+func (e *Level) Clone() delta.Entity {
+	cp := *e
+	cp.Tiles = delta.CloneSliceFunc(e.Tiles, delta.CloneSlice[uint8])
+	cp.Spawns = delta.CloneMapFunc(e.Spawns, delta.CloneSlice[int32])
+	cp.Loot = delta.CloneMapFunc(e.Loot, delta.CloneMap[int32, uint16])
+	cp.Heights = delta.CloneSliceFunc(e.Heights, func(v [][]float32) [][]float32 { return delta.CloneSliceFunc(v, delta.CloneSlice[float32]) })
+	cp.Chunks = delta.CloneSliceFunc(e.Chunks, delta.CloneSlice[byte])
+	return &cp
+}
+
+func (e *Level) Delta(o delta.Entity) delta.Delta {
+	if o == nil {
+		return nil
+	}
+	other, ok := o.(*Level)
+	if !ok {
+		return nil // or panic
+	}
+	return e.Diff(other)
+}
+
+// Diff returns the changes that turn other into e, or all of e when other is
+// nil
+func (e *Level) Diff(other *Level) *LevelDelta {
+	if other == nil {
+		other = &Level{}
+	}
+	d := &LevelDelta{}
+	if e.ID != other.ID {
+		v := e.ID
+		d.ID = &v
+	}
+	if !delta.SlicesEqualFunc(e.Tiles, other.Tiles, delta.SlicesEqual[uint8]) {
+		v := delta.CloneSliceFunc(e.Tiles, delta.CloneSlice[uint8])
+		d.Tiles = &v
+	}
+	if !delta.MapsEqualFunc(e.Spawns, other.Spawns, delta.SlicesEqual[int32]) {
+		v := delta.CloneMapFunc(e.Spawns, delta.CloneSlice[int32])
+		d.Spawns = &v
+	}
+	if !delta.MapsEqualFunc(e.Loot, other.Loot, delta.MapsEqual[int32, uint16]) {
+		v := delta.CloneMapFunc(e.Loot, delta.CloneMap[int32, uint16])
+		d.Loot = &v
+	}
+	if !delta.SlicesEqualFunc(e.Heights, other.Heights, func(a, b [][]float32) bool { return delta.SlicesEqualFunc(a, b, delta.SlicesEqual[float32]) }) {
+		v := delta.CloneSliceFunc(e.Heights, func(v [][]float32) [][]float32 { return delta.CloneSliceFunc(v, delta.CloneSlice[float32]) })
+		d.Heights = &v
+	}
+	if !delta.SlicesEqualFunc(e.Chunks, other.Chunks, delta.SlicesEqual[byte]) {
+		v := delta.CloneSliceFunc(e.Chunks, delta.CloneSlice[byte])
+		d.Chunks = &v
+	}
+	return d
+}
+
+func (e *Level) ApplyDelta(d delta.Delta) {
+	if d == nil {
+		return
+	}
+	dt, ok := d.(*LevelDelta)
+	if !ok {
+		return // or panic
+	}
+	dt.ApplyTo(e)
+}
+
+// ApplyDeltaChecked is like ApplyDelta, but reports a delta of another type or
+// one that fails validation instead of ignoring it
+func (e *Level) ApplyDeltaChecked(d delta.Delta) error {
+	if d == nil {
+		return nil
+	}
+	dt, ok := d.(*LevelDelta)
+	if !ok {
+		return delta.TypeMismatch("Level", d)
+	}
+	return dt.ApplyToChecked(e)
+}
+
+// Apply applies d to e if it passes validation
+func (e *Level) Apply(d *LevelDelta) error {
+	if d == nil {
+		return nil
+	}
+	return d.ApplyToChecked(e)
+}
+
+var _ delta.ValidatingDelta = (*LevelDelta)(nil)
+
+type LevelDelta struct {
+	ID      *int64
+	Tiles   *[][]uint8
+	Spawns  *map[string][]int32
+	Loot    *map[string]map[int32]uint16
+	Heights *[][][]float32
+	Chunks  *[][]byte
+}
+
+// IsEmpty returns true if the delta carries no changes
+func (d *LevelDelta) IsEmpty() bool {
+	return d.ID == nil && d.Tiles == nil && d.Spawns == nil && d.Loot == nil && d.Heights == nil && d.Chunks == nil
+}
+
+// Validate returns a *delta.ApplyError if d cannot be applied to e, because e
+// has another type or a field would leave the bounds of its tags, without
+// changing e. Deltas do not carry the ID of the entity they were computed for,
+// so a full state applied to another entity is only reported by delta.Route.
+func (d *LevelDelta) Validate(e delta.Entity) error {
+	_, ok := e.(*Level)
+	if !ok {
+		return delta.TypeMismatch("Level", e)
+	}
+	return nil
+}
+
+// ApplyToChecked applies d to e only if it passes Validate, so either all of d
+// is applied or none of it
+func (d *LevelDelta) ApplyToChecked(e delta.Entity) error {
+	if err := d.Validate(e); err != nil {
+		return err
+	}
+	d.ApplyTo(e)
+	return nil
+}
+
+func (d *LevelDelta) ApplyTo(e delta.Entity) {
+	et, ok := e.(*Level)
+	if !ok {
+		return // or panic
+	}
+	if d.ID != nil {
+		et.ID = *d.ID
+	}
+	if d.Tiles != nil {
+		et.Tiles = delta.CloneSliceFunc(*d.Tiles, delta.CloneSlice[uint8])
+	}
+	if d.Spawns != nil {
+		et.Spawns = delta.CloneMapFunc(*d.Spawns, delta.CloneSlice[int32])
+	}
+	if d.Loot != nil {
+		et.Loot = delta.CloneMapFunc(*d.Loot, delta.CloneMap[int32, uint16])
+	}
+	if d.Heights != nil {
+		et.Heights = delta.CloneSliceFunc(*d.Heights, func(v [][]float32) [][]float32 { return delta.CloneSliceFunc(v, delta.CloneSlice[float32]) })
+	}
+	if d.Chunks != nil {
+		et.Chunks = delta.CloneSliceFunc(*d.Chunks, delta.CloneSlice[byte])
+	}
+}
+
+func (d *LevelDelta) Serialize(w io.Writer) error {
+	bw := delta.NewBinaryWriter(w)
+
+	// Write field presence bitmask
+	var fieldMask uint64
+	if d.ID != nil {
+		fieldMask |= 1 << 0
+	}
+	if d.Tiles != nil {
+		fieldMask |= 1 << 1
+	}
+	if d.Spawns != nil {
+		fieldMask |= 1 << 2
+	}
+	if d.Loot != nil {
+		fieldMask |= 1 << 3
+	}
+	if d.Heights != nil {
+		fieldMask |= 1 << 4
+	}
+	if d.Chunks != nil {
+		fieldMask |= 1 << 5
+	}
+	if err := bw.WriteUint64(fieldMask); err != nil {
+		return err
+	}
+
+	// Write field values for present fields
+	if d.ID != nil {
+		// Serialize primitive
+		if err := bw.WriteInt64(*d.ID); err != nil {
+			return err
+		}
+	}
+	if d.Tiles != nil {
+		// Serialize nested collection
+		if err := delta.WriteSlice(bw, *d.Tiles, func(v []uint8) error { return delta.WriteSlice(bw, v, bw.WriteUint8) }); err != nil {
+			return err
+		}
+	}
+	if d.Spawns != nil {
+		// Serialize nested collection
+		if err := delta.WriteMap(bw, *d.Spawns, bw.WriteString, func(v []int32) error { return delta.WriteSlice(bw, v, bw.WriteInt32) }); err != nil {
+			return err
+		}
+	}
+	if d.Loot != nil {
+		// Serialize nested collection
+		if err := delta.WriteMap(bw, *d.Loot, bw.WriteString, func(v map[int32]uint16) error { return delta.WriteMap(bw, v, bw.WriteInt32, bw.WriteUint16) }); err != nil {
+			return err
+		}
+	}
+	if d.Heights != nil {
+		// Serialize nested collection
+		if err := delta.WriteSlice(bw, *d.Heights, func(v [][]float32) error {
+			return delta.WriteSlice(bw, v, func(v []float32) error { return delta.WriteSlice(bw, v, bw.WriteFloat32) })
+		}); err != nil {
+			return err
+		}
+	}
+	if d.Chunks != nil {
+		// Serialize nested collection
+		if err := delta.WriteSlice(bw, *d.Chunks, bw.WriteBytes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *LevelDelta) Deserialize(r io.Reader) error {
+	br := delta.NewBinaryReader(r)
+
+	// Read field presence bitmask
+	fieldMask, err := br.ReadFieldMask(6)
+	if err != nil {
+		return delta.WrapDecodeError("Level", "", br.Offset(), err)
+	}
+
+	// Read field values for present fields
+	if fieldMask&(1<<0) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt64()
+		if err != nil {
+			return delta.WrapDecodeError("Level", "ID", offset, err)
+		}
+		d.ID = &val
+	}
+	if fieldMask&(1<<1) != 0 {
+		offset := br.Offset()
+		// Deserialize nested collection
+		val, err := delta.ReadSlice(br, func() ([]uint8, error) { return delta.ReadSlice(br, br.ReadUint8) })
+		if err != nil {
+			return delta.WrapDecodeError("Level", "Tiles", offset, err)
+		}
+		d.Tiles = &val
+	}
+	if fieldMask&(1<<2) != 0 {
+		offset := br.Offset()
+		// Deserialize nested collection
+		val, err := delta.ReadMap(br, br.ReadString, func() ([]int32, error) { return delta.ReadSlice(br, br.ReadInt32) })
+		if err != nil {
+			return delta.WrapDecodeError("Level", "Spawns", offset, err)
+		}
+		d.Spawns = &val
+	}
+	if fieldMask&(1<<3) != 0 {
+		offset := br.Offset()
+		// Deserialize nested collection
+		val, err := delta.ReadMap(br, br.ReadString, func() (map[int32]uint16, error) { return delta.ReadMap(br, br.ReadInt32, br.ReadUint16) })
+		if err != nil {
+			return delta.WrapDecodeError("Level", "Loot", offset, err)
+		}
+		d.Loot = &val
+	}
+	if fieldMask&(1<<4) != 0 {
+		offset := br.Offset()
+		// Deserialize nested collection
+		val, err := delta.ReadSlice(br, func() ([][]float32, error) {
+			return delta.ReadSlice(br, func() ([]float32, error) { return delta.ReadSlice(br, br.ReadFloat32) })
+		})
+		if err != nil {
+			return delta.WrapDecodeError("Level", "Heights", offset, err)
+		}
+		d.Heights = &val
+	}
+	if fieldMask&(1<<5) != 0 {
+		offset := br.Offset()
+		// Deserialize nested collection
+		val, err := delta.ReadSlice(br, br.ReadBytes)
+		if err != nil {
+			return delta.WrapDecodeError("Level", "Chunks", offset, err)
+		}
+		d.Chunks = &val
+	}
+
+	return nil
+}
+
+var (
+	_ delta.EntityOf[int64]             = (*Unit)(nil)
+	_ delta.Diffable[*Unit, *UnitDelta] = (*Unit)(nil)
+	_ delta.CheckedEntity               = (*Unit)(nil)
+)
+
+func (e *Unit) GetID() int64 {
+	return e.ID
+}
+
+func (e *Unit) Clone() delta.Entity {
+	cp := *e
+	if e.Buffs != nil {
+		cp.Buffs = make(map[string]struct{})
+		for k, v := range e.Buffs {
+			cp.Buffs[k] = v
+		}
+	}
+	if e.Flags != nil {
+		cp.Flags = make(map[int32]bool)
+		for k, v := range e.Flags {
+			cp.Flags[k] = v
+		}
+	}
+	return &cp
+}
+
+func (e *Unit) Delta(o delta.Entity) delta.Delta {
+	if o == nil {
+		return nil
+	}
+	other, ok := o.(*Unit)
+	if !ok {
+		return nil // or panic
+	}
+	return e.Diff(other)
+}
+
+// Diff returns the changes that turn other into e, or all of e when other is
+// nil
+func (e *Unit) Diff(other *Unit) *UnitDelta {
+	if other == nil {
+		other = &Unit{}
+	}
+	d := &UnitDelta{}
+	if e.ID != other.ID {
+		v := e.ID
+		d.ID = &v
+	}
+	if e.Kind != other.Kind {
+		v := e.Kind
+		d.Kind = &v
+	}
+	if e.HP != other.HP {
+		v := e.HP
+		d.HP = &v
+	}
+	d.Buffs = delta.DiffSet(e.Buffs, other.Buffs, struct{}{})
+	d.Flags = delta.DiffSet(e.Flags, other.Flags, true)
+	return d
+}
+
+func (e *Unit) ApplyDelta(d delta.Delta) {
+	if d == nil {
+		return
+	}
+	dt, ok := d.(*UnitDelta)
+	if !ok {
+		return // or panic
+	}
+	dt.ApplyTo(e)
+}
+
+// ApplyDeltaChecked is like ApplyDelta, but reports a delta of another type or
+// one that fails validation instead of ignoring it
+func (e *Unit) ApplyDeltaChecked(d delta.Delta) error {
+	if d == nil {
+		return nil
+	}
+	dt, ok := d.(*UnitDelta)
+	if !ok {
+		return delta.TypeMismatch("Unit", d)
+	}
+	return dt.ApplyToChecked(e)
+}
+
+// Apply applies d to e if it passes validation
+func (e *Unit) Apply(d *UnitDelta) error {
+	if d == nil {
+		return nil
+	}
+	return d.ApplyToChecked(e)
+}
+
+var _ delta.ValidatingDelta = (*UnitDelta)(nil)
+
+type UnitDelta struct {
+	ID    *int64
+	Kind  *string
+	HP    *int32
+	Buffs *delta.SetDelta[string]
+	Flags *delta.SetDelta[int32]
+}
+
+// IsEmpty returns true if the delta carries no changes
+func (d *UnitDelta) IsEmpty() bool {
+	return d.ID == nil && d.Kind == nil && d.HP == nil && d.Buffs == nil && d.Flags == nil
+}
+
+// Validate returns a *delta.ApplyError if d cannot be applied to e, because e
+// has another type or a field would leave the bounds of its tags, without
+// changing e. Deltas do not carry the ID of the entity they were computed for,
+// so a full state applied to another entity is only reported by delta.Route.
+func (d *UnitDelta) Validate(e delta.Entity) error {
+	_, ok := e.(*Unit)
+	if !ok {
+		return delta.TypeMismatch("Unit", e)
+	}
+	return nil
+}
+
+// ApplyToChecked applies d to e only if it passes Validate, so either all of d
+// is applied or none of it
+func (d *UnitDelta) ApplyToChecked(e delta.Entity) error {
+	if err := d.Validate(e); err != nil {
+		return err
+	}
+	d.ApplyTo(e)
+	return nil
+}
+
+func (d *UnitDelta) ApplyTo(e delta.Entity) {
+	et, ok := e.(*Unit)
+	if !ok {
+		return // or panic
+	}
+	if d.ID != nil {
+		et.ID = *d.ID
+	}
+	if d.Kind != nil {
+		et.Kind = *d.Kind
+	}
+	if d.HP != nil {
+		et.HP = *d.HP
+	}
+	if d.Buffs != nil {
+		delta.ApplySet(&et.Buffs, d.Buffs, struct{}{})
+	}
+	if d.Flags != nil {
+		delta.ApplySet(&et.Flags, d.Flags, true)
+	}
+}
+
+func (d *UnitDelta) Serialize(w io.Writer) error {
+	bw := delta.NewBinaryWriter(w)
+
+	// Write field presence bitmask
+	var fieldMask uint64
+	if d.ID != nil {
+		fieldMask |= 1 << 0
+	}
+	if d.Kind != nil {
+		fieldMask |= 1 << 1
+	}
+	if d.HP != nil {
+		fieldMask |= 1 << 2
+	}
+	if d.Buffs != nil {
+		fieldMask |= 1 << 3
+	}
+	if d.Flags != nil {
+		fieldMask |= 1 << 4
+	}
+	if err := bw.WriteUint64(fieldMask); err != nil {
+		return err
+	}
+
+	// Write field values for present fields
+	if d.ID != nil {
+		// Serialize primitive
+		if err := bw.WriteInt64(*d.ID); err != nil {
+			return err
+		}
+	}
+	if d.Kind != nil {
+		// Serialize primitive
+		if err := bw.WriteString(*d.Kind); err != nil {
+			return err
+		}
+	}
+	if d.HP != nil {
+		// Serialize primitive
+		if err := bw.WriteInt32(*d.HP); err != nil {
+			return err
+		}
+	}
+	if d.Buffs != nil {
+		// Serialize set
+		if err := delta.WriteSet(bw, d.Buffs, bw.WriteString); err != nil {
+			return err
+		}
+	}
+	if d.Flags != nil {
+		// Serialize set
+		if err := delta.WriteSet(bw, d.Flags, bw.WriteInt32); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *UnitDelta) Deserialize(r io.Reader) error {
+	br := delta.NewBinaryReader(r)
+
+	// Read field presence bitmask
+	fieldMask, err := br.ReadFieldMask(5)
+	if err != nil {
+		return delta.WrapDecodeError("Unit", "", br.Offset(), err)
+	}
+
+	// Read field values for present fields
+	if fieldMask&(1<<0) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt64()
+		if err != nil {
+			return delta.WrapDecodeError("Unit", "ID", offset, err)
+		}
+		d.ID = &val
+	}
+	if fieldMask&(1<<1) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadString()
+		if err != nil {
+			return delta.WrapDecodeError("Unit", "Kind", offset, err)
+		}
+		d.Kind = &val
+	}
+	if fieldMask&(1<<2) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt32()
+		if err != nil {
+			return delta.WrapDecodeError("Unit", "HP", offset, err)
+		}
+		d.HP = &val
+	}
+	if fieldMask&(1<<3) != 0 {
+		offset := br.Offset()
+		// Deserialize set
+		sd, err := delta.ReadSet(br, br.ReadString)
+		if err != nil {
+			return delta.WrapDecodeError("Unit", "Buffs", offset, err)
+		}
+		d.Buffs = sd
+	}
+	if fieldMask&(1<<4) != 0 {
+		offset := br.Offset()
+		// Deserialize set
+		sd, err := delta.ReadSet(br, br.ReadInt32)
+		if err != nil {
+			return delta.WrapDecodeError("Unit", "Flags", offset, err)
+		}
+		d.Flags = sd
+	}
+
+	return nil
+}
+
+var (
+	_ delta.EntityOf[int64]               = (*Lobby)(nil)
+	_ delta.Diffable[*Lobby, *LobbyDelta] = (*Lobby)(nil)
+	_ delta.CheckedEntity                 = (*Lobby)(nil)
+)
+
+func (e *Lobby) GetID() int64 {
+	return e.ID
+}
+
+func (e *Lobby) Clone() delta.Entity {
+	cp := *e
+	if e.Players != nil {
+		cp.Players = make([]Player, len(e.Players))
+		for i := range e.Players {
+			cp.Players[i] = *e.Players[i].Clone().(*Player)
+		}
+	}
+	if e.Units != nil {
+		cp.Units = make(map[int64]Unit)
+		for k, v := range e.Units {
+			cp.Units[k] = *v.Clone().(*Unit)
+		}
+	}
+	if e.Projectiles != nil {
+		cp.Projectiles = make([]Projectile, len(e.Projectiles))
+		for i := range e.Projectiles {
+			cp.Projectiles[i] = *e.Projectiles[i].Clone().(*Projectile)
+		}
+	}
+	return &cp
+}
+
+func (e *Lobby) Delta(o delta.Entity) delta.Delta {
+	if o == nil {
+		return nil
+	}
+	other, ok := o.(*Lobby)
+	if !ok {
+		return nil // or panic
+	}
+	return e.Diff(other)
+}
+
+// Diff returns the changes that turn other into e, or all of e when other is
+// nil
+func (e *Lobby) Diff(other *Lobby) *LobbyDelta {
+	if other == nil {
+		other = &Lobby{}
+	}
+	d := &LobbyDelta{}
+	if e.ID != other.ID {
+		v := e.ID
+		d.ID = &v
+	}
+	if e.Name != other.Name {
+		v := e.Name
+		d.Name = &v
+	}
+	d.Players = delta.DiffSlice[Player, *Player, int64, *PlayerDelta](e.Players, other.Players)
+	d.Units = delta.DiffMap[int64, Unit, *Unit, int64, *UnitDelta](e.Units, other.Units)
+	d.Projectiles = delta.DiffSlice[Projectile, *Projectile, Handle, *ProjectileDelta](e.Projectiles, other.Projectiles)
+	return d
+}
+
+func (e *Lobby) ApplyDelta(d delta.Delta) {
+	if d == nil {
+		return
+	}
+	dt, ok := d.(*LobbyDelta)
+	if !ok {
+		return // or panic
+	}
+	dt.ApplyTo(e)
+}
+
+// ApplyDeltaChecked is like ApplyDelta, but reports a delta of another type or
+// one that fails validation instead of ignoring it
+func (e *Lobby) ApplyDeltaChecked(d delta.Delta) error {
+	if d == nil {
+		return nil
+	}
+	dt, ok := d.(*LobbyDelta)
+	if !ok {
+		return delta.TypeMismatch("Lobby", d)
+	}
+	return dt.ApplyToChecked(e)
+}
+
+// Apply applies d to e if it passes validation
+func (e *Lobby) Apply(d *LobbyDelta) error {
+	if d == nil {
+		return nil
+	}
+	return d.ApplyToChecked(e)
+}
+
+var _ delta.ValidatingDelta = (*LobbyDelta)(nil)
+
+type LobbyDelta struct {
+	ID          *int64
+	Name        *string
+	Players     *delta.CollectionDelta[int64, *PlayerDelta]
+	Units       *delta.CollectionDelta[int64, *UnitDelta]
+	Projectiles *delta.CollectionDelta[Handle, *ProjectileDelta]
+}
+
+// IsEmpty returns true if the delta carries no changes
+func (d *LobbyDelta) IsEmpty() bool {
+	return d.ID == nil && d.Name == nil && d.Players == nil && d.Units == nil && d.Projectiles == nil
+}
+
+// Validate returns a *delta.ApplyError if d cannot be applied to e, because e
+// has another type or a field would leave the bounds of its tags, without
+// changing e. Deltas do not carry the ID of the entity they were computed for,
+// so a full state applied to another entity is only reported by delta.Route.
+func (d *LobbyDelta) Validate(e delta.Entity) error {
+	et, ok := e.(*Lobby)
+	if !ok {
+		return delta.TypeMismatch("Lobby", e)
+	}
+	if err := delta.ValidateSlice[Player, *Player, int64, *PlayerDelta]("Lobby", "Players", et.Players, d.Players); err != nil {
+		return err
+	}
+	if err := delta.ValidateMap[int64, Unit, *Unit, int64]("Lobby", "Units", et.Units, d.Units); err != nil {
+		return err
+	}
+	if err := delta.ValidateSlice[Projectile, *Projectile, Handle, *ProjectileDelta]("Lobby", "Projectiles", et.Projectiles, d.Projectiles); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ApplyToChecked applies d to e only if it passes Validate, so either all of d
+// is applied or none of it
+func (d *LobbyDelta) ApplyToChecked(e delta.Entity) error {
+	if err := d.Validate(e); err != nil {
+		return err
+	}
+	d.ApplyTo(e)
+	return nil
+}
+
+func (d *LobbyDelta) ApplyTo(e delta.Entity) {
+	et, ok := e.(*Lobby)
+	if !ok {
+		return // or panic
+	}
+	if d.ID != nil {
+		et.ID = *d.ID
+	}
+	if d.Name != nil {
+		et.Name = *d.Name
+	}
+	if d.Players != nil {
+		delta.ApplySlice(&et.Players, d.Players)
+	}
+	if d.Units != nil {
+		delta.ApplyMap[int64, Unit, *Unit, int64](&et.Units, d.Units)
+	}
+	if d.Projectiles != nil {
+		delta.ApplySlice(&et.Projectiles, d.Projectiles)
+	}
+}
+
+func (d *LobbyDelta) Serialize(w io.Writer) error {
+	bw := delta.NewBinaryWriter(w)
+
+	// Write field presence bitmask
+	var fieldMask uint64
+	if d.ID != nil {
+		fieldMask |= 1 << 0
+	}
+	if d.Name != nil {
+		fieldMask |= 1 << 1
+	}
+	if d.Players != nil {
+		fieldMask |= 1 << 2
+	}
+	if d.Units != nil {
+		fieldMask |= 1 << 3
+	}
+	if d.Projectiles != nil {
+		fieldMask |= 1 << 4
+	}
+	if err := bw.WriteUint64(fieldMask); err != nil {
+		return err
+	}
+
+	// Write field values for present fields
+	if d.ID != nil {
+		// Serialize primitive
+		if err := bw.WriteInt64(*d.ID); err != nil {
+			return err
+		}
+	}
+	if d.Name != nil {
+		// Serialize primitive
+		if err := bw.WriteString(*d.Name); err != nil {
+			return err
+		}
+	}
+	if d.Players != nil {
+		// Serialize entity collection
+		if err := delta.WriteCollection(bw, d.Players, bw.WriteInt64); err != nil {
+			return err
+		}
+	}
+	if d.Units != nil {
+		// Serialize entity collection
+		if err := delta.WriteCollection(bw, d.Units, bw.WriteInt64); err != nil {
+			return err
+		}
+	}
+	if d.Projectiles != nil {
+		// Serialize entity collection
+		if err := delta.WriteCollection(bw, d.Projectiles, func(v Handle) error { return bw.WriteUint32(uint32(v)) }); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *LobbyDelta) Deserialize(r io.Reader) error {
+	br := delta.NewBinaryReader(r)
+
+	// Read field presence bitmask
+	fieldMask, err := br.ReadFieldMask(5)
+	if err != nil {
+		return delta.WrapDecodeError("Lobby", "", br.Offset(), err)
+	}
+
+	// Read field values for present fields
+	if fieldMask&(1<<0) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt64()
+		if err != nil {
+			return delta.WrapDecodeError("Lobby", "ID", offset, err)
+		}
+		d.ID = &val
+	}
+	if fieldMask&(1<<1) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadString()
+		if err != nil {
+			return delta.WrapDecodeError("Lobby", "Name", offset, err)
+		}
+		d.Name = &val
+	}
+	if fieldMask&(1<<2) != 0 {
+		offset := br.Offset()
+		// Deserialize entity collection
+		cd, err := delta.ReadCollection(br, br.ReadInt64, func() *PlayerDelta { return &PlayerDelta{} })
+		if err != nil {
+			return delta.WrapDecodeError("Lobby", "Players", offset, err)
+		}
+		d.Players = cd
+	}
+	if fieldMask&(1<<3) != 0 {
+		offset := br.Offset()
+		// Deserialize entity collection
+		cd, err := delta.ReadCollection(br, br.ReadInt64, func() *UnitDelta { return &UnitDelta{} })
+		if err != nil {
+			return delta.WrapDecodeError("Lobby", "Units", offset, err)
+		}
+		d.Units = cd
+	}
+	if fieldMask&(1<<4) != 0 {
+		offset := br.Offset()
+		// Deserialize entity collection
+		cd, err := delta.ReadCollection(br, func() (Handle, error) { v, err := br.ReadUint32(); return Handle(v), err }, func() *ProjectileDelta { return &ProjectileDelta{} })
+		if err != nil {
+			return delta.WrapDecodeError("Lobby", "Projectiles", offset, err)
+		}
+		d.Projectiles = cd
+	}
+
+	return nil
+}
+
+var (
+	_ delta.EntityOf[int64]                 = (*Player)(nil)
+	_ delta.Diffable[*Player, *PlayerDelta] = (*Player)(nil)
+	_ delta.CheckedEntity                   = (*Player)(nil)
+)
+
+func (e *Player) GetID() int64 {
+	return e.ID
+}
+
+func (e *Player) Clone() delta.Entity {
+	cp := *e
+	if e.Tags != nil {
+		cp.Tags = make([]string, len(e.Tags))
+		copy(cp.Tags, e.Tags)
+	}
+	cp.Weapon = delta.CloneInterface(e.Weapon)
+	if e.PendingTags != nil {
+		cp.PendingTags = make([]string, len(e.PendingTags))
+		copy(cp.PendingTags, e.PendingTags)
+	}
+	if e.Timers != nil {
+		cp.Timers = make(map[string]int32)
+		for k, v := range e.Timers {
+			cp.Timers[k] = v
+		}
+	}
+	return &cp
+}
+
+func (e *Player) Delta(o delta.Entity) delta.Delta {
+	if o == nil {
+		return nil
+	}
+	other, ok := o.(*Player)
+	if !ok {
+		return nil // or panic
+	}
+	return e.Diff(other)
+}
+
+// Diff returns the changes that turn other into e, or all of e when other is
+// nil
+func (e *Player) Diff(other *Player) *PlayerDelta {
+	if other == nil {
+		other = &Player{}
+	}
+	d := &PlayerDelta{}
+	if e.ID != other.ID {
+		v := e.ID
+		d.ID = &v
+	}
+	if e.X != other.X {
+		v := e.X
+		d.X = &v
+	}
+	if e.Y != other.Y {
+		v := e.Y
+		d.Y = &v
+	}
+	if e.Name != other.Name {
+		v := e.Name
+		d.Name = &v
+	}
+	if e.Health != other.Health {
+		v := e.Health
+		d.Health = &v
+	}
+	if !delta.SlicesEqual(e.Tags, other.Tags) {
+		if e.Tags != nil {
+			v := make([]string, len(e.Tags))
+			copy(v, e.Tags)
+			d.Tags = &v
+		} else {
+			d.Tags = &[]string{}
+		}
+	}
+	d.Weapon = delta.DiffInterface(e.Weapon, other.Weapon)
+	if e.level != other.level {
+		v := e.level
+		d.level = &v
+	}
+	if e.Score != other.Score {
+		v := e.Score
+		d.Score = &v
+	}
+	return d
+}
+
+func (e *Player) ApplyDelta(d delta.Delta) {
+	if d == nil {
+		return
+	}
+	dt, ok := d.(*PlayerDelta)
+	if !ok {
+		return // or panic
+	}
+	dt.ApplyTo(e)
+}
+
+// ApplyDeltaChecked is like ApplyDelta, but reports a delta of another type or
+// one that fails validation instead of ignoring it
+func (e *Player) ApplyDeltaChecked(d delta.Delta) error {
+	if d == nil {
+		return nil
+	}
+	dt, ok := d.(*PlayerDelta)
+	if !ok {
+		return delta.TypeMismatch("Player", d)
+	}
+	return dt.ApplyToChecked(e)
+}
+
+// Apply applies d to e if it passes validation
+func (e *Player) Apply(d *PlayerDelta) error {
+	if d == nil {
+		return nil
+	}
+	return d.ApplyToChecked(e)
+}
+
+var _ delta.ValidatingDelta = (*PlayerDelta)(nil)
+
+type PlayerDelta struct {
+	ID     *int64
+	X      *float64
+	Y      *float64
+	Name   *string
+	Health *int32
+	Tags   *[]string
+	Weapon *delta.InterfaceDelta
+	level  *int32
+	Score  *Score
+}
+
+// IsEmpty returns true if the delta carries no changes
+func (d *PlayerDelta) IsEmpty() bool {
+	return d.ID == nil && d.X == nil && d.Y == nil && d.Name == nil && d.Health == nil && d.Tags == nil && d.Weapon == nil && d.level == nil && d.Score == nil
+}
+
+// Validate returns a *delta.ApplyError if d cannot be applied to e, because e
+// has another type or a field would leave the bounds of its tags, without
+// changing e. Deltas do not carry the ID of the entity they were computed for,
+// so a full state applied to another entity is only reported by delta.Route.
+func (d *PlayerDelta) Validate(e delta.Entity) error {
+	et, ok := e.(*Player)
+	if !ok {
+		return delta.TypeMismatch("Player", e)
+	}
+	if d.Name != nil {
+		if err := delta.CheckMaxLen("Player", "Name", len(*d.Name), 32); err != nil {
+			return err
+		}
+	}
+	if d.Health != nil {
+		if err := delta.CheckMin("Player", "Health", *d.Health, 0); err != nil {
+			return err
+		}
+		if err := delta.CheckMax("Player", "Health", *d.Health, 100); err != nil {
+			return err
+		}
+	}
+	if d.Tags != nil {
+		if err := delta.CheckMaxLen("Player", "Tags", len(*d.Tags), 8); err != nil {
+			return err
+		}
+	}
+	if err := delta.ValidateInterface("Player", "Weapon", et.Weapon, d.Weapon); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ApplyToChecked applies d to e only if it passes Validate, so either all of d
+// is applied or none of it
+func (d *PlayerDelta) ApplyToChecked(e delta.Entity) error {
+	if err := d.Validate(e); err != nil {
+		return err
+	}
+	d.ApplyTo(e)
+	return nil
+}
+
+func (d *PlayerDelta) ApplyTo(e delta.Entity) {
+	et, ok := e.(*Player)
+	if !ok {
+		return // or panic
+	}
+	if d.ID != nil {
+		et.ID = *d.ID
+	}
+	if d.X != nil {
+		et.X = *d.X
+	}
+	if d.Y != nil {
+		et.Y = *d.Y
+	}
+	if d.Name != nil {
+		et.Name = *d.Name
+	}
+	if d.Health != nil {
+		et.Health = *d.Health
+	}
+	if d.Tags != nil {
+		if *d.Tags != nil {
+			et.Tags = make([]string, len(*d.Tags))
+			copy(et.Tags, *d.Tags)
+		} else {
+			et.Tags = nil
+		}
+	}
+	if d.Weapon != nil {
+		delta.ApplyInterface(&et.Weapon, d.Weapon)
+	}
+	if d.level != nil {
+		et.level = *d.level
+	}
+	if d.Score != nil {
+		et.Score = *d.Score
+	}
+}
+
+func (d *PlayerDelta) Serialize(w io.Writer) error {
+	bw := delta.NewBinaryWriter(w)
+
+	// Write field presence bitmask
+	var fieldMask uint64
+	if d.ID != nil {
+		fieldMask |= 1 << 0
+	}
+	if d.X != nil {
+		fieldMask |= 1 << 1
+	}
+	if d.Y != nil {
+		fieldMask |= 1 << 2
+	}
+	if d.Name != nil {
+		fieldMask |= 1 << 3
+	}
+	if d.Health != nil {
+		fieldMask |= 1 << 4
+	}
+	if d.Tags != nil {
+		fieldMask |= 1 << 5
+	}
+	if d.Weapon != nil {
+		fieldMask |= 1 << 6
+	}
+	if d.level != nil {
+		fieldMask |= 1 << 7
+	}
+	if d.Score != nil {
+		fieldMask |= 1 << 8
+	}
+	if err := bw.WriteUint64(fieldMask); err != nil {
+		return err
+	}
+
+	// Write field values for present fields
+	if d.ID != nil {
+		// Serialize primitive
+		if err := bw.WriteInt64(*d.ID); err != nil {
+			return err
+		}
+	}
+	if d.X != nil {
+		// Serialize primitive
+		if err := bw.WriteFloat64(*d.X); err != nil {
+			return err
+		}
+	}
+	if d.Y != nil {
+		// Serialize primitive
+		if err := bw.WriteFloat64(*d.Y); err != nil {
+			return err
+		}
+	}
+	if d.Name != nil {
+		// Serialize primitive
+		if err := bw.WriteString(*d.Name); err != nil {
+			return err
+		}
+	}
+	if d.Health != nil {
+		// Serialize primitive
+		if err := bw.WriteInt32(*d.Health); err != nil {
+			return err
+		}
+	}
+	if d.Tags != nil {
+		// Serialize slice
+		if err := bw.WriteVarUint32(uint32(len(*d.Tags))); err != nil {
+			return err
+		}
+		for _, item := range *d.Tags {
+			if err := bw.WriteString(item); err != nil {
+				return err
+			}
+		}
+	}
+	if d.Weapon != nil {
+		// Serialize interface
+		if err := delta.WriteInterface(bw, d.Weapon); err != nil {
+			return err
+		}
+	}
+	if d.level != nil {
+		// Serialize primitive
+		if err := bw.WriteInt32(*d.level); err != nil {
+			return err
+		}
+	}
+	if d.Score != nil {
+		// Serialize primitive
+		if err := bw.WriteInt32(int32(*d.Score)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *PlayerDelta) Deserialize(r io.Reader) error {
+	br := delta.NewBinaryReader(r)
+
+	// Read field presence bitmask
+	fieldMask, err := br.ReadFieldMask(9)
+	if err != nil {
+		return delta.WrapDecodeError("Player", "", br.Offset(), err)
+	}
+
+	// Read field values for present fields
+	if fieldMask&(1<<0) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt64()
+		if err != nil {
+			return delta.WrapDecodeError("Player", "ID", offset, err)
+		}
+		d.ID = &val
+	}
+	if fieldMask&(1<<1) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadFloat64()
+		if err != nil {
+			return delta.WrapDecodeError("Player", "X", offset, err)
+		}
+		d.X = &val
+	}
+	if fieldMask&(1<<2) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadFloat64()
+		if err != nil {
+			return delta.WrapDecodeError("Player", "Y", offset, err)
+		}
+		d.Y = &val
+	}
+	if fieldMask&(1<<3) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadString()
+		if err != nil {
+			return delta.WrapDecodeError("Player", "Name", offset, err)
+		}
+		d.Name = &val
+	}
+	if fieldMask&(1<<4) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt32()
+		if err != nil {
+			return delta.WrapDecodeError("Player", "Health", offset, err)
+		}
+		d.Health = &val
+	}
+	if fieldMask&(1<<5) != 0 {
+		offset := br.Offset()
+		// Deserialize slice
+		length, err := br.ReadVarUint32()
+		if err != nil {
+			return delta.WrapDecodeError("Player", "Tags", offset, err)
+		}
+		slice := make([]string, length)
+		for i := range slice {
+			item, err := br.ReadString()
+			if err != nil {
+				return delta.WrapDecodeError("Player", "Tags", offset, err)
+			}
+			slice[i] = item
+		}
+		d.Tags = &slice
+	}
+	if fieldMask&(1<<6) != 0 {
+		offset := br.Offset()
+		// Deserialize interface
+		id, err := delta.ReadInterface(br)
+		if err != nil {
+			return delta.WrapDecodeError("Player", "Weapon", offset, err)
+		}
+		d.Weapon = id
+	}
+	if fieldMask&(1<<7) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt32()
+		if err != nil {
+			return delta.WrapDecodeError("Player", "level", offset, err)
+		}
+		d.level = &val
+	}
+	if fieldMask&(1<<8) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt32()
+		if err != nil {
+			return delta.WrapDecodeError("Player", "Score", offset, err)
+		}
+		v := Score(val)
+		d.Score = &v
+	}
+
+	return nil
+}
+
+var (
+	_ delta.EntityOf[Handle]                        = (*Projectile)(nil)
+	_ delta.Diffable[*Projectile, *ProjectileDelta] = (*Projectile)(nil)
+	_ delta.CheckedEntity                           = (*Projectile)(nil)
+)
+
+func (e *Projectile) GetID() Handle {
+	return e.Handle
+}
+
+func (e *Projectile) Clone() delta.Entity {
+	cp := *e
+	return &cp
+}
+
+func (e *Projectile) Delta(o delta.Entity) delta.Delta {
+	if o == nil {
+		return nil
+	}
+	other, ok := o.(*Projectile)
+	if !ok {
+		return nil // or panic
+	}
+	return e.Diff(other)
+}
+
+// Diff returns the changes that turn other into e, or all of e when other is
+// nil
+func (e *Projectile) Diff(other *Projectile) *ProjectileDelta {
+	if other == nil {
+		other = &Projectile{}
+	}
+	d := &ProjectileDelta{}
+	if e.Handle != other.Handle {
+		v := e.Handle
+		d.Handle = &v
+	}
+	if e.OwnerID != other.OwnerID {
+		v := e.OwnerID
+		d.OwnerID = &v
+	}
+	if e.X != other.X {
+		v := e.X
+		d.X = &v
+	}
+	if e.Y != other.Y {
+		v := e.Y
+		d.Y = &v
+	}
+	return d
+}
+
+func (e *Projectile) ApplyDelta(d delta.Delta) {
+	if d == nil {
+		return
+	}
+	dt, ok := d.(*ProjectileDelta)
+	if !ok {
+		return // or panic
+	}
+	dt.ApplyTo(e)
+}
+
+// ApplyDeltaChecked is like ApplyDelta, but reports a delta of another type or
+// one that fails validation instead of ignoring it
+func (e *Projectile) ApplyDeltaChecked(d delta.Delta) error {
+	if d == nil {
+		return nil
+	}
+	dt, ok := d.(*ProjectileDelta)
+	if !ok {
+		return delta.TypeMismatch("Projectile", d)
+	}
+	return dt.ApplyToChecked(e)
+}
+
+// Apply applies d to e if it passes validation
+func (e *Projectile) Apply(d *ProjectileDelta) error {
+	if d == nil {
+		return nil
+	}
+	return d.ApplyToChecked(e)
+}
+
+var _ delta.ValidatingDelta = (*ProjectileDelta)(nil)
+
+type ProjectileDelta struct {
+	Handle  *Handle
+	OwnerID *int64
+	X       *float32
+	Y       *float32
+}
+
+// IsEmpty returns true if the delta carries no changes
+func (d *ProjectileDelta) IsEmpty() bool {
+	return d.Handle == nil && d.OwnerID == nil && d.X == nil && d.Y == nil
+}
+
+// Validate returns a *delta.ApplyError if d cannot be applied to e, because e
+// has another type or a field would leave the bounds of its tags, without
+// changing e. Deltas do not carry the ID of the entity they were computed for,
+// so a full state applied to another entity is only reported by delta.Route.
+func (d *ProjectileDelta) Validate(e delta.Entity) error {
+	_, ok := e.(*Projectile)
+	if !ok {
+		return delta.TypeMismatch("Projectile", e)
+	}
+	return nil
+}
+
+// ApplyToChecked applies d to e only if it passes Validate, so either all of d
+// is applied or none of it
+func (d *ProjectileDelta) ApplyToChecked(e delta.Entity) error {
+	if err := d.Validate(e); err != nil {
+		return err
+	}
+	d.ApplyTo(e)
+	return nil
+}
+
+func (d *ProjectileDelta) ApplyTo(e delta.Entity) {
+	et, ok := e.(*Projectile)
+	if !ok {
+		return // or panic
+	}
+	if d.Handle != nil {
+		et.Handle = *d.Handle
+	}
+	if d.OwnerID != nil {
+		et.OwnerID = *d.OwnerID
+	}
+	if d.X != nil {
+		et.X = *d.X
+	}
+	if d.Y != nil {
+		et.Y = *d.Y
+	}
+}
+
+func (d *ProjectileDelta) Serialize(w io.Writer) error {
+	bw := delta.NewBinaryWriter(w)
+
+	// Write field presence bitmask
+	var fieldMask uint64
+	if d.Handle != nil {
+		fieldMask |= 1 << 0
+	}
+	if d.OwnerID != nil {
+		fieldMask |= 1 << 1
+	}
+	if d.X != nil {
+		fieldMask |= 1 << 2
+	}
+	if d.Y != nil {
+		fieldMask |= 1 << 3
+	}
+	if err := bw.WriteUint64(fieldMask); err != nil {
+		return err
+	}
+
+	// Write field values for present fields
+	if d.Handle != nil {
+		// Serialize primitive
+		if err := bw.WriteUint32(uint32(*d.Handle)); err != nil {
+			return err
+		}
+	}
+	if d.OwnerID != nil {
+		// Serialize primitive
+		if err := bw.WriteInt64(*d.OwnerID); err != nil {
+			return err
+		}
+	}
+	if d.X != nil {
+		// Serialize primitive
+		if err := bw.WriteFloat32(*d.X); err != nil {
+			return err
+		}
+	}
+	if d.Y != nil {
+		// Serialize primitive
+		if err := bw.WriteFloat32(*d.Y); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *ProjectileDelta) Deserialize(r io.Reader) error {
+	br := delta.NewBinaryReader(r)
+
+	// Read field presence bitmask
+	fieldMask, err := br.ReadFieldMask(4)
+	if err != nil {
+		return delta.WrapDecodeError("Projectile", "", br.Offset(), err)
+	}
+
+	// Read field values for present fields
+	if fieldMask&(1<<0) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadUint32()
+		if err != nil {
+			return delta.WrapDecodeError("Projectile", "Handle", offset, err)
+		}
+		v := Handle(val)
+		d.Handle = &v
+	}
+	if fieldMask&(1<<1) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt64()
+		if err != nil {
+			return delta.WrapDecodeError("Projectile", "OwnerID", offset, err)
+		}
+		d.OwnerID = &val
+	}
+	if fieldMask&(1<<2) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadFloat32()
+		if err != nil {
+			return delta.WrapDecodeError("Projectile", "X", offset, err)
+		}
+		d.X = &val
+	}
+	if fieldMask&(1<<3) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadFloat32()
+		if err != nil {
+			return delta.WrapDecodeError("Projectile", "Y", offset, err)
+		}
+		d.Y = &val
+	}
+
+	return nil
+}
+
+var (
+	_ delta.EntityOf[int64]               = (*Stash)(nil)
+	_ delta.Diffable[*Stash, *StashDelta] = (*Stash)(nil)
+	_ delta.CheckedEntity                 = (*Stash)(nil)
+)
+
+func (e *Stash) GetID() int64 {
+	return e.ID
+}
+
+func (e *Stash) Clone() delta.Entity {
+	cp := *e
+	if e.Items != nil {
+		cp.Items = make([]inventory.Item, len(e.Items))
+		for i := range e.Items {
+			cp.Items[i] = *e.Items[i].Clone().(*inventory.Item)
+		}
+	}
+	if e.Slots != nil {
+		cp.Slots = make(map[string]inventory.Item)
+		for k, v := range e.Slots {
+			cp.Slots[k] = *v.Clone().(*inventory.Item)
+		}
+	}
+	cp.Grades = delta.CloneMap(e.Grades)
+	cp.History = delta.CloneSlice(e.History)
+	cp.Locks = delta.CloneMap(e.Locks)
+	return &cp
+}
+
+func (e *Stash) Delta(o delta.Entity) delta.Delta {
+	if o == nil {
+		return nil
+	}
+	other, ok := o.(*Stash)
+	if !ok {
+		return nil // or panic
+	}
+	return e.Diff(other)
+}
+
+// Diff returns the changes that turn other into e, or all of e when other is
+// nil
+func (e *Stash) Diff(other *Stash) *StashDelta {
+	if other == nil {
+		other = &Stash{}
+	}
+	d := &StashDelta{}
+	if e.ID != other.ID {
+		v := e.ID
+		d.ID = &v
+	}
+	if e.Owner != other.Owner {
+		v := e.Owner
+		d.Owner = &v
+	}
+	d.Items = delta.DiffSlice[inventory.Item, *inventory.Item, int64, *inventory.ItemDelta](e.Items, other.Items)
+	d.Slots = delta.DiffMap[string, inventory.Item, *inventory.Item, int64, *inventory.ItemDelta](e.Slots, other.Slots)
+	if e.Best != other.Best {
+		v := e.Best
+		d.Best = &v
+	}
+	if e.Grade != other.Grade {
+		v := e.Grade
+		d.Grade = &v
+	}
+	if !delta.MapsEqual(e.Grades, other.Grades) {
+		v := delta.CloneMap(e.Grades)
+		d.Grades = &v
+	}
+	if !delta.SlicesEqual(e.History, other.History) {
+		v := delta.CloneSlice(e.History)
+		d.History = &v
+	}
+	d.Locks = delta.DiffSet(e.Locks, other.Locks, true)
+	if e.Cooldown != other.Cooldown {
+		v := e.Cooldown
+		d.Cooldown = &v
+	}
+	return d
+}
+
+func (e *Stash) ApplyDelta(d delta.Delta) {
+	if d == nil {
+		return
+	}
+	dt, ok := d.(*StashDelta)
+	if !ok {
+		return // or panic
+	}
+	dt.ApplyTo(e)
+}
+
+// ApplyDeltaChecked is like ApplyDelta, but reports a delta of another type or
+// one that fails validation instead of ignoring it
+func (e *Stash) ApplyDeltaChecked(d delta.Delta) error {
+	if d == nil {
+		return nil
+	}
+	dt, ok := d.(*StashDelta)
+	if !ok {
+		return delta.TypeMismatch("Stash", d)
+	}
+	return dt.ApplyToChecked(e)
+}
+
+// Apply applies d to e if it passes validation
+func (e *Stash) Apply(d *StashDelta) error {
+	if d == nil {
+		return nil
+	}
+	return d.ApplyToChecked(e)
+}
+
+var _ delta.ValidatingDelta = (*StashDelta)(nil)
+
+type StashDelta struct {
+	ID       *int64
+	Owner    *int64
+	Items    *delta.CollectionDelta[int64, *inventory.ItemDelta]
+	Slots    *delta.CollectionDelta[string, *inventory.ItemDelta]
+	Best     *inventory.Rarity
+	Grade    *Grade
+	Grades   *map[inventory.Rarity]Grade
+	History  *[]Grade
+	Locks    *delta.SetDelta[string]
+	Cooldown *time.Duration
+}
+
+// IsEmpty returns true if the delta carries no changes
+func (d *StashDelta) IsEmpty() bool {
+	return d.ID == nil && d.Owner == nil && d.Items == nil && d.Slots == nil && d.Best == nil && d.Grade == nil && d.Grades == nil && d.History == nil && d.Locks == nil && d.Cooldown == nil
+}
+
+// Validate returns a *delta.ApplyError if d cannot be applied to e, because e
+// has another type or a field would leave the bounds of its tags, without
+// changing e. Deltas do not carry the ID of the entity they were computed for,
+// so a full state applied to another entity is only reported by delta.Route.
+func (d *StashDelta) Validate(e delta.Entity) error {
+	et, ok := e.(*Stash)
+	if !ok {
+		return delta.TypeMismatch("Stash", e)
+	}
+	if err := delta.ValidateSlice[inventory.Item, *inventory.Item, int64, *inventory.ItemDelta]("Stash", "Items", et.Items, d.Items); err != nil {
+		return err
+	}
+	if err := delta.ValidateMap[string, inventory.Item, *inventory.Item, int64]("Stash", "Slots", et.Slots, d.Slots); err != nil {
+		return err
+	}
+	if d.Grade != nil {
+		if err := delta.CheckMin("Stash", "Grade", *d.Grade, 0); err != nil {
+			return err
+		}
+		if err := delta.CheckMax("Stash", "Grade", *d.Grade, 10); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyToChecked applies d to e only if it passes Validate, so either all of d
+// is applied or none of it
+func (d *StashDelta) ApplyToChecked(e delta.Entity) error {
+	if err := d.Validate(e); err != nil {
+		return err
+	}
+	d.ApplyTo(e)
+	return nil
+}
+
+func (d *StashDelta) ApplyTo(e delta.Entity) {
+	et, ok := e.(*Stash)
+	if !ok {
+		return // or panic
+	}
+	if d.ID != nil {
+		et.ID = *d.ID
+	}
+	if d.Owner != nil {
+		et.Owner = *d.Owner
+	}
+	if d.Items != nil {
+		delta.ApplySlice(&et.Items, d.Items)
+	}
+	if d.Slots != nil {
+		delta.ApplyMap[string, inventory.Item, *inventory.Item, int64](&et.Slots, d.Slots)
+	}
+	if d.Best != nil {
+		et.Best = *d.Best
+	}
+	if d.Grade != nil {
+		et.Grade = *d.Grade
+	}
+	if d.Grades != nil {
+		et.Grades = delta.CloneMap(*d.Grades)
+	}
+	if d.History != nil {
+		et.History = delta.CloneSlice(*d.History)
+	}
+	if d.Locks != nil {
+		delta.ApplySet(&et.Locks, d.Locks, true)
+	}
+	if d.Cooldown != nil {
+		et.Cooldown = *d.Cooldown
+	}
+}
+
+func (d *StashDelta) Serialize(w io.Writer) error {
+	bw := delta.NewBinaryWriter(w)
+
+	// Write field presence bitmask
+	var fieldMask uint64
+	if d.ID != nil {
+		fieldMask |= 1 << 0
+	}
+	if d.Owner != nil {
+		fieldMask |= 1 << 1
+	}
+	if d.Items != nil {
+		fieldMask |= 1 << 2
+	}
+	if d.Slots != nil {
+		fieldMask |= 1 << 3
+	}
+	if d.Best != nil {
+		fieldMask |= 1 << 4
+	}
+	if d.Grade != nil {
+		fieldMask |= 1 << 5
+	}
+	if d.Grades != nil {
+		fieldMask |= 1 << 6
+	}
+	if d.History != nil {
+		fieldMask |= 1 << 7
+	}
+	if d.Locks != nil {
+		fieldMask |= 1 << 8
+	}
+	if d.Cooldown != nil {
+		fieldMask |= 1 << 9
+	}
+	if err := bw.WriteUint64(fieldMask); err != nil {
+		return err
+	}
+
+	// Write field values for present fields
+	if d.ID != nil {
+		// Serialize primitive
+		if err := bw.WriteInt64(*d.ID); err != nil {
+			return err
+		}
+	}
+	if d.Owner != nil {
+		// Serialize primitive
+		if err := bw.WriteInt64(*d.Owner); err != nil {
+			return err
+		}
+	}
+	if d.Items != nil {
+		// Serialize entity collection
+		if err := delta.WriteCollection(bw, d.Items, bw.WriteInt64); err != nil {
+			return err
+		}
+	}
+	if d.Slots != nil {
+		// Serialize entity collection
+		if err := delta.WriteCollection(bw, d.Slots, bw.WriteString); err != nil {
+			return err
+		}
+	}
+	if d.Best != nil {
+		// Serialize primitive
+		if err := bw.WriteUint8(uint8(*d.Best)); err != nil {
+			return err
+		}
+	}
+	if d.Grade != nil {
+		// Serialize primitive
+		if err := bw.WriteInt32(int32(*d.Grade)); err != nil {
+			return err
+		}
+	}
+	if d.Grades != nil {
+		// Serialize generic collection
+		if err := delta.WriteMap(bw, *d.Grades, func(v inventory.Rarity) error { return bw.WriteUint8(uint8(v)) }, func(v Grade) error { return bw.WriteInt32(int32(v)) }); err != nil {
+			return err
+		}
+	}
+	if d.History != nil {
+		// Serialize generic collection
+		if err := delta.WriteSlice(bw, *d.History, func(v Grade) error { return bw.WriteInt32(int32(v)) }); err != nil {
+			return err
+		}
+	}
+	if d.Locks != nil {
+		// Serialize set
+		if err := delta.WriteSet(bw, d.Locks, bw.WriteString); err != nil {
+			return err
+		}
+	}
+	if d.Cooldown != nil {
+		// Serialize primitive
+		if err := bw.WriteInt64(int64(*d.Cooldown)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *StashDelta) Deserialize(r io.Reader) error {
+	br := delta.NewBinaryReader(r)
+
+	// Read field presence bitmask
+	fieldMask, err := br.ReadFieldMask(10)
+	if err != nil {
+		return delta.WrapDecodeError("Stash", "", br.Offset(), err)
+	}
+
+	// Read field values for present fields
+	if fieldMask&(1<<0) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt64()
+		if err != nil {
+			return delta.WrapDecodeError("Stash", "ID", offset, err)
+		}
+		d.ID = &val
+	}
+	if fieldMask&(1<<1) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt64()
+		if err != nil {
+			return delta.WrapDecodeError("Stash", "Owner", offset, err)
+		}
+		d.Owner = &val
+	}
+	if fieldMask&(1<<2) != 0 {
+		offset := br.Offset()
+		// Deserialize entity collection
+		cd, err := delta.ReadCollection(br, br.ReadInt64, func() *inventory.ItemDelta { return &inventory.ItemDelta{} })
+		if err != nil {
+			return delta.WrapDecodeError("Stash", "Items", offset, err)
+		}
+		d.Items = cd
+	}
+	if fieldMask&(1<<3) != 0 {
+		offset := br.Offset()
+		// Deserialize entity collection
+		cd, err := delta.ReadCollection(br, br.ReadString, func() *inventory.ItemDelta { return &inventory.ItemDelta{} })
+		if err != nil {
+			return delta.WrapDecodeError("Stash", "Slots", offset, err)
+		}
+		d.Slots = cd
+	}
+	if fieldMask&(1<<4) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadUint8()
+		if err != nil {
+			return delta.WrapDecodeError("Stash", "Best", offset, err)
+		}
+		v := inventory.Rarity(val)
+		d.Best = &v
+	}
+	if fieldMask&(1<<5) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt32()
+		if err != nil {
+			return delta.WrapDecodeError("Stash", "Grade", offset, err)
+		}
+		v := Grade(val)
+		d.Grade = &v
+	}
+	if fieldMask&(1<<6) != 0 {
+		offset := br.Offset()
+		// Deserialize generic collection
+		val, err := delta.ReadMap(br, func() (inventory.Rarity, error) { v, err := br.ReadUint8(); return inventory.Rarity(v), err }, func() (Grade, error) { v, err := br.ReadInt32(); return Grade(v), err })
+		if err != nil {
+			return delta.WrapDecodeError("Stash", "Grades", offset, err)
+		}
+		d.Grades = &val
+	}
+	if fieldMask&(1<<7) != 0 {
+		offset := br.Offset()
+		// Deserialize generic collection
+		val, err := delta.ReadSlice(br, func() (Grade, error) { v, err := br.ReadInt32(); return Grade(v), err })
+		if err != nil {
+			return delta.WrapDecodeError("Stash", "History", offset, err)
+		}
+		d.History = &val
+	}
+	if fieldMask&(1<<8) != 0 {
+		offset := br.Offset()
+		// Deserialize set
+		sd, err := delta.ReadSet(br, br.ReadString)
+		if err != nil {
+			return delta.WrapDecodeError("Stash", "Locks", offset, err)
+		}
+		d.Locks = sd
+	}
+	if fieldMask&(1<<9) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt64()
+		if err != nil {
+			return delta.WrapDecodeError("Stash", "Cooldown", offset, err)
+		}
+		v := time.Duration(val)
+		d.Cooldown = &v
+	}
+
+	return nil
+}
+
+func (e *Stats[T]) GetID() int64 {
+	return e.ID
+}
+
+func (e *Stats[T]) Clone() delta.Entity {
+	cp := *e
+	cp.History = delta.CloneSlice(e.History)
+	cp.ByName = delta.CloneMap(e.ByName)
+	cp.Counts = delta.CloneMap(e.Counts)
+	if e.Bonus != nil {
+		cp.Bonus = make(map[string]int32)
+		for k, v := range e.Bonus {
+			cp.Bonus[k] = v
+		}
+	}
+	return &cp
+}
+
+func (e *Stats[T]) Delta(o delta.Entity) delta.Delta {
+	if o == nil {
+		return nil
+	}
+	other, ok := o.(*Stats[T])
+	if !ok {
+		return nil // or panic
+	}
+	return e.Diff(other)
+}
+
+// Diff returns the changes that turn other into e, or all of e when other is
+// nil
+func (e *Stats[T]) Diff(other *Stats[T]) *StatsDelta[T] {
+	if other == nil {
+		other = &Stats[T]{}
+	}
+	d := &StatsDelta[T]{}
+	if e.ID != other.ID {
+		v := e.ID
+		d.ID = &v
+	}
+	if e.Current != other.Current {
+		v := e.Current
+		d.Current = &v
+	}
+	if e.Max != other.Max {
+		v := e.Max
+		d.Max = &v
+	}
+	if !delta.SlicesEqual(e.History, other.History) {
+		v := delta.CloneSlice(e.History)
+		d.History = &v
+	}
+	if !delta.MapsEqual(e.ByName, other.ByName) {
+		v := delta.CloneMap(e.ByName)
+		d.ByName = &v
+	}
+	if !delta.MapsEqual(e.Counts, other.Counts) {
+		v := delta.CloneMap(e.Counts)
+		d.Counts = &v
+	}
+	if !delta.MapsEqual(e.Bonus, other.Bonus) {
+		if e.Bonus != nil {
+			v := make(map[string]int32)
+			for k, val := range e.Bonus {
+				v[k] = val
+			}
+			d.Bonus = &v
+		} else {
+			v := make(map[string]int32)
+			d.Bonus = &v
+		}
+	}
+	return d
+}
+
+func (e *Stats[T]) ApplyDelta(d delta.Delta) {
+	if d == nil {
+		return
+	}
+	dt, ok := d.(*StatsDelta[T])
+	if !ok {
+		return // or panic
+	}
+	dt.ApplyTo(e)
+}
+
+// ApplyDeltaChecked is like ApplyDelta, but reports a delta of another type or
+// one that fails validation instead of ignoring it
+func (e *Stats[T]) ApplyDeltaChecked(d delta.Delta) error {
+	if d == nil {
+		return nil
+	}
+	dt, ok := d.(*StatsDelta[T])
+	if !ok {
+		return delta.TypeMismatch("Stats", d)
+	}
+	return dt.ApplyToChecked(e)
+}
+
+// Apply applies d to e if it passes validation
+func (e *Stats[T]) Apply(d *StatsDelta[T]) error {
+	if d == nil {
+		return nil
+	}
+	return d.ApplyToChecked(e)
+}
+
+type StatsDelta[T delta.Primitive] struct {
+	ID      *int64
+	Current *T
+	Max     *T
+	History *[]T
+	ByName  *map[string]T
+	Counts  *map[T]int32
+	Bonus   *map[string]int32
+}
+
+// IsEmpty returns true if the delta carries no changes
+func (d *StatsDelta[T]) IsEmpty() bool {
+	return d.ID == nil && d.Current == nil && d.Max == nil && d.History == nil && d.ByName == nil && d.Counts == nil && d.Bonus == nil
+}
+
+// Validate returns a *delta.ApplyError if d cannot be applied to e, because e
+// has another type or a field would leave the bounds of its tags, without
+// changing e. Deltas do not carry the ID of the entity they were computed for,
+// so a full state applied to another entity is only reported by delta.Route.
+func (d *StatsDelta[T]) Validate(e delta.Entity) error {
+	_, ok := e.(*Stats[T])
+	if !ok {
+		return delta.TypeMismatch("Stats", e)
+	}
+	return nil
+}
+
+// ApplyToChecked applies d to e only if it passes Validate, so either all of d
+// is applied or none of it
+func (d *StatsDelta[T]) ApplyToChecked(e delta.Entity) error {
+	if err := d.Validate(e); err != nil {
+		return err
+	}
+	d.ApplyTo(e)
+	return nil
+}
+
+func (d *StatsDelta[T]) ApplyTo(e delta.Entity) {
+	et, ok := e.(*Stats[T])
+	if !ok {
+		return // or panic
+	}
+	if d.ID != nil {
+		et.ID = *d.ID
+	}
+	if d.Current != nil {
+		et.Current = *d.Current
+	}
+	if d.Max != nil {
+		et.Max = *d.Max
+	}
+	if d.History != nil {
+		et.History = delta.CloneSlice(*d.History)
+	}
+	if d.ByName != nil {
+		et.ByName = delta.CloneMap(*d.ByName)
+	}
+	if d.Counts != nil {
+		et.Counts = delta.CloneMap(*d.Counts)
+	}
+	if d.Bonus != nil {
+		if *d.Bonus != nil {
+			et.Bonus = make(map[string]int32)
+			for k, v := range *d.Bonus {
+				et.Bonus[k] = v
+			}
+		} else {
+			et.Bonus = nil
+		}
+	}
+}
+
+func (d *StatsDelta[T]) Serialize(w io.Writer) error {
+	bw := delta.NewBinaryWriter(w)
+
+	// Write field presence bitmask
+	var fieldMask uint64
+	if d.ID != nil {
+		fieldMask |= 1 << 0
+	}
+	if d.Current != nil {
+		fieldMask |= 1 << 1
+	}
+	if d.Max != nil {
+		fieldMask |= 1 << 2
+	}
+	if d.History != nil {
+		fieldMask |= 1 << 3
+	}
+	if d.ByName != nil {
+		fieldMask |= 1 << 4
+	}
+	if d.Counts != nil {
+		fieldMask |= 1 << 5
+	}
+	if d.Bonus != nil {
+		fieldMask |= 1 << 6
+	}
+	if err := bw.WriteUint64(fieldMask); err != nil {
+		return err
+	}
+
+	// Write field values for present fields
+	if d.ID != nil {
+		// Serialize primitive
+		if err := bw.WriteInt64(*d.ID); err != nil {
+			return err
+		}
+	}
+	if d.Current != nil {
+		// Serialize type parameter
+		if err := delta.WriteValue(bw, *d.Current); err != nil {
+			return err
+		}
+	}
+	if d.Max != nil {
+		// Serialize type parameter
+		if err := delta.WriteValue(bw, *d.Max); err != nil {
+			return err
+		}
+	}
+	if d.History != nil {
+		// Serialize generic collection
+		if err := delta.WriteSlice(bw, *d.History, func(v T) error { return delta.WriteValue(bw, v) }); err != nil {
+			return err
+		}
+	}
+	if d.ByName != nil {
+		// Serialize generic collection
+		if err := delta.WriteMap(bw, *d.ByName, bw.WriteString, func(v T) error { return delta.WriteValue(bw, v) }); err != nil {
+			return err
+		}
+	}
+	if d.Counts != nil {
+		// Serialize generic collection
+		if err := delta.WriteMap(bw, *d.Counts, func(v T) error { return delta.WriteValue(bw, v) }, bw.WriteInt32); err != nil {
+			return err
+		}
+	}
+	if d.Bonus != nil {
+		// Serialize map
+		if err := bw.WriteVarUint32(uint32(len(*d.Bonus))); err != nil {
+			return err
+		}
+		for _, k := range delta.SortedKeys(*d.Bonus) {
+			v := (*d.Bonus)[k]
+			if err := bw.WriteString(k); err != nil {
+				return err
+			}
+			if err := bw.WriteInt32(v); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (d *StatsDelta[T]) Deserialize(r io.Reader) error {
+	br := delta.NewBinaryReader(r)
+
+	// Read field presence bitmask
+	fieldMask, err := br.ReadFieldMask(7)
+	if err != nil {
+		return delta.WrapDecodeError("Stats", "", br.Offset(), err)
+	}
+
+	// Read field values for present fields
+	if fieldMask&(1<<0) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt64()
+		if err != nil {
+			return delta.WrapDecodeError("Stats", "ID", offset, err)
+		}
+		d.ID = &val
+	}
+	if fieldMask&(1<<1) != 0 {
+		offset := br.Offset()
+		// Deserialize type parameter
+		val, err := delta.ReadValue[T](br)
+		if err != nil {
+			return delta.WrapDecodeError("Stats", "Current", offset, err)
+		}
+		d.Current = &val
+	}
+	if fieldMask&(1<<2) != 0 {
+		offset := br.Offset()
+		// Deserialize type parameter
+		val, err := delta.ReadValue[T](br)
+		if err != nil {
+			return delta.WrapDecodeError("Stats", "Max", offset, err)
+		}
+		d.Max = &val
+	}
+	if fieldMask&(1<<3) != 0 {
+		offset := br.Offset()
+		// Deserialize generic collection
+		val, err := delta.ReadSlice(br, func() (T, error) { return delta.ReadValue[T](br) })
+		if err != nil {
+			return delta.WrapDecodeError("Stats", "History", offset, err)
+		}
+		d.History = &val
+	}
+	if fieldMask&(1<<4) != 0 {
+		offset := br.Offset()
+		// Deserialize generic collection
+		val, err := delta.ReadMap(br, br.ReadString, func() (T, error) { return delta.ReadValue[T](br) })
+		if err != nil {
+			return delta.WrapDecodeError("Stats", "ByName", offset, err)
+		}
+		d.ByName = &val
+	}
+	if fieldMask&(1<<5) != 0 {
+		offset := br.Offset()
+		// Deserialize generic collection
+		val, err := delta.ReadMap(br, func() (T, error) { return delta.ReadValue[T](br) }, br.ReadInt32)
+		if err != nil {
+			return delta.WrapDecodeError("Stats", "Counts", offset, err)
+		}
+		d.Counts = &val
+	}
+	if fieldMask&(1<<6) != 0 {
+		offset := br.Offset()
+		// Deserialize map
+		length, err := br.ReadVarUint32()
+		if err != nil {
+			return delta.WrapDecodeError("Stats", "Bonus", offset, err)
+		}
+		m := make(map[string]int32)
+		for i := uint32(0); i < length; i++ {
+			k, err := br.ReadString()
+			if err != nil {
+				return delta.WrapDecodeError("Stats", "Bonus", offset, err)
+			}
+			v, err := br.ReadInt32()
+			if err != nil {
+				return delta.WrapDecodeError("Stats", "Bonus", offset, err)
+			}
+			m[k] = v
+		}
+		d.Bonus = &m
+	}
+
+	return nil
+}
+
+var (
+	_ delta.EntityOf[int64]                       = (*Transform)(nil)
+	_ delta.Diffable[*Transform, *TransformDelta] = (*Transform)(nil)
+	_ delta.CheckedEntity                         = (*Transform)(nil)
+)
+
+func (e *Transform) GetID() int64 {
+	return e.ID
+}
+
+func (e *Transform) Clone() delta.Entity {
+	cp := *e
+	cp.Position = delta.MarshalerCodec[Vec3]().Clone(e.Position)
+	cp.Velocity = delta.MarshalerCodec[Vec3]().Clone(e.Velocity)
+	cp.Updated = delta.MustCodec[time.Time]("time").Clone(e.Updated)
+	return &cp
+}
+
+func (e *Transform) Delta(o delta.Entity) delta.Delta {
+	if o == nil {
+		return nil
+	}
+	other, ok := o.(*Transform)
+	if !ok {
+		return nil // or panic
+	}
+	return e.Diff(other)
+}
+
+// Diff returns the changes that turn other into e, or all of e when other is
+// nil
+func (e *Transform) Diff(other *Transform) *TransformDelta {
+	if other == nil {
+		other = &Transform{}
+	}
+	d := &TransformDelta{}
+	if e.ID != other.ID {
+		v := e.ID
+		d.ID = &v
+	}
+	if !delta.MarshalerCodec[Vec3]().Equal(e.Position, other.Position) {
+		v := delta.MarshalerCodec[Vec3]().Clone(e.Position)
+		d.Position = &v
+	}
+	if !delta.MarshalerCodec[Vec3]().Equal(e.Velocity, other.Velocity) {
+		v := delta.MarshalerCodec[Vec3]().Clone(e.Velocity)
+		d.Velocity = &v
+	}
+	if !delta.MustCodec[time.Time]("time").Equal(e.Updated, other.Updated) {
+		v := delta.MustCodec[time.Time]("time").Clone(e.Updated)
+		d.Updated = &v
+	}
+	return d
+}
+
+func (e *Transform) ApplyDelta(d delta.Delta) {
+	if d == nil {
+		return
+	}
+	dt, ok := d.(*TransformDelta)
+	if !ok {
+		return // or panic
+	}
+	dt.ApplyTo(e)
+}
+
+// ApplyDeltaChecked is like ApplyDelta, but reports a delta of another type or
+// one that fails validation instead of ignoring it
+func (e *Transform) ApplyDeltaChecked(d delta.Delta) error {
+	if d == nil {
+		return nil
+	}
+	dt, ok := d.(*TransformDelta)
+	if !ok {
+		return delta.TypeMismatch("Transform", d)
+	}
+	return dt.ApplyToChecked(e)
+}
+
+// Apply applies d to e if it passes validation
+func (e *Transform) Apply(d *TransformDelta) error {
+	if d == nil {
+		return nil
+	}
+	return d.ApplyToChecked(e)
+}
+
+var _ delta.ValidatingDelta = (*TransformDelta)(nil)
+
+type TransformDelta struct {
+	ID       *int64
+	Position *Vec3
+	Velocity *Vec3
+	Updated  *time.Time
+}
+
+// IsEmpty returns true if the delta carries no changes
+func (d *TransformDelta) IsEmpty() bool {
+	return d.ID == nil && d.Position == nil && d.Velocity == nil && d.Updated == nil
+}
+
+// Validate returns a *delta.ApplyError if d cannot be applied to e, because e
+// has another type or a field would leave the bounds of its tags, without
+// changing e. Deltas do not carry the ID of the entity they were computed for,
+// so a full state applied to another entity is only reported by delta.Route.
+func (d *TransformDelta) Validate(e delta.Entity) error {
+	_, ok := e.(*Transform)
+	if !ok {
+		return delta.TypeMismatch("Transform", e)
+	}
+	return nil
+}
+
+// ApplyToChecked applies d to e only if it passes Validate, so either all of d
+// is applied or none of it
+func (d *TransformDelta) ApplyToChecked(e delta.Entity) error {
+	if err := d.Validate(e); err != nil {
+		return err
+	}
+	d.ApplyTo(e)
+	return nil
+}
+
+func (d *TransformDelta) ApplyTo(e delta.Entity) {
+	et, ok := e.(*Transform)
+	if !ok {
+		return // or panic
+	}
+	if d.ID != nil {
+		et.ID = *d.ID
+	}
+	if d.Position != nil {
+		et.Position = delta.MarshalerCodec[Vec3]().Clone(*d.Position)
+	}
+	if d.Velocity != nil {
+		et.Velocity = delta.MarshalerCodec[Vec3]().Clone(*d.Velocity)
+	}
+	if d.Updated != nil {
+		et.Updated = delta.MustCodec[time.Time]("time").Clone(*d.Updated)
+	}
+}
+
+func (d *TransformDelta) Serialize(w io.Writer) error {
+	bw := delta.NewBinaryWriter(w)
+
+	// Write field presence bitmask
+	var fieldMask uint64
+	if d.ID != nil {
+		fieldMask |= 1 << 0
+	}
+	if d.Position != nil {
+		fieldMask |= 1 << 1
+	}
+	if d.Velocity != nil {
+		fieldMask |= 1 << 2
+	}
+	if d.Updated != nil {
+		fieldMask |= 1 << 3
+	}
+	if err := bw.WriteUint64(fieldMask); err != nil {
+		return err
+	}
+
+	// Write field values for present fields
+	if d.ID != nil {
+		// Serialize primitive
+		if err := bw.WriteInt64(*d.ID); err != nil {
+			return err
+		}
+	}
+	if d.Position != nil {
+		// Serialize with codec
+		if err := delta.MarshalerCodec[Vec3]().Encode(bw, *d.Position); err != nil {
+			return err
+		}
+	}
+	if d.Velocity != nil {
+		// Serialize with codec
+		if err := delta.MarshalerCodec[Vec3]().Encode(bw, *d.Velocity); err != nil {
+			return err
+		}
+	}
+	if d.Updated != nil {
+		// Serialize with codec
+		if err := delta.MustCodec[time.Time]("time").Encode(bw, *d.Updated); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *TransformDelta) Deserialize(r io.Reader) error {
+	br := delta.NewBinaryReader(r)
+
+	// Read field presence bitmask
+	fieldMask, err := br.ReadFieldMask(4)
+	if err != nil {
+		return delta.WrapDecodeError("Transform", "", br.Offset(), err)
+	}
+
+	// Read field values for present fields
+	if fieldMask&(1<<0) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt64()
+		if err != nil {
+			return delta.WrapDecodeError("Transform", "ID", offset, err)
+		}
+		d.ID = &val
+	}
+	if fieldMask&(1<<1) != 0 {
+		offset := br.Offset()
+		// Deserialize with codec
+		val, err := delta.MarshalerCodec[Vec3]().Decode(br)
+		if err != nil {
+			return delta.WrapDecodeError("Transform", "Position", offset, err)
+		}
+		d.Position = &val
+	}
+	if fieldMask&(1<<2) != 0 {
+		offset := br.Offset()
+		// Deserialize with codec
+		val, err := delta.MarshalerCodec[Vec3]().Decode(br)
+		if err != nil {
+			return delta.WrapDecodeError("Transform", "Velocity", offset, err)
+		}
+		d.Velocity = &val
+	}
+	if fieldMask&(1<<3) != 0 {
+		offset := br.Offset()
+		// Deserialize with codec
+		val, err := delta.MustCodec[time.Time]("time").Decode(br)
+		if err != nil {
+			return delta.WrapDecodeError("Transform", "Updated", offset, err)
+		}
+		d.Updated = &val
+	}
+
+	return nil
+}
+
+var (
+	_ delta.EntityOf[int64]               = (*Sword)(nil)
+	_ delta.Diffable[*Sword, *SwordDelta] = (*Sword)(nil)
+	_ delta.CheckedEntity                 = (*Sword)(nil)
+)
+
+func init() {
+	delta.RegisterType(1, func() delta.Entity { return &Sword{} }, func() delta.Delta { return &SwordDelta{} })
+}
+
+func (e *Sword) GetID() int64 {
+	return e.ID
+}
+
+func (e *Sword) Clone() delta.Entity {
+	cp := *e
+	return &cp
+}
+
+func (e *Sword) Delta(o delta.Entity) delta.Delta {
+	if o == nil {
+		return nil
+	}
+	other, ok := o.(*Sword)
+	if !ok {
+		return nil // or panic
+	}
+	return e.Diff(other)
+}
+
+// Diff returns the changes that turn other into e, or all of e when other is
+// nil
+func (e *Sword) Diff(other *Sword) *SwordDelta {
+	if other == nil {
+		other = &Sword{}
+	}
+	d := &SwordDelta{}
+	if e.ID != other.ID {
+		v := e.ID
+		d.ID = &v
+	}
+	if e.Sharpness != other.Sharpness {
+		v := e.Sharpness
+		d.Sharpness = &v
+	}
+	return d
+}
+
+func (e *Sword) ApplyDelta(d delta.Delta) {
+	if d == nil {
+		return
+	}
+	dt, ok := d.(*SwordDelta)
+	if !ok {
+		return // or panic
+	}
+	dt.ApplyTo(e)
+}
+
+// ApplyDeltaChecked is like ApplyDelta, but reports a delta of another type or
+// one that fails validation instead of ignoring it
+func (e *Sword) ApplyDeltaChecked(d delta.Delta) error {
+	if d == nil {
+		return nil
+	}
+	dt, ok := d.(*SwordDelta)
+	if !ok {
+		return delta.TypeMismatch("Sword", d)
+	}
+	return dt.ApplyToChecked(e)
+}
+
+// Apply applies d to e if it passes validation
+func (e *Sword) Apply(d *SwordDelta) error {
+	if d == nil {
+		return nil
+	}
+	return d.ApplyToChecked(e)
+}
+
+var _ delta.ValidatingDelta = (*SwordDelta)(nil)
+
+type SwordDelta struct {
+	ID        *int64
+	Sharpness *int32
+}
+
+// IsEmpty returns true if the delta carries no changes
+func (d *SwordDelta) IsEmpty() bool {
+	return d.ID == nil && d.Sharpness == nil
+}
+
+// Validate returns a *delta.ApplyError if d cannot be applied to e, because e
+// has another type or a field would leave the bounds of its tags, without
+// changing e. Deltas do not carry the ID of the entity they were computed for,
+// so a full state applied to another entity is only reported by delta.Route.
+func (d *SwordDelta) Validate(e delta.Entity) error {
+	_, ok := e.(*Sword)
+	if !ok {
+		return delta.TypeMismatch("Sword", e)
+	}
+	return nil
+}
+
+// ApplyToChecked applies d to e only if it passes Validate, so either all of d
+// is applied or none of it
+func (d *SwordDelta) ApplyToChecked(e delta.Entity) error {
+	if err := d.Validate(e); err != nil {
+		return err
+	}
+	d.ApplyTo(e)
+	return nil
+}
+
+func (d *SwordDelta) ApplyTo(e delta.Entity) {
+	et, ok := e.(*Sword)
+	if !ok {
+		return // or panic
+	}
+	if d.ID != nil {
+		et.ID = *d.ID
+	}
+	if d.Sharpness != nil {
+		et.Sharpness = *d.Sharpness
+	}
+}
+
+func (d *SwordDelta) Serialize(w io.Writer) error {
+	bw := delta.NewBinaryWriter(w)
+
+	// Write field presence bitmask
+	var fieldMask uint64
+	if d.ID != nil {
+		fieldMask |= 1 << 0
+	}
+	if d.Sharpness != nil {
+		fieldMask |= 1 << 1
+	}
+	if err := bw.WriteUint64(fieldMask); err != nil {
+		return err
+	}
+
+	// Write field values for present fields
+	if d.ID != nil {
+		// Serialize primitive
+		if err := bw.WriteInt64(*d.ID); err != nil {
+			return err
+		}
+	}
+	if d.Sharpness != nil {
+		// Serialize primitive
+		if err := bw.WriteInt32(*d.Sharpness); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *SwordDelta) Deserialize(r io.Reader) error {
+	br := delta.NewBinaryReader(r)
+
+	// Read field presence bitmask
+	fieldMask, err := br.ReadFieldMask(2)
+	if err != nil {
+		return delta.WrapDecodeError("Sword", "", br.Offset(), err)
+	}
+
+	// Read field values for present fields
+	if fieldMask&(1<<0) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt64()
+		if err != nil {
+			return delta.WrapDecodeError("Sword", "ID", offset, err)
+		}
+		d.ID = &val
+	}
+	if fieldMask&(1<<1) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt32()
+		if err != nil {
+			return delta.WrapDecodeError("Sword", "Sharpness", offset, err)
+		}
+		d.Sharpness = &val
+	}
+
+	return nil
+}
+
+var (
+	_ delta.EntityOf[int64]           = (*Bow)(nil)
+	_ delta.Diffable[*Bow, *BowDelta] = (*Bow)(nil)
+	_ delta.CheckedEntity             = (*Bow)(nil)
+)
+
+func init() {
+	delta.RegisterType(2, func() delta.Entity { return &Bow{} }, func() delta.Delta { return &BowDelta{} })
+}
+
+func (e *Bow) GetID() int64 {
+	return e.ID
+}
+
+func (e *Bow) Clone() delta.Entity {
+	cp := *e
+	return &cp
+}
+
+func (e *Bow) Delta(o delta.Entity) delta.Delta {
+	if o == nil {
+		return nil
+	}
+	other, ok := o.(*Bow)
+	if !ok {
+		return nil // or panic
+	}
+	return e.Diff(other)
+}
+
+// Diff returns the changes that turn other into e, or all of e when other is
+// nil
+func (e *Bow) Diff(other *Bow) *BowDelta {
+	if other == nil {
+		other = &Bow{}
+	}
+	d := &BowDelta{}
+	if e.ID != other.ID {
+		v := e.ID
+		d.ID = &v
+	}
+	if e.Arrows != other.Arrows {
+		v := e.Arrows
+		d.Arrows = &v
+	}
+	if e.Range != other.Range {
+		v := e.Range
+		d.Range = &v
+	}
+	return d
+}
+
+func (e *Bow) ApplyDelta(d delta.Delta) {
+	if d == nil {
+		return
+	}
+	dt, ok := d.(*BowDelta)
+	if !ok {
+		return // or panic
+	}
+	dt.ApplyTo(e)
+}
+
+// ApplyDeltaChecked is like ApplyDelta, but reports a delta of another type or
+// one that fails validation instead of ignoring it
+func (e *Bow) ApplyDeltaChecked(d delta.Delta) error {
+	if d == nil {
+		return nil
+	}
+	dt, ok := d.(*BowDelta)
+	if !ok {
+		return delta.TypeMismatch("Bow", d)
+	}
+	return dt.ApplyToChecked(e)
+}
+
+// Apply applies d to e if it passes validation
+func (e *Bow) Apply(d *BowDelta) error {
+	if d == nil {
+		return nil
+	}
+	return d.ApplyToChecked(e)
+}
+
+var _ delta.ValidatingDelta = (*BowDelta)(nil)
+
+type BowDelta struct {
+	ID     *int64
+	Arrows *int32
+	Range  *float32
+}
+
+// IsEmpty returns true if the delta carries no changes
+func (d *BowDelta) IsEmpty() bool {
+	return d.ID == nil && d.Arrows == nil && d.Range == nil
+}
+
+// Validate returns a *delta.ApplyError if d cannot be applied to e, because e
+// has another type or a field would leave the bounds of its tags, without
+// changing e. Deltas do not carry the ID of the entity they were computed for,
+// so a full state applied to another entity is only reported by delta.Route.
+func (d *BowDelta) Validate(e delta.Entity) error {
+	_, ok := e.(*Bow)
+	if !ok {
+		return delta.TypeMismatch("Bow", e)
+	}
+	return nil
+}
+
+// ApplyToChecked applies d to e only if it passes Validate, so either all of d
+// is applied or none of it
+func (d *BowDelta) ApplyToChecked(e delta.Entity) error {
+	if err := d.Validate(e); err != nil {
+		return err
+	}
+	d.ApplyTo(e)
+	return nil
+}
+
+func (d *BowDelta) ApplyTo(e delta.Entity) {
+	et, ok := e.(*Bow)
+	if !ok {
+		return // or panic
+	}
+	if d.ID != nil {
+		et.ID = *d.ID
+	}
+	if d.Arrows != nil {
+		et.Arrows = *d.Arrows
+	}
+	if d.Range != nil {
+		et.Range = *d.Range
+	}
+}
+
+func (d *BowDelta) Serialize(w io.Writer) error {
+	bw := delta.NewBinaryWriter(w)
+
+	// Write field presence bitmask
+	var fieldMask uint64
+	if d.ID != nil {
+		fieldMask |= 1 << 0
+	}
+	if d.Arrows != nil {
+		fieldMask |= 1 << 1
+	}
+	if d.Range != nil {
+		fieldMask |= 1 << 2
+	}
+	if err := bw.WriteUint64(fieldMask); err != nil {
+		return err
+	}
+
+	// Write field values for present fields
+	if d.ID != nil {
+		// Serialize primitive
+		if err := bw.WriteInt64(*d.ID); err != nil {
+			return err
+		}
+	}
+	if d.Arrows != nil {
+		// Serialize primitive
+		if err := bw.WriteInt32(*d.Arrows); err != nil {
+			return err
+		}
+	}
+	if d.Range != nil {
+		// Serialize primitive
+		if err := bw.WriteFloat32(*d.Range); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *BowDelta) Deserialize(r io.Reader) error {
+	br := delta.NewBinaryReader(r)
+
+	// Read field presence bitmask
+	fieldMask, err := br.ReadFieldMask(3)
+	if err != nil {
+		return delta.WrapDecodeError("Bow", "", br.Offset(), err)
+	}
+
+	// Read field values for present fields
+	if fieldMask&(1<<0) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt64()
+		if err != nil {
+			return delta.WrapDecodeError("Bow", "ID", offset, err)
+		}
+		d.ID = &val
+	}
+	if fieldMask&(1<<1) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt32()
+		if err != nil {
+			return delta.WrapDecodeError("Bow", "Arrows", offset, err)
+		}
+		d.Arrows = &val
+	}
+	if fieldMask&(1<<2) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadFloat32()
+		if err != nil {
+			return delta.WrapDecodeError("Bow", "Range", offset, err)
+		}
+		d.Range = &val
+	}
+
+	return nil
+}
@@ -1,5 +1,10 @@
 package example
 
+//go:generate go run github.com/cbodonnell/delta/cmd/deltagen
+//go:generate go run github.com/cbodonnell/delta/cmd/deltagen -input ./... -lang typescript -output ts
+//go:generate go run github.com/cbodonnell/delta/cmd/deltagen -input ./... -lang csharp -output cs
+//go:generate go run github.com/cbodonnell/delta/cmd/deltagen -input ./... -schema schema.json
+
 // delta:entity
 type GameState struct {
 	// Integer types
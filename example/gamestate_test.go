@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"reflect"
 	"testing"
+
+	"github.com/cbodonnell/delta"
 )
 
 func TestGameState_RoundTrip(t *testing.T) {
@@ -174,3 +176,40 @@ func TestGameStateDelta_SerializeDeserialize(t *testing.T) {
 		t.Errorf("Deserialized delta does not match original:\nOriginal: %+v\nDeserialized: %+v", delta, newDelta)
 	}
 }
+
+// syncTo brings target up to date with source through the typed API, without
+// any type assertions
+func syncTo[T delta.Diffable[T, D], D delta.Delta](target, source T) error {
+	return target.Apply(source.Diff(target))
+}
+
+func TestGameState_Diffable(t *testing.T) {
+	server := &GameState{ID: 1, Score: 10, Inventory: []string{"sword"}}
+	client := &GameState{ID: 1}
+
+	if err := syncTo[*GameState, *GameStateDelta](client, server); err != nil {
+		t.Fatalf("Apply() error: %v", err)
+	}
+	if !reflect.DeepEqual(client, server) {
+		t.Errorf("Apply(Diff()) = %+v, want %+v", client, server)
+	}
+
+	if d := server.Diff(client); !d.IsEmpty() {
+		t.Errorf("Diff() of equal states = %+v, want empty", d)
+	}
+	if d := server.Diff(nil); d.ID == nil || d.Score == nil || d.Inventory == nil || d.Lives != nil {
+		t.Errorf("Diff(nil) = %+v, want all non-zero fields", d)
+	}
+	if err := client.Apply(nil); err != nil {
+		t.Errorf("Apply(nil) error: %v", err)
+	}
+
+	// A change of ID is a change like any other
+	renamed := &GameState{ID: 2, Score: 10, Inventory: []string{"sword"}}
+	if err := syncTo[*GameState, *GameStateDelta](client, renamed); err != nil {
+		t.Fatalf("Apply() of an ID change error: %v", err)
+	}
+	if !reflect.DeepEqual(client, renamed) {
+		t.Errorf("Apply(Diff()) of an ID change = %+v, want %+v", client, renamed)
+	}
+}
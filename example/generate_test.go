@@ -0,0 +1,138 @@
+package example
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cbodonnell/delta/gen"
+)
+
+func TestGenerate_UpToDate(t *testing.T) {
+	files, err := gen.Generate(gen.Config{Input: "."})
+	if err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+	if len(files) != 1 || files[0].Path != "example_deltagen.go" {
+		t.Fatalf("Generate() returned %d files, want only example_deltagen.go", len(files))
+	}
+	compareGolden(t, files)
+}
+
+func TestGenerate_TypeScript(t *testing.T) {
+	files, err := gen.Generate(gen.Config{Input: "./...", Lang: "typescript", Output: "ts"})
+	if err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+
+	// Every golden module must still be generated
+	golden, err := filepath.Glob(filepath.Join("ts", "*.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != len(golden) {
+		t.Errorf("Generate() returned %d files, want the %d in ts/", len(files), len(golden))
+	}
+	compareGolden(t, files)
+}
+
+func TestGenerate_CSharp(t *testing.T) {
+	files, err := gen.Generate(gen.Config{Input: "./...", Lang: "csharp", Output: "cs"})
+	if err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+
+	// Every golden class must still be generated
+	golden, err := filepath.Glob(filepath.Join("cs", "*.cs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != len(golden) {
+		t.Errorf("Generate() returned %d files, want the %d in cs/", len(files), len(golden))
+	}
+	compareGolden(t, files)
+}
+
+func TestGenerate_Schema(t *testing.T) {
+	files, err := gen.Generate(gen.Config{Input: "./...", Schema: "schema.json"})
+	if err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+	if len(files) == 0 || files[len(files)-1].Path != "schema.json" {
+		t.Fatalf("Generate() did not return schema.json last")
+	}
+	compareGolden(t, files)
+}
+
+func TestDecodeDelta(t *testing.T) {
+	b, err := os.ReadFile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema gen.Schema
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	old := &Lobby{ID: 1, Name: "arena", Players: []Player{{BaseEntity: BaseEntity{ID: 1}, Weapon: &Sword{ID: 7}}}}
+	updated := &Lobby{ID: 1, Name: "final", Players: []Player{{BaseEntity: BaseEntity{ID: 1}, Weapon: &Bow{ID: 8, Arrows: 3}}}}
+	var buf bytes.Buffer
+	if err := updated.Delta(old).Serialize(&buf); err != nil {
+		t.Fatalf("Failed to serialize delta: %v", err)
+	}
+	data := buf.Bytes()
+
+	d, err := gen.DecodeDelta(schema, "Lobby", data)
+	if err != nil {
+		t.Fatalf("DecodeDelta() failed: %v", err)
+	}
+	if d.Size != len(data) || len(d.Fields) != 2 {
+		t.Fatalf("DecodeDelta() = %d bytes with %d fields, want %d bytes with Name and Players", d.Size, len(d.Fields), len(data))
+	}
+
+	// The mask is followed by Name, a varint length and the string
+	name := d.Fields[0]
+	if name.Name != "Name" || name.Offset != 8 || name.Size != 6 || name.Value != "final" {
+		t.Errorf("DecodeDelta() Name = %+v, want \"final\" at offset 8 size 6", name)
+	}
+	players, ok := d.Fields[1].Value.(*gen.CollectionValue)
+	if !ok || len(players.Changed) != 1 || players.Changed[0].Key != int64(1) {
+		t.Fatalf("DecodeDelta() Players = %#v, want player 1 changed", d.Fields[1].Value)
+	}
+	weapon, ok := players.Changed[0].Delta.Fields[0].Value.(*gen.InterfaceValue)
+	if !ok || weapon.TypeID != 2 || !weapon.Full || weapon.Delta.Entity != "Bow" {
+		t.Errorf("DecodeDelta() Weapon = %#v, want a full Bow delta", players.Changed[0].Delta.Fields[0].Value)
+	}
+	if end := d.Fields[1].Offset + d.Fields[1].Size; end != len(data) {
+		t.Errorf("DecodeDelta() Players ends at %d, want %d", end, len(data))
+	}
+
+	// Truncated deltas return the fields decoded so far
+	d, err = gen.DecodeDelta(schema, "Lobby", data[:len(data)-1])
+	if !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		t.Errorf("DecodeDelta() of truncated delta error = %v, want EOF", err)
+	}
+	if d == nil || len(d.Fields) != 2 || d.Fields[0].Value != "final" {
+		t.Errorf("DecodeDelta() of truncated delta did not return Name")
+	}
+}
+
+// compareGolden checks that the generated files match those committed to the
+// repository, which are regenerated with go generate
+func compareGolden(t *testing.T, files []gen.File) {
+	t.Helper()
+	for _, f := range files {
+		existing, err := os.ReadFile(f.Path)
+		if err != nil {
+			t.Errorf("Failed to read generated file: %v", err)
+			continue
+		}
+		if !bytes.Equal(existing, f.Content) {
+			t.Errorf("%s is out of date, run go generate", f.Path)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+// Code generated by deltagen. DO NOT EDIT.
+package inventory
+
+import (
+	"io"
+
+	"github.com/cbodonnell/delta"
+)
+
+var (
+	_ delta.EntityOf[int64]             = (*Item)(nil)
+	_ delta.Diffable[*Item, *ItemDelta] = (*Item)(nil)
+	_ delta.CheckedEntity               = (*Item)(nil)
+)
+
+func (e *Item) GetID() int64 {
+	return e.ID
+}
+
+func (e *Item) Clone() delta.Entity {
+	cp := *e
+	return &cp
+}
+
+func (e *Item) Delta(o delta.Entity) delta.Delta {
+	if o == nil {
+		return nil
+	}
+	other, ok := o.(*Item)
+	if !ok {
+		return nil // or panic
+	}
+	return e.Diff(other)
+}
+
+// Diff returns the changes that turn other into e, or all of e when other is
+// nil
+func (e *Item) Diff(other *Item) *ItemDelta {
+	if other == nil {
+		other = &Item{}
+	}
+	d := &ItemDelta{}
+	if e.ID != other.ID {
+		v := e.ID
+		d.ID = &v
+	}
+	if e.Name != other.Name {
+		v := e.Name
+		d.Name = &v
+	}
+	if e.Count != other.Count {
+		v := e.Count
+		d.Count = &v
+	}
+	if e.Rarity != other.Rarity {
+		v := e.Rarity
+		d.Rarity = &v
+	}
+	return d
+}
+
+func (e *Item) ApplyDelta(d delta.Delta) {
+	if d == nil {
+		return
+	}
+	dt, ok := d.(*ItemDelta)
+	if !ok {
+		return // or panic
+	}
+	dt.ApplyTo(e)
+}
+
+// ApplyDeltaChecked is like ApplyDelta, but reports a delta of another type or
+// one that fails validation instead of ignoring it
+func (e *Item) ApplyDeltaChecked(d delta.Delta) error {
+	if d == nil {
+		return nil
+	}
+	dt, ok := d.(*ItemDelta)
+	if !ok {
+		return delta.TypeMismatch("Item", d)
+	}
+	return dt.ApplyToChecked(e)
+}
+
+// Apply applies d to e if it passes validation
+func (e *Item) Apply(d *ItemDelta) error {
+	if d == nil {
+		return nil
+	}
+	return d.ApplyToChecked(e)
+}
+
+var _ delta.ValidatingDelta = (*ItemDelta)(nil)
+
+type ItemDelta struct {
+	ID     *int64
+	Name   *string
+	Count  *int32
+	Rarity *Rarity
+}
+
+// IsEmpty returns true if the delta carries no changes
+func (d *ItemDelta) IsEmpty() bool {
+	return d.ID == nil && d.Name == nil && d.Count == nil && d.Rarity == nil
+}
+
+// Validate returns a *delta.ApplyError if d cannot be applied to e, because e
+// has another type or a field would leave the bounds of its tags, without
+// changing e. Deltas do not carry the ID of the entity they were computed for,
+// so a full state applied to another entity is only reported by delta.Route.
+func (d *ItemDelta) Validate(e delta.Entity) error {
+	_, ok := e.(*Item)
+	if !ok {
+		return delta.TypeMismatch("Item", e)
+	}
+	return nil
+}
+
+// ApplyToChecked applies d to e only if it passes Validate, so either all of d
+// is applied or none of it
+func (d *ItemDelta) ApplyToChecked(e delta.Entity) error {
+	if err := d.Validate(e); err != nil {
+		return err
+	}
+	d.ApplyTo(e)
+	return nil
+}
+
+func (d *ItemDelta) ApplyTo(e delta.Entity) {
+	et, ok := e.(*Item)
+	if !ok {
+		return // or panic
+	}
+	if d.ID != nil {
+		et.ID = *d.ID
+	}
+	if d.Name != nil {
+		et.Name = *d.Name
+	}
+	if d.Count != nil {
+		et.Count = *d.Count
+	}
+	if d.Rarity != nil {
+		et.Rarity = *d.Rarity
+	}
+}
+
+func (d *ItemDelta) Serialize(w io.Writer) error {
+	bw := delta.NewBinaryWriter(w)
+
+	// Write field presence bitmask
+	var fieldMask uint64
+	if d.ID != nil {
+		fieldMask |= 1 << 0
+	}
+	if d.Name != nil {
+		fieldMask |= 1 << 1
+	}
+	if d.Count != nil {
+		fieldMask |= 1 << 2
+	}
+	if d.Rarity != nil {
+		fieldMask |= 1 << 3
+	}
+	if err := bw.WriteUint64(fieldMask); err != nil {
+		return err
+	}
+
+	// Write field values for present fields
+	if d.ID != nil {
+		// Serialize primitive
+		if err := bw.WriteInt64(*d.ID); err != nil {
+			return err
+		}
+	}
+	if d.Name != nil {
+		// Serialize primitive
+		if err := bw.WriteString(*d.Name); err != nil {
+			return err
+		}
+	}
+	if d.Count != nil {
+		// Serialize primitive
+		if err := bw.WriteInt32(*d.Count); err != nil {
+			return err
+		}
+	}
+	if d.Rarity != nil {
+		// Serialize primitive
+		if err := bw.WriteUint8(uint8(*d.Rarity)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *ItemDelta) Deserialize(r io.Reader) error {
+	br := delta.NewBinaryReader(r)
+
+	// Read field presence bitmask
+	fieldMask, err := br.ReadFieldMask(4)
+	if err != nil {
+		return delta.WrapDecodeError("Item", "", br.Offset(), err)
+	}
+
+	// Read field values for present fields
+	if fieldMask&(1<<0) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt64()
+		if err != nil {
+			return delta.WrapDecodeError("Item", "ID", offset, err)
+		}
+		d.ID = &val
+	}
+	if fieldMask&(1<<1) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadString()
+		if err != nil {
+			return delta.WrapDecodeError("Item", "Name", offset, err)
+		}
+		d.Name = &val
+	}
+	if fieldMask&(1<<2) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadInt32()
+		if err != nil {
+			return delta.WrapDecodeError("Item", "Count", offset, err)
+		}
+		d.Count = &val
+	}
+	if fieldMask&(1<<3) != 0 {
+		offset := br.Offset()
+		// Deserialize primitive
+		val, err := br.ReadUint8()
+		if err != nil {
+			return delta.WrapDecodeError("Item", "Rarity", offset, err)
+		}
+		v := Rarity(val)
+		d.Rarity = &v
+	}
+
+	return nil
+}
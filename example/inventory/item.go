@@ -0,0 +1,14 @@
+package inventory
+
+//go:generate go run github.com/cbodonnell/delta/cmd/deltagen
+
+// Rarity ranks items. It is encoded as its underlying uint8.
+type Rarity uint8
+
+// delta:entity
+type Item struct {
+	ID     int64
+	Name   string
+	Count  int32
+	Rarity Rarity
+}
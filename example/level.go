@@ -0,0 +1,11 @@
+package example
+
+// delta:entity
+type Level struct {
+	ID      int64
+	Tiles   [][]uint8
+	Spawns  map[string][]int32
+	Loot    map[string]map[int32]uint16
+	Heights [][][]float32
+	Chunks  [][]byte
+}
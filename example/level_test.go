@@ -0,0 +1,56 @@
+package example
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestLevel_NestedCollections(t *testing.T) {
+	original := &Level{
+		ID:      1,
+		Tiles:   [][]uint8{{1, 2, 3}, {4, 5, 6}},
+		Spawns:  map[string][]int32{"north": {1, 2}, "south": {3}},
+		Loot:    map[string]map[int32]uint16{"chest": {1: 10, 2: 20}},
+		Heights: [][][]float32{{{0.5, 1.5}}, {{2.5}}},
+		Chunks:  [][]byte{{0xAA}, {0xBB, 0xCC}},
+	}
+
+	// Clone deep copies every level of nesting
+	cloned := original.Clone().(*Level)
+	cloned.Tiles[0][0] = 9
+	cloned.Spawns["north"][0] = 9
+	cloned.Loot["chest"][1] = 99
+	cloned.Heights[0][0][0] = 9.5
+	if original.Tiles[0][0] != 1 || original.Spawns["north"][0] != 1 ||
+		original.Loot["chest"][1] != 10 || original.Heights[0][0][0] != 0.5 {
+		t.Fatalf("Clone() did not create deep copy of nested collections")
+	}
+
+	// Only changed fields are included, compared element by element
+	cloned.Chunks = [][]byte{{0xAA}, {0xBB, 0xCC}}
+	delta := original.Delta(cloned).(*LevelDelta)
+	if delta.Tiles == nil || delta.Spawns == nil || delta.Loot == nil || delta.Heights == nil {
+		t.Errorf("Delta() missed changed nested fields: %+v", delta)
+	}
+	if delta.Chunks != nil {
+		t.Errorf("Delta() included unchanged field Chunks")
+	}
+
+	var buf bytes.Buffer
+	if err := delta.Serialize(&buf); err != nil {
+		t.Fatalf("Failed to serialize delta: %v", err)
+	}
+	newDelta := &LevelDelta{}
+	if err := newDelta.Deserialize(&buf); err != nil {
+		t.Fatalf("Failed to deserialize delta: %v", err)
+	}
+	if !reflect.DeepEqual(newDelta, delta) {
+		t.Errorf("Deserialized delta does not match original:\nOriginal: %+v\nDeserialized: %+v", delta, newDelta)
+	}
+
+	cloned.ApplyDelta(newDelta)
+	if !reflect.DeepEqual(cloned, original) {
+		t.Errorf("Round-trip failed:\nOriginal: %+v\nAfter delta: %+v", original, cloned)
+	}
+}
@@ -0,0 +1,21 @@
+package example
+
+// delta:entity
+type Unit struct {
+	ID   int64
+	Kind string
+	HP   int32
+
+	// Sets are diffed as added and removed keys
+	Buffs map[string]struct{}
+	Flags map[int32]bool
+}
+
+// delta:entity
+type Lobby struct {
+	ID          int64
+	Name        string
+	Players     []Player       // diffed element-wise by player ID
+	Units       map[int64]Unit // diffed element-wise by map key
+	Projectiles []Projectile   // diffed element-wise by handle
+}
@@ -0,0 +1,279 @@
+package example
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/cbodonnell/delta"
+)
+
+func TestLobby_EntityCollections(t *testing.T) {
+	original := &Lobby{
+		ID:   1,
+		Name: "arena",
+		Players: []Player{
+			{BaseEntity: BaseEntity{ID: 1}, Name: "alice", Health: 100},
+			{BaseEntity: BaseEntity{ID: 2}, Name: "bob", Health: 80},
+			{BaseEntity: BaseEntity{ID: 3}, Name: "carol", Health: 60, Tags: []string{"mage"}},
+		},
+		Units: map[int64]Unit{
+			10: {ID: 10, Kind: "tank", HP: 500},
+			11: {ID: 11, Kind: "scout", HP: 50},
+		},
+	}
+
+	// Clone deep copies nested entities
+	cloned := original.Clone().(*Lobby)
+	cloned.Players[2].Tags[0] = "modified"
+	if original.Players[2].Tags[0] != "mage" {
+		t.Fatalf("Clone() did not create deep copy of nested entity")
+	}
+
+	// Remove bob, change carol, add dave and reorder; remove a unit, change
+	// another and add a third
+	target := &Lobby{
+		ID:   1,
+		Name: "arena",
+		Players: []Player{
+			{BaseEntity: BaseEntity{ID: 3}, Name: "carol", Health: 10},
+			{BaseEntity: BaseEntity{ID: 4}, Name: "dave", Health: 90},
+			{BaseEntity: BaseEntity{ID: 1}, Name: "alice", Health: 100},
+		},
+		Units: map[int64]Unit{
+			10: {ID: 10, Kind: "tank", HP: 250},
+			12: {ID: 12, Kind: "healer", HP: 40},
+		},
+	}
+
+	delta := original.Delta(target).(*LobbyDelta)
+	if delta.Name != nil {
+		t.Errorf("Delta() included unchanged field Name")
+	}
+	if delta.Players == nil || len(delta.Players.Added) != 1 || len(delta.Players.Changed) != 1 {
+		t.Fatalf("Delta() Players = %+v, want bob added and carol changed", delta.Players)
+	}
+	if _, ok := delta.Players.Changed[1]; ok {
+		t.Errorf("Delta() included unchanged player 1")
+	}
+
+	var buf bytes.Buffer
+	if err := delta.Serialize(&buf); err != nil {
+		t.Fatalf("Failed to serialize delta: %v", err)
+	}
+	newDelta := &LobbyDelta{}
+	if err := newDelta.Deserialize(&buf); err != nil {
+		t.Fatalf("Failed to deserialize delta: %v", err)
+	}
+	if !reflect.DeepEqual(newDelta, delta) {
+		t.Errorf("Deserialized delta does not match original:\nOriginal: %+v\nDeserialized: %+v", delta, newDelta)
+	}
+
+	target.ApplyDelta(newDelta)
+	if !reflect.DeepEqual(target, original) {
+		t.Errorf("Round-trip failed:\nOriginal: %+v\nAfter delta: %+v", original, target)
+	}
+
+	// Unchanged collections produce no delta
+	if delta := original.Delta(original.Clone()).(*LobbyDelta); !delta.IsEmpty() {
+		t.Errorf("Delta() of identical lobbies = %+v, want empty", delta)
+	}
+}
+
+func TestUnit_Sets(t *testing.T) {
+	original := &Unit{
+		ID:    10,
+		Buffs: map[string]struct{}{"haste": {}, "shield": {}},
+		Flags: map[int32]bool{1: true, 2: true},
+	}
+	target := &Unit{
+		ID:    10,
+		Buffs: map[string]struct{}{"shield": {}, "poison": {}},
+	}
+
+	delta := original.Delta(target).(*UnitDelta)
+	if delta.Buffs == nil || !reflect.DeepEqual(delta.Buffs.Added, []string{"haste"}) ||
+		!reflect.DeepEqual(delta.Buffs.Removed, []string{"poison"}) {
+		t.Fatalf("Delta() Buffs = %+v, want haste added and poison removed", delta.Buffs)
+	}
+
+	var buf bytes.Buffer
+	if err := delta.Serialize(&buf); err != nil {
+		t.Fatalf("Failed to serialize delta: %v", err)
+	}
+	newDelta := &UnitDelta{}
+	if err := newDelta.Deserialize(&buf); err != nil {
+		t.Fatalf("Failed to deserialize delta: %v", err)
+	}
+
+	target.ApplyDelta(newDelta)
+	if !reflect.DeepEqual(target, original) {
+		t.Errorf("Round-trip failed:\nOriginal: %+v\nAfter delta: %+v", original, target)
+	}
+
+	// A false entry is not a member of the set
+	target.Flags[3] = false
+	if delta := original.Delta(target).(*UnitDelta); !delta.IsEmpty() {
+		t.Errorf("Delta() with a false set entry = %+v, want empty", delta)
+	}
+}
+
+func TestLobby_ApplyDeltaCheckedNested(t *testing.T) {
+	original := &Lobby{
+		ID:      1,
+		Name:    "arena",
+		Players: []Player{{BaseEntity: BaseEntity{ID: 1}, Health: 50}},
+	}
+	target := &Lobby{
+		ID:      1,
+		Name:    "renamed",
+		Players: []Player{{BaseEntity: BaseEntity{ID: 1}, Health: 500}},
+	}
+
+	applied := original.Clone().(*Lobby)
+	err := applied.Apply(target.Diff(original))
+	var ae *delta.ApplyError
+	if !errors.As(err, &ae) || !errors.Is(err, delta.ErrOutOfRange) {
+		t.Fatalf("Apply() error = %v, want an *delta.ApplyError wrapping ErrOutOfRange", err)
+	}
+	if ae.Entity != "Lobby" || ae.Field != "Players[1].Health" {
+		t.Errorf("Apply() error at %s.%s, want Lobby.Players[1].Health", ae.Entity, ae.Field)
+	}
+	if !reflect.DeepEqual(applied, original) {
+		t.Errorf("Apply() changed the lobby to %+v", applied)
+	}
+
+	// The unchecked methods still apply everything
+	applied.ApplyDelta(target.Delta(original))
+	if !reflect.DeepEqual(applied, target) {
+		t.Errorf("ApplyDelta() = %+v, want %+v", applied, target)
+	}
+}
+
+func TestLobby_DecodeErrors(t *testing.T) {
+	old := &Lobby{ID: 1, Players: []Player{{BaseEntity: BaseEntity{ID: 1}, Name: "a"}}}
+	updated := &Lobby{ID: 1, Players: []Player{{BaseEntity: BaseEntity{ID: 1}, Name: "alice"}}}
+	var buf bytes.Buffer
+	if err := updated.Delta(old).Serialize(&buf); err != nil {
+		t.Fatalf("Failed to serialize delta: %v", err)
+	}
+	data := buf.Bytes()
+
+	// The mask, the added and changed counts, player 1's key and mask, then
+	// the length of the player's name precede the name
+	const nameOffset = 8 + 1 + 1 + 8 + 8 + 1
+
+	tests := []struct {
+		name   string
+		data   []byte
+		want   error
+		field  string
+		offset int64
+	}{
+		{"empty", nil, io.EOF, "", 0},
+		{"truncated mask", data[:4], io.ErrUnexpectedEOF, "", 0},
+		{"truncated nested field", data[:nameOffset+2], io.ErrUnexpectedEOF, "Players[1].Name", nameOffset},
+		{"varint overflow", []byte{2, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff}, delta.ErrVarintOverflow, "Name", 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&LobbyDelta{}).Deserialize(bytes.NewReader(tt.data))
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Deserialize() error = %v, want %v", err, tt.want)
+			}
+			var de *delta.DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("Deserialize() error = %#v, want a *delta.DecodeError", err)
+			}
+			if de.Entity != "Lobby" || de.Field != tt.field || de.Offset != tt.offset {
+				t.Errorf("Deserialize() error at %s %q offset %d, want Lobby %q offset %d", de.Entity, de.Field, de.Offset, tt.field, tt.offset)
+			}
+
+			// The reflective decoder reports the same position
+			rd, err := delta.NewReflectDelta(&Lobby{})
+			if err != nil {
+				t.Fatalf("NewReflectDelta() error: %v", err)
+			}
+			err = rd.Deserialize(bytes.NewReader(tt.data))
+			if !errors.As(err, &de) || de.Field != tt.field || de.Offset != tt.offset {
+				t.Errorf("reflective Deserialize() error = %v, want field %q at offset %d", err, tt.field, tt.offset)
+			}
+		})
+	}
+}
+
+func TestLobby_DecodeModes(t *testing.T) {
+	old := &Lobby{ID: 1, Players: []Player{{BaseEntity: BaseEntity{ID: 1}, Name: "a"}}}
+	updated := &Lobby{ID: 1, Players: []Player{{BaseEntity: BaseEntity{ID: 1}, Name: "alice"}}}
+	var buf bytes.Buffer
+	if err := updated.Delta(old).Serialize(&buf); err != nil {
+		t.Fatalf("Failed to serialize delta: %v", err)
+	}
+	valid := buf.Bytes()
+
+	// Player 1's mask follows the Lobby mask, the added and changed counts
+	// and the player's key
+	const playerMask = 8 + 1 + 1 + 8
+
+	trailing := append(append([]byte(nil), valid...), 0, 0)
+	unknownBit := append([]byte(nil), valid...)
+	unknownBit[1] |= 0x40 // bit 14, Lobby has 5 fields
+	nestedBit := append([]byte(nil), valid...)
+	nestedBit[playerMask+2] |= 0x10 // bit 20, Player has 8 fields
+
+	// The values of unknown nested fields cannot be skipped, so they are
+	// rejected in both modes
+	tests := []struct {
+		name    string
+		data    []byte
+		want    error
+		field   string
+		offset  int64
+		ignored bool
+	}{
+		{"trailing bytes", trailing, delta.ErrTrailingBytes, "", int64(len(valid)), true},
+		{"unknown field", unknownBit, delta.ErrUnknownFields, "", 0, true},
+		{"unknown nested field", nestedBit, delta.ErrUnknownFields, "Players[1]", playerMask, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rd, err := delta.NewReflectDelta(&Lobby{})
+			if err != nil {
+				t.Fatalf("NewReflectDelta() error: %v", err)
+			}
+			for _, d := range []delta.Delta{&LobbyDelta{}, rd} {
+				err := delta.Decode(tt.data, d, delta.Strict)
+				var de *delta.DecodeError
+				if !errors.Is(err, tt.want) || !errors.As(err, &de) {
+					t.Fatalf("Decode(%T, Strict) error = %v, want a *delta.DecodeError wrapping %v", d, err, tt.want)
+				}
+				if de.Field != tt.field || de.Offset != tt.offset {
+					t.Errorf("Decode(%T, Strict) error at %q offset %d, want %q offset %d", d, de.Field, de.Offset, tt.field, tt.offset)
+				}
+			}
+
+			d := &LobbyDelta{}
+			err = delta.Decode(tt.data, d, delta.IgnoreTrailing)
+			if !tt.ignored {
+				if !errors.Is(err, tt.want) {
+					t.Errorf("Decode(IgnoreTrailing) error = %v, want %v", err, tt.want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decode(IgnoreTrailing) error: %v", err)
+			}
+			applied := old.Clone().(*Lobby)
+			applied.ApplyDelta(d)
+			if !reflect.DeepEqual(applied, updated) {
+				t.Errorf("Decode(IgnoreTrailing) applied = %+v, want %+v", applied, updated)
+			}
+		})
+	}
+
+	if err := delta.Decode(valid, &LobbyDelta{}, delta.Strict); err != nil {
+		t.Errorf("Decode(Strict) of a valid delta error: %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package example
+
+// BaseEntity holds the fields shared by every entity in the example game
+type BaseEntity struct {
+	ID   int64
+	X, Y float64
+}
+
+// Score is embedded in Player, where it is a field named Score
+type Score int32
+
+// Cooldowns holds server-only timers embedded in Player
+type Cooldowns struct {
+	Timers map[string]int32
+}
+
+// delta:entity
+type Player struct {
+	BaseEntity
+
+	Name   string   `delta:"maxlen=32"`
+	Health int32    `delta:"min=0,max=100"` // checked before applying
+	Tags   []string `delta:"maxlen=8"`
+	Weapon Weapon   // polymorphic, diffed through the type registry
+
+	// Server-only bookkeeping, never diffed or transmitted
+	LastInputSeq uint32   `delta:"-"`
+	PendingTags  []string `delta:"-"`
+
+	// Unexported fields are only diffed when opted in
+	level   int32 `delta:"include"`
+	session string
+
+	Score                 // embedded named type, diffed as a field
+	Cooldowns `delta:"-"` // skipped, but deep copied by Clone
+}
@@ -0,0 +1,231 @@
+package example
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/cbodonnell/delta"
+)
+
+func TestPlayer_EmbeddedFields(t *testing.T) {
+	original := &Player{
+		BaseEntity: BaseEntity{ID: 7, X: 1.5, Y: 2.5},
+		Name:       "alice",
+		Health:     100,
+		Tags:       []string{"mage"},
+	}
+
+	if original.GetID() != 7 {
+		t.Fatalf("GetID() = %v, want 7", original.GetID())
+	}
+
+	cloned := original.Clone().(*Player)
+	cloned.X = 9.5     // promoted field change
+	cloned.Health = 50 // own field change
+	cloned.Score = 12  // embedded named type change
+	cloned.Tags = append(cloned.Tags, "healer")
+
+	delta := original.Delta(cloned).(*PlayerDelta)
+	if delta.X == nil || delta.Y != nil {
+		t.Fatalf("Delta() did not diff promoted fields: X=%v Y=%v", delta.X, delta.Y)
+	}
+	if delta.Score == nil || *delta.Score != 0 {
+		t.Fatalf("Delta() did not diff the embedded Score: %v", delta.Score)
+	}
+
+	var buf bytes.Buffer
+	if err := delta.Serialize(&buf); err != nil {
+		t.Fatalf("Failed to serialize delta: %v", err)
+	}
+	newDelta := &PlayerDelta{}
+	if err := newDelta.Deserialize(&buf); err != nil {
+		t.Fatalf("Failed to deserialize delta: %v", err)
+	}
+
+	cloned.ApplyDelta(newDelta)
+	if !reflect.DeepEqual(cloned, original) {
+		t.Errorf("Round-trip failed:\nOriginal: %+v\nAfter delta: %+v", original, cloned)
+	}
+}
+
+func TestPlayer_SkippedFields(t *testing.T) {
+	original := &Player{
+		BaseEntity:   BaseEntity{ID: 7},
+		LastInputSeq: 42,
+		PendingTags:  []string{"stunned"},
+		level:        3,
+		session:      "abc",
+		Cooldowns:    Cooldowns{Timers: map[string]int32{"dash": 3}},
+	}
+
+	// Clone deep copies skipped fields too, including those of skipped
+	// embedded structs
+	cloned := original.Clone().(*Player)
+	cloned.PendingTags[0] = "modified"
+	if original.PendingTags[0] != "stunned" {
+		t.Fatalf("Clone() did not create deep copy of skipped slice")
+	}
+	cloned.Timers["dash"] = 0
+	if original.Timers["dash"] != 3 {
+		t.Fatalf("Clone() did not create deep copy of skipped embedded map")
+	}
+
+	// Only the opted-in unexported field is diffed
+	target := &Player{BaseEntity: BaseEntity{ID: 7}}
+	delta := original.Delta(target).(*PlayerDelta)
+	if delta.level == nil {
+		t.Errorf("Delta() did not include field tagged delta:\"include\"")
+	}
+
+	target.ApplyDelta(delta)
+	if target.level != 3 {
+		t.Errorf("level = %v, want 3", target.level)
+	}
+	if target.LastInputSeq != 0 || target.PendingTags != nil || target.session != "" || target.Timers != nil {
+		t.Errorf("ApplyDelta() modified skipped fields: %+v", target)
+	}
+}
+
+func TestPlayer_InterfaceField(t *testing.T) {
+	original := &Player{
+		BaseEntity: BaseEntity{ID: 7},
+		Weapon:     &Sword{ID: 1, Sharpness: 10},
+	}
+
+	// Clone deep copies the held entity
+	cloned := original.Clone().(*Player)
+	cloned.Weapon.(*Sword).Sharpness = 20
+	if original.Weapon.(*Sword).Sharpness != 10 {
+		t.Fatalf("Clone() did not create deep copy of interface field")
+	}
+
+	tests := []struct {
+		name   string
+		target Weapon
+		full   bool
+	}{
+		{"same type", &Sword{ID: 1, Sharpness: 20}, false},
+		{"different type", &Bow{ID: 2, Arrows: 30}, true},
+		{"nil", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &Player{BaseEntity: BaseEntity{ID: 7}, Weapon: tt.target}
+			delta := original.Delta(target).(*PlayerDelta)
+			if delta.Weapon == nil || delta.Weapon.Full != tt.full {
+				t.Fatalf("Delta() Weapon = %+v, want full = %v", delta.Weapon, tt.full)
+			}
+
+			var buf bytes.Buffer
+			if err := delta.Serialize(&buf); err != nil {
+				t.Fatalf("Failed to serialize delta: %v", err)
+			}
+			newDelta := &PlayerDelta{}
+			if err := newDelta.Deserialize(&buf); err != nil {
+				t.Fatalf("Failed to deserialize delta: %v", err)
+			}
+
+			target.ApplyDelta(newDelta)
+			if !reflect.DeepEqual(target, original) {
+				t.Errorf("Round-trip failed:\nOriginal: %+v\nAfter delta: %+v", original, target)
+			}
+		})
+	}
+
+	// Setting the field to nil is a change too
+	target := &Player{BaseEntity: BaseEntity{ID: 7}, Weapon: &Bow{ID: 2}}
+	empty := &Player{BaseEntity: BaseEntity{ID: 7}}
+	empty.Delta(target).ApplyTo(target)
+	if target.Weapon != nil {
+		t.Errorf("Weapon = %+v, want nil", target.Weapon)
+	}
+}
+
+func TestDiffInterface_Unencodable(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"not an entity", "sword", "delta: type string is not an entity"},
+		{"unregistered entity", &Player{}, "delta: type *example.Player is not registered"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != tt.want {
+					t.Errorf("DiffInterface() panic = %v, want %q", r, tt.want)
+				}
+			}()
+			delta.DiffInterface[any](tt.value, nil)
+		})
+	}
+}
+
+func TestPlayer_ApplyDeltaChecked(t *testing.T) {
+	original := &Player{BaseEntity: BaseEntity{ID: 1}, Name: "alice", Health: 50, Weapon: &Sword{ID: 1, Sharpness: 2}}
+
+	t.Run("valid", func(t *testing.T) {
+		target := &Player{BaseEntity: BaseEntity{ID: 1}, Name: "alice", Health: 80, Weapon: &Bow{ID: 1, Arrows: 3}}
+		applied := original.Clone().(*Player)
+		if err := applied.ApplyDeltaChecked(target.Delta(original)); err != nil {
+			t.Fatalf("ApplyDeltaChecked() error: %v", err)
+		}
+		if !reflect.DeepEqual(applied, target) {
+			t.Errorf("ApplyDeltaChecked() = %+v, want %+v", applied, target)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		d      delta.Delta
+		target *Player
+		want   error
+		field  string
+	}{
+		{
+			name: "type mismatch",
+			d:    (&Sword{ID: 1}).Delta(&Sword{}),
+			want: delta.ErrTypeMismatch,
+		},
+		{
+			name:  "above max",
+			d:     (&Player{BaseEntity: BaseEntity{ID: 1}, Name: "mallory", Health: 101}).Delta(original),
+			want:  delta.ErrOutOfRange,
+			field: "Health",
+		},
+		{
+			name:  "below min",
+			d:     (&Player{BaseEntity: BaseEntity{ID: 1}, Health: -1}).Delta(original),
+			want:  delta.ErrOutOfRange,
+			field: "Health",
+		},
+		{
+			name:  "too long",
+			d:     (&Player{BaseEntity: BaseEntity{ID: 1}, Tags: make([]string, 9)}).Delta(original),
+			want:  delta.ErrOutOfRange,
+			field: "Tags",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applied := original.Clone().(*Player)
+			err := applied.ApplyDeltaChecked(tt.d)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("ApplyDeltaChecked() error = %v, want %v", err, tt.want)
+			}
+			var ae *delta.ApplyError
+			if !errors.As(err, &ae) || ae.Entity != "Player" || ae.Field != tt.field {
+				t.Errorf("ApplyDeltaChecked() error = %#v, want an *delta.ApplyError for Player field %q", err, tt.field)
+			}
+
+			// Nothing is applied when validation fails, including the valid
+			// fields of the delta
+			if !reflect.DeepEqual(applied, original) {
+				t.Errorf("ApplyDeltaChecked() changed the player to %+v", applied)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package example
+
+// Handle identifies a projectile. It is encoded as its underlying uint32.
+type Handle uint32
+
+// delta:entity
+type Projectile struct {
+	Handle  Handle `delta:"id"`
+	OwnerID int64
+	X, Y    float32
+}
@@ -0,0 +1,298 @@
+package example
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cbodonnell/delta"
+	"github.com/cbodonnell/delta/example/inventory"
+)
+
+var reflectCases = []struct {
+	name     string
+	old, new delta.Entity
+}{
+	{
+		name: "GameState",
+		old:  &GameState{ID: 1, Round: 1, Inventory: []string{"sword"}, PlayerScores: map[string]int16{"alice": 1, "bob": 2}},
+		new: &GameState{
+			ID: 1, Round: 2, Score: 300, Lives: -1, MaxHP: 250, X: 1.5, Y: -2, Speed: 3.25,
+			PlayerName: "alice", IsActive: true,
+			Inventory: []string{"sword", "shield"}, Positions: []float64{1, 2}, PlayerIDs: []int64{7},
+			Data:         []byte{0xde, 0xad},
+			PlayerScores: map[string]int16{"alice": 5, "bob": 2, "carol": 9, "dave": -1},
+			ItemCounts:   map[int8]int32{3: 4, -1: 7, 12: 0}, Metadata: map[string]string{"mode": "ctf", "map": "dust", "team": "red"},
+		},
+	},
+	{
+		name: "GameState/cleared",
+		old:  &GameState{ID: 1, Inventory: []string{"sword"}, Metadata: map[string]string{"mode": "ctf"}},
+		new:  &GameState{ID: 1},
+	},
+	{
+		name: "Lobby",
+		old: &Lobby{
+			ID: 1, Name: "arena",
+			Players: []Player{
+				{BaseEntity: BaseEntity{ID: 1}, Name: "alice", Weapon: &Sword{ID: 1, Sharpness: 3}},
+				{BaseEntity: BaseEntity{ID: 2}, Name: "bob", Weapon: &Bow{ID: 2, Arrows: 10}},
+				{BaseEntity: BaseEntity{ID: 3}, Name: "carol", level: 4, session: "a"},
+			},
+			Units: map[int64]Unit{
+				10: {ID: 10, Kind: "tank", Buffs: map[string]struct{}{"shield": {}, "cloak": {}}},
+				11: {ID: 11, Kind: "jeep"},
+				12: {ID: 12, Kind: "jeep", HP: 1},
+				15: {ID: 15, Kind: "scout"},
+				16: {ID: 16, Kind: "scout"},
+			},
+			Projectiles: []Projectile{{Handle: 7, OwnerID: 1}, {Handle: 9, OwnerID: 2, X: 1}},
+		},
+		new: &Lobby{
+			ID: 1, Name: "arena 2",
+			Players: []Player{
+				{BaseEntity: BaseEntity{ID: 3}, Name: "carol", level: 5, session: "b"},
+				{BaseEntity: BaseEntity{ID: 4, X: 2}, Name: "dave", Tags: []string{"new"}, Weapon: &Sword{ID: 4}},
+				{BaseEntity: BaseEntity{ID: 1}, Name: "alice", Weapon: &Bow{ID: 1, Range: 30}},
+				{BaseEntity: BaseEntity{ID: 5}, Name: "erin", Score: 40},
+			},
+			Units: map[int64]Unit{
+				10: {ID: 10, Kind: "tank", HP: 5, Buffs: map[string]struct{}{"haste": {}, "regen": {}, "armor": {}}, Flags: map[int32]bool{1: true, 4: true}},
+				12: {ID: 12, Kind: "jeep", HP: 2},
+				13: {ID: 13, Kind: "scout", Flags: map[int32]bool{7: true}},
+				14: {ID: 14, Kind: "tank", HP: 9},
+			},
+			Projectiles: []Projectile{{Handle: 9, OwnerID: 2, X: 4}, {Handle: 11, OwnerID: 3}},
+		},
+	},
+	{
+		name: "Lobby/weapon changed",
+		old:  &Lobby{ID: 1, Players: []Player{{BaseEntity: BaseEntity{ID: 1}, Weapon: &Sword{ID: 1, Sharpness: 3}}}},
+		new:  &Lobby{ID: 1, Players: []Player{{BaseEntity: BaseEntity{ID: 1}, Weapon: &Sword{ID: 1, Sharpness: 9}}}},
+	},
+	{
+		name: "Lobby/weapon replaced",
+		old:  &Lobby{ID: 1, Players: []Player{{BaseEntity: BaseEntity{ID: 1}, Weapon: &Sword{ID: 1, Sharpness: 3}}}},
+		new:  &Lobby{ID: 1, Players: []Player{{BaseEntity: BaseEntity{ID: 1}, Weapon: &Bow{ID: 1, Range: 30}}}},
+	},
+	{
+		name: "Lobby/unit replaced",
+		old:  &Lobby{ID: 1, Units: map[int64]Unit{10: {ID: 10}}, Players: []Player{{BaseEntity: BaseEntity{ID: 1}, Weapon: &Bow{ID: 1}}}},
+		new:  &Lobby{ID: 1, Units: map[int64]Unit{11: {ID: 11, Flags: map[int32]bool{2: true, 3: false}}}, Players: []Player{{BaseEntity: BaseEntity{ID: 1}}}},
+	},
+	{
+		name: "Level",
+		old:  &Level{ID: 1, Tiles: [][]uint8{{1, 2}}},
+		new: &Level{
+			ID: 1, Tiles: [][]uint8{{1, 2}, {3}}, Spawns: map[string][]int32{"red": {1, 2}, "blue": {3}, "green": nil},
+			Loot: map[string]map[int32]uint16{"chest": {7: 1, 2: 5, 9: 0}, "barrel": {1: 1}}, Heights: [][][]float32{{{0.5}}}, Chunks: [][]byte{{0xff}},
+		},
+	},
+	{
+		name: "Stats",
+		old:  &Stats[int32]{ID: 1, Current: 5, History: []int32{1}},
+		new:  &Stats[int32]{ID: 1, Current: 7, Max: 10, History: []int32{1, 2}, ByName: map[string]int32{"str": 3, "dex": 1, "int": 4}, Counts: map[int32]int32{1: 2, -3: 1, 8: 0}},
+	},
+	{
+		name: "Transform",
+		old:  &Transform{ID: 1, Updated: time.Unix(100, 0).UTC()},
+		new:  &Transform{ID: 1, Position: Vec3{1, 2, 3}, Updated: time.Unix(200, 5).UTC()},
+	},
+	{
+		name: "Projectile",
+		old:  &Projectile{Handle: 9, OwnerID: 1},
+		new:  &Projectile{Handle: 9, OwnerID: 1, X: 4},
+	},
+	{
+		name: "Stash",
+		old:  &Stash{ID: 1},
+		new: &Stash{
+			ID: 1, Owner: 2, Best: 3, Grade: 7, Grades: map[inventory.Rarity]Grade{1: 2, 3: -4},
+			History: []Grade{1, 5}, Locks: map[string]Locked{"vault": true, "chest": true, "door": false}, Cooldown: 1500 * time.Millisecond,
+		},
+	},
+	{
+		name: "unchanged",
+		old:  &Lobby{ID: 1, Players: []Player{{BaseEntity: BaseEntity{ID: 1}, Weapon: &Sword{ID: 1}}}},
+		new:  &Lobby{ID: 1, Players: []Player{{BaseEntity: BaseEntity{ID: 1}, Weapon: &Sword{ID: 1}}}},
+	},
+}
+
+func TestReflect_MatchesGenerated(t *testing.T) {
+	for _, tc := range reflectCases {
+		t.Run(tc.name, func(t *testing.T) {
+			generated := tc.new.Delta(tc.old)
+			var want bytes.Buffer
+			if err := generated.Serialize(&want); err != nil {
+				t.Fatalf("Failed to serialize generated delta: %v", err)
+			}
+
+			reflective, err := delta.Diff(tc.old, tc.new)
+			if err != nil {
+				t.Fatalf("Diff() error: %v", err)
+			}
+			var got bytes.Buffer
+			if err := reflective.Serialize(&got); err != nil {
+				t.Fatalf("Failed to serialize reflective delta: %v", err)
+			}
+			if !bytes.Equal(got.Bytes(), want.Bytes()) {
+				t.Fatalf("Diff() serialized to %x, want %x", got.Bytes(), want.Bytes())
+			}
+			if got, want := reflective.(delta.ElementDelta).IsEmpty(), generated.(delta.ElementDelta).IsEmpty(); got != want {
+				t.Errorf("IsEmpty() = %v, want %v", got, want)
+			}
+
+			// Applying either delta, in memory or after decoding it with the
+			// other implementation, must produce the new state
+			decoded, err := delta.NewReflectDelta(tc.old)
+			if err != nil {
+				t.Fatalf("NewReflectDelta() error: %v", err)
+			}
+			if err := decoded.Deserialize(bytes.NewReader(want.Bytes())); err != nil {
+				t.Fatalf("Failed to deserialize generated delta: %v", err)
+			}
+			fromGenerated := tc.new.Delta(tc.old)
+			if err := fromGenerated.Deserialize(bytes.NewReader(got.Bytes())); err != nil {
+				t.Fatalf("Failed to deserialize reflective delta: %v", err)
+			}
+
+			for name, d := range map[string]delta.Delta{
+				"reflective":                reflective,
+				"decoded reflective":        decoded,
+				"generated":                 generated,
+				"generated from reflective": fromGenerated,
+			} {
+				applied := tc.old.Clone()
+				if err := delta.Apply(applied, d); err != nil {
+					t.Fatalf("Apply(%s) error: %v", name, err)
+				}
+				if diff := tc.new.Delta(applied); !diff.(delta.ElementDelta).IsEmpty() {
+					t.Errorf("Apply(%s) left differences %+v", name, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestReflect_Errors(t *testing.T) {
+	type noID struct {
+		Name string
+	}
+	type plainInt struct {
+		ID    int64
+		Count int
+	}
+	type pointer struct {
+		ID   int64
+		Next *pointer
+	}
+	type embedsTime struct {
+		ID int64
+		time.Time
+	}
+	type badBound struct {
+		ID   int64
+		Rank int8 `delta:"max=300"`
+	}
+
+	if _, err := delta.Diff(&GameState{}, &Lobby{}); err == nil {
+		t.Errorf("Diff() of different types did not fail")
+	}
+	if _, err := delta.Diff(noID{}, noID{}); err == nil {
+		t.Errorf("Diff() of a struct without an identity field did not fail")
+	}
+	if _, err := delta.Diff(plainInt{}, plainInt{}); err == nil {
+		t.Errorf("Diff() of a struct with an int field did not fail")
+	}
+	if _, err := delta.Diff(pointer{}, pointer{}); err == nil {
+		t.Errorf("Diff() of a struct with a pointer field did not fail")
+	}
+	if _, err := delta.Diff(badBound{}, badBound{}); err == nil {
+		t.Errorf("Diff() of a struct with a max that doesn't fit its field did not fail")
+	}
+	if _, err := delta.Diff(embedsTime{}, embedsTime{}); err == nil {
+		t.Errorf("Diff() of a struct embedding one without accessible fields did not fail")
+	}
+	if _, err := delta.Diff((*GameState)(nil), &GameState{}); err == nil {
+		t.Errorf("Diff() of a nil pointer did not fail")
+	}
+
+	d, err := delta.Diff(&GameState{}, &GameState{Score: 1})
+	if err != nil {
+		t.Fatalf("Diff() error: %v", err)
+	}
+	if err := delta.Apply(GameState{}, d); err == nil {
+		t.Errorf("Apply() to a struct value did not fail")
+	}
+	if err := delta.Apply(&Lobby{}, d); err == nil {
+		t.Errorf("Apply() of a GameState delta to a Lobby did not fail")
+	}
+
+	// Generated deltas are checked against the target too, rather than decoded
+	// as a delta of its type
+	lobby := &Lobby{}
+	if err := delta.Apply(lobby, (&GameState{ID: 42}).Delta(&GameState{})); !errors.Is(err, delta.ErrTypeMismatch) {
+		t.Errorf("Apply() of a generated GameState delta to a Lobby error = %v, want ErrTypeMismatch", err)
+	}
+	if lobby.ID != 0 {
+		t.Errorf("Apply() of a mismatched delta changed the Lobby ID to %d", lobby.ID)
+	}
+	if err := delta.Apply(&struct{ ID int64 }{}, (&GameState{ID: 42}).Delta(&GameState{})); err == nil {
+		t.Errorf("Apply() of a generated delta to a plain struct did not fail")
+	}
+}
+
+func TestReflect_Bounds(t *testing.T) {
+	original := &Lobby{
+		ID:      1,
+		Name:    "arena",
+		Players: []Player{{BaseEntity: BaseEntity{ID: 1}, Health: 50}},
+	}
+
+	tests := []struct {
+		name   string
+		target *Lobby
+		field  string
+	}{
+		{
+			name:   "changed player above max",
+			target: &Lobby{ID: 1, Name: "renamed", Players: []Player{{BaseEntity: BaseEntity{ID: 1}, Health: 500}}},
+			field:  "Players[1].Health",
+		},
+		{
+			name:   "added player below min",
+			target: &Lobby{ID: 1, Players: []Player{{BaseEntity: BaseEntity{ID: 1}, Health: 50}, {BaseEntity: BaseEntity{ID: 2}, Health: -1}}},
+			field:  "Players[2].Health",
+		},
+		{
+			name:   "too many tags",
+			target: &Lobby{ID: 1, Players: []Player{{BaseEntity: BaseEntity{ID: 1}, Health: 50, Tags: make([]string, 9)}}},
+			field:  "Players[1].Tags",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := delta.Diff(original, tt.target)
+			if err != nil {
+				t.Fatalf("Diff() error: %v", err)
+			}
+
+			applied := original.Clone().(*Lobby)
+			err = delta.Apply(applied, d)
+			var ae *delta.ApplyError
+			if !errors.Is(err, delta.ErrOutOfRange) || !errors.As(err, &ae) || ae.Entity != "Lobby" || ae.Field != tt.field {
+				t.Fatalf("Apply() error = %v, want ErrOutOfRange for Lobby field %q", err, tt.field)
+			}
+			if !reflect.DeepEqual(applied, original) {
+				t.Errorf("Apply() changed the lobby to %+v", applied)
+			}
+
+			// Validate reports the same error without applying the delta
+			if err := d.(*delta.ReflectDelta).Validate(applied); !errors.Is(err, delta.ErrOutOfRange) {
+				t.Errorf("Validate() error = %v, want ErrOutOfRange", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package example
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/cbodonnell/delta"
+)
+
+func TestSortedKeys(t *testing.T) {
+	grades := delta.SortedKeys(map[Grade]bool{3: true, -7: true, 0: true, 12: true})
+	if want := []Grade{-7, 0, 3, 12}; !reflect.DeepEqual(grades, want) {
+		t.Errorf("SortedKeys() of named int32 keys = %v, want %v", grades, want)
+	}
+
+	floats := delta.SortedKeys(map[float64]int32{2.5: 1, math.Inf(-1): 1, -0.5: 1})
+	if want := []float64{math.Inf(-1), -0.5, 2.5}; !reflect.DeepEqual(floats, want) {
+		t.Errorf("SortedKeys() of float64 keys = %v, want %v", floats, want)
+	}
+
+	bools := delta.SortedKeys(map[bool]int32{true: 1, false: 0})
+	if want := []bool{false, true}; !reflect.DeepEqual(bools, want) {
+		t.Errorf("SortedKeys() of bool keys = %v, want %v", bools, want)
+	}
+
+	strings := delta.SortedKeys(map[string]int32{"b": 1, "a": 1, "": 1})
+	if want := []string{"", "a", "b"}; !reflect.DeepEqual(strings, want) {
+		t.Errorf("SortedKeys() of string keys = %v, want %v", strings, want)
+	}
+
+	// Keys of other kinds are ordered by reflection
+	type pair struct{ A, B int32 }
+	pairs := delta.SortedKeys(map[pair]int32{{2, 1}: 1, {1, 2}: 1})
+	if want := []pair{{1, 2}, {2, 1}}; !reflect.DeepEqual(pairs, want) {
+		t.Errorf("SortedKeys() of struct keys = %v, want %v", pairs, want)
+	}
+}
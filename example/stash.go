@@ -0,0 +1,29 @@
+package example
+
+import (
+	"time"
+
+	"github.com/cbodonnell/delta/example/inventory"
+)
+
+// Grade is a named primitive type, encoded as its underlying int32
+type Grade int32
+
+// Locked marks the members of a set-shaped map with a named bool type
+type Locked bool
+
+// Stash holds items whose entity type is declared in another package
+//
+// delta:entity
+type Stash struct {
+	ID       int64
+	Owner    int64
+	Items    []inventory.Item
+	Slots    map[string]inventory.Item
+	Best     inventory.Rarity
+	Grade    Grade `delta:"min=0,max=10"`
+	Grades   map[inventory.Rarity]Grade
+	History  []Grade
+	Locks    map[string]Locked
+	Cooldown time.Duration
+}
@@ -0,0 +1,97 @@
+package example
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cbodonnell/delta"
+	"github.com/cbodonnell/delta/example/inventory"
+)
+
+func TestStash_EntitiesFromOtherPackage(t *testing.T) {
+	original := &Stash{
+		ID:    1,
+		Owner: 7,
+		Items: []inventory.Item{
+			{ID: 1, Name: "potion", Count: 3},
+			{ID: 2, Name: "arrow", Count: 40},
+		},
+		Slots: map[string]inventory.Item{
+			"head": {ID: 3, Name: "helmet", Count: 1},
+		},
+	}
+
+	// Arrows were used up, the potion is new and the helmet was unequipped
+	target := &Stash{
+		ID:    1,
+		Owner: 7,
+		Items: []inventory.Item{
+			{ID: 2, Name: "arrow", Count: 12},
+		},
+		Slots: map[string]inventory.Item{},
+	}
+
+	delta := original.Delta(target).(*StashDelta)
+	if delta.Owner != nil {
+		t.Errorf("Delta() included unchanged field Owner")
+	}
+	if delta.Items == nil || len(delta.Items.Added) != 1 || len(delta.Items.Changed) != 1 {
+		t.Fatalf("Delta() Items = %+v, want potion added and arrow changed", delta.Items)
+	}
+
+	var buf bytes.Buffer
+	if err := delta.Serialize(&buf); err != nil {
+		t.Fatalf("Failed to serialize delta: %v", err)
+	}
+	newDelta := &StashDelta{}
+	if err := newDelta.Deserialize(&buf); err != nil {
+		t.Fatalf("Failed to deserialize delta: %v", err)
+	}
+
+	target.ApplyDelta(newDelta)
+	if !reflect.DeepEqual(target, original) {
+		t.Errorf("Round-trip failed:\nOriginal: %+v\nAfter delta: %+v", original, target)
+	}
+}
+
+func TestStash_NamedPrimitiveTypes(t *testing.T) {
+	original := &Stash{ID: 1, Locks: map[string]Locked{"door": true}}
+	target := &Stash{
+		ID:       1,
+		Items:    []inventory.Item{{ID: 1, Name: "gem", Rarity: 4}},
+		Best:     4,
+		Grade:    9,
+		Grades:   map[inventory.Rarity]Grade{4: 9},
+		History:  []Grade{3, 9},
+		Locks:    map[string]Locked{"vault": true},
+		Cooldown: 2 * time.Second,
+	}
+
+	d := target.Diff(original)
+	if d.Locks == nil || len(d.Locks.Added) != 1 || len(d.Locks.Removed) != 1 {
+		t.Errorf("Diff() Locks = %+v, want vault added and door removed", d.Locks)
+	}
+
+	var buf bytes.Buffer
+	if err := d.Serialize(&buf); err != nil {
+		t.Fatalf("Failed to serialize delta: %v", err)
+	}
+	decoded := &StashDelta{}
+	if err := decoded.Deserialize(&buf); err != nil {
+		t.Fatalf("Failed to deserialize delta: %v", err)
+	}
+	if err := original.Apply(decoded); err != nil {
+		t.Fatalf("Apply() error: %v", err)
+	}
+	if !reflect.DeepEqual(original, target) {
+		t.Errorf("Round-trip failed:\nTarget: %+v\nAfter delta: %+v", target, original)
+	}
+
+	grade := Grade(11)
+	if err := original.Apply(&StashDelta{Grade: &grade}); !errors.Is(err, delta.ErrOutOfRange) {
+		t.Errorf("Apply() of an out of range Grade error = %v, want ErrOutOfRange", err)
+	}
+}
@@ -0,0 +1,19 @@
+package example
+
+import "github.com/cbodonnell/delta"
+
+// Stats is a typed stat container shared across games
+//
+// delta:entity
+type Stats[T delta.Primitive] struct {
+	ID      int64
+	Current T
+	Max     T
+	History []T
+	ByName  map[string]T
+	Counts  map[T]int32
+	Bonus   map[string]Points
+}
+
+// Points is an alias, encoded as the int32 it stands for
+type Points = int32
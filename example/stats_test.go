@@ -0,0 +1,58 @@
+package example
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestStats_Generic(t *testing.T) {
+	t.Run("int32", func(t *testing.T) {
+		testStatsRoundTrip(t, &Stats[int32]{
+			ID:      1,
+			Current: 50,
+			Max:     100,
+			History: []int32{10, 20, 30},
+			ByName:  map[string]int32{"str": 5},
+			Counts:  map[int32]int32{1: 2},
+			Bonus:   map[string]Points{"crit": 3},
+		})
+	})
+	t.Run("string", func(t *testing.T) {
+		testStatsRoundTrip(t, &Stats[string]{
+			ID:      2,
+			Current: "gold",
+			History: []string{"bronze", "silver"},
+			Counts:  map[string]int32{"gold": 1},
+		})
+	})
+}
+
+func testStatsRoundTrip[T int32 | string](t *testing.T, original *Stats[T]) {
+	t.Helper()
+
+	cloned := original.Clone().(*Stats[T])
+	if !reflect.DeepEqual(cloned, original) {
+		t.Fatalf("Clone() did not create identical copy")
+	}
+
+	target := &Stats[T]{ID: original.ID}
+	delta := original.Delta(target).(*StatsDelta[T])
+
+	var buf bytes.Buffer
+	if err := delta.Serialize(&buf); err != nil {
+		t.Fatalf("Failed to serialize delta: %v", err)
+	}
+	newDelta := &StatsDelta[T]{}
+	if err := newDelta.Deserialize(&buf); err != nil {
+		t.Fatalf("Failed to deserialize delta: %v", err)
+	}
+	if !reflect.DeepEqual(newDelta, delta) {
+		t.Errorf("Deserialized delta does not match original:\nOriginal: %+v\nDeserialized: %+v", delta, newDelta)
+	}
+
+	target.ApplyDelta(newDelta)
+	if !reflect.DeepEqual(target, original) {
+		t.Errorf("Round-trip failed:\nOriginal: %+v\nAfter delta: %+v", original, target)
+	}
+}
@@ -0,0 +1,76 @@
+package example
+
+import (
+	"time"
+
+	"github.com/cbodonnell/delta"
+)
+
+// Vec3 is a point or direction in world space. It encodes itself through the
+// delta.DeltaMarshaler methods, so entities can hold it directly.
+type Vec3 struct {
+	X, Y, Z float32
+}
+
+func (v Vec3) Equal(other Vec3) bool {
+	return v == other
+}
+
+func (v Vec3) Clone() Vec3 {
+	return v
+}
+
+func (v Vec3) Encode(bw *delta.BinaryWriter) error {
+	for _, c := range []float32{v.X, v.Y, v.Z} {
+		if err := bw.WriteFloat32(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *Vec3) Decode(br *delta.BinaryReader) error {
+	for _, c := range []*float32{&v.X, &v.Y, &v.Z} {
+		f, err := br.ReadFloat32()
+		if err != nil {
+			return err
+		}
+		*c = f
+	}
+	return nil
+}
+
+// timeCodec encodes a time.Time as nanoseconds since the Unix epoch in UTC
+type timeCodec struct{}
+
+func (timeCodec) Equal(a, b time.Time) bool {
+	return a.Equal(b)
+}
+
+func (timeCodec) Clone(v time.Time) time.Time {
+	return v
+}
+
+func (timeCodec) Encode(bw *delta.BinaryWriter, v time.Time) error {
+	return bw.WriteInt64(v.UnixNano())
+}
+
+func (timeCodec) Decode(br *delta.BinaryReader) (time.Time, error) {
+	n, err := br.ReadInt64()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, n).UTC(), nil
+}
+
+func init() {
+	delta.RegisterCodec[time.Time]("time", timeCodec{})
+}
+
+// delta:entity
+type Transform struct {
+	ID       int64
+	Position Vec3
+	Velocity Vec3
+	Updated  time.Time `delta:"codec=time"`
+}
@@ -0,0 +1,55 @@
+package example
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTransform_Codecs(t *testing.T) {
+	original := &Transform{
+		ID:       1,
+		Position: Vec3{X: 1, Y: 2, Z: 3},
+		Velocity: Vec3{X: 0.5},
+		Updated:  time.Date(2024, 5, 1, 12, 0, 0, 500, time.UTC),
+	}
+
+	cloned := original.Clone().(*Transform)
+	if !reflect.DeepEqual(cloned, original) {
+		t.Fatalf("Clone() did not create identical copy")
+	}
+
+	// Only the position and timestamp changed
+	target := &Transform{
+		ID:       1,
+		Position: Vec3{X: 1, Y: 2},
+		Velocity: Vec3{X: 0.5},
+		Updated:  time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC),
+	}
+
+	delta := original.Delta(target).(*TransformDelta)
+	if delta.Velocity != nil {
+		t.Errorf("Delta() included unchanged field Velocity")
+	}
+	if delta.Position == nil || delta.Updated == nil {
+		t.Fatalf("Delta() = %+v, want Position and Updated", delta)
+	}
+
+	var buf bytes.Buffer
+	if err := delta.Serialize(&buf); err != nil {
+		t.Fatalf("Failed to serialize delta: %v", err)
+	}
+	newDelta := &TransformDelta{}
+	if err := newDelta.Deserialize(&buf); err != nil {
+		t.Fatalf("Failed to deserialize delta: %v", err)
+	}
+	if !reflect.DeepEqual(newDelta, delta) {
+		t.Errorf("Deserialized delta does not match original:\nOriginal: %+v\nDeserialized: %+v", delta, newDelta)
+	}
+
+	target.ApplyDelta(newDelta)
+	if !reflect.DeepEqual(target, original) {
+		t.Errorf("Round-trip failed:\nOriginal: %+v\nAfter delta: %+v", original, target)
+	}
+}
@@ -0,0 +1,60 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+import * as delta from "./delta";
+
+export interface Bow {
+  id: bigint;
+  arrows: number;
+  range: number;
+}
+
+export interface BowDelta {
+  id?: bigint;
+  arrows?: number;
+  range?: number;
+}
+
+export function newBow(): Bow {
+  return {
+    id: 0n,
+    arrows: 0,
+    range: 0,
+  };
+}
+
+export function getBowID(e: Bow): bigint {
+  return e.id;
+}
+
+export function deserializeBowDelta(r: delta.BinaryReader): BowDelta {
+  const d: BowDelta = {};
+  const mask = r.readUint64();
+  if (delta.hasField(mask, 0)) {
+    d.id = r.readInt64();
+  }
+  if (delta.hasField(mask, 1)) {
+    d.arrows = r.readInt32();
+  }
+  if (delta.hasField(mask, 2)) {
+    d.range = r.readFloat32();
+  }
+  return d;
+}
+
+export function applyBowDelta(e: Bow, d: BowDelta): void {
+  if (d.id !== undefined) {
+    e.id = d.id;
+  }
+  if (d.arrows !== undefined) {
+    e.arrows = d.arrows;
+  }
+  if (d.range !== undefined) {
+    e.range = d.range;
+  }
+}
+
+delta.registerType(2, {
+  create: newBow,
+  read: deserializeBowDelta,
+  apply: (e, d) => applyBowDelta(e as Bow, d as BowDelta),
+});
@@ -0,0 +1,180 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+import * as delta from "./delta";
+
+export interface GameState {
+  id: bigint;
+  round: number;
+  score: number;
+  lives: number;
+  maxHP: number;
+  x: number;
+  y: number;
+  speed: number;
+  playerName: string;
+  isActive: boolean;
+  inventory: string[];
+  positions: number[];
+  playerIDs: bigint[];
+  data: Uint8Array;
+  playerScores: Map<string, number>;
+  itemCounts: Map<number, number>;
+  metadata: Map<string, string>;
+}
+
+export interface GameStateDelta {
+  id?: bigint;
+  round?: number;
+  score?: number;
+  lives?: number;
+  maxHP?: number;
+  x?: number;
+  y?: number;
+  speed?: number;
+  playerName?: string;
+  isActive?: boolean;
+  inventory?: string[];
+  positions?: number[];
+  playerIDs?: bigint[];
+  data?: Uint8Array;
+  playerScores?: Map<string, number>;
+  itemCounts?: Map<number, number>;
+  metadata?: Map<string, string>;
+}
+
+export function newGameState(): GameState {
+  return {
+    id: 0n,
+    round: 0,
+    score: 0,
+    lives: 0,
+    maxHP: 0,
+    x: 0,
+    y: 0,
+    speed: 0,
+    playerName: "",
+    isActive: false,
+    inventory: [],
+    positions: [],
+    playerIDs: [],
+    data: new Uint8Array(0),
+    playerScores: new Map(),
+    itemCounts: new Map(),
+    metadata: new Map(),
+  };
+}
+
+export function getGameStateID(e: GameState): bigint {
+  return e.id;
+}
+
+export function deserializeGameStateDelta(r: delta.BinaryReader): GameStateDelta {
+  const d: GameStateDelta = {};
+  const mask = r.readUint64();
+  if (delta.hasField(mask, 0)) {
+    d.id = r.readInt64();
+  }
+  if (delta.hasField(mask, 1)) {
+    d.round = r.readInt16();
+  }
+  if (delta.hasField(mask, 2)) {
+    d.score = r.readInt32();
+  }
+  if (delta.hasField(mask, 3)) {
+    d.lives = r.readInt8();
+  }
+  if (delta.hasField(mask, 4)) {
+    d.maxHP = r.readUint16();
+  }
+  if (delta.hasField(mask, 5)) {
+    d.x = r.readFloat64();
+  }
+  if (delta.hasField(mask, 6)) {
+    d.y = r.readFloat64();
+  }
+  if (delta.hasField(mask, 7)) {
+    d.speed = r.readFloat32();
+  }
+  if (delta.hasField(mask, 8)) {
+    d.playerName = r.readString();
+  }
+  if (delta.hasField(mask, 9)) {
+    d.isActive = r.readBool();
+  }
+  if (delta.hasField(mask, 10)) {
+    d.inventory = delta.readSlice(r, (r) => r.readString());
+  }
+  if (delta.hasField(mask, 11)) {
+    d.positions = delta.readSlice(r, (r) => r.readFloat64());
+  }
+  if (delta.hasField(mask, 12)) {
+    d.playerIDs = delta.readSlice(r, (r) => r.readInt64());
+  }
+  if (delta.hasField(mask, 13)) {
+    d.data = r.readBytes();
+  }
+  if (delta.hasField(mask, 14)) {
+    d.playerScores = delta.readMap(r, (r) => r.readString(), (r) => r.readInt16());
+  }
+  if (delta.hasField(mask, 15)) {
+    d.itemCounts = delta.readMap(r, (r) => r.readInt8(), (r) => r.readInt32());
+  }
+  if (delta.hasField(mask, 16)) {
+    d.metadata = delta.readMap(r, (r) => r.readString(), (r) => r.readString());
+  }
+  return d;
+}
+
+export function applyGameStateDelta(e: GameState, d: GameStateDelta): void {
+  if (d.id !== undefined) {
+    e.id = d.id;
+  }
+  if (d.round !== undefined) {
+    e.round = d.round;
+  }
+  if (d.score !== undefined) {
+    e.score = d.score;
+  }
+  if (d.lives !== undefined) {
+    e.lives = d.lives;
+  }
+  if (d.maxHP !== undefined) {
+    e.maxHP = d.maxHP;
+  }
+  if (d.x !== undefined) {
+    e.x = d.x;
+  }
+  if (d.y !== undefined) {
+    e.y = d.y;
+  }
+  if (d.speed !== undefined) {
+    e.speed = d.speed;
+  }
+  if (d.playerName !== undefined) {
+    e.playerName = d.playerName;
+  }
+  if (d.isActive !== undefined) {
+    e.isActive = d.isActive;
+  }
+  if (d.inventory !== undefined) {
+    e.inventory = d.inventory;
+  }
+  if (d.positions !== undefined) {
+    e.positions = d.positions;
+  }
+  if (d.playerIDs !== undefined) {
+    e.playerIDs = d.playerIDs;
+  }
+  if (d.data !== undefined) {
+    e.data = d.data;
+  }
+  if (d.playerScores !== undefined) {
+    e.playerScores = d.playerScores;
+  }
+  if (d.itemCounts !== undefined) {
+    e.itemCounts = d.itemCounts;
+  }
+  if (d.metadata !== undefined) {
+    e.metadata = d.metadata;
+  }
+}
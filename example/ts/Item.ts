@@ -0,0 +1,63 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+import * as delta from "./delta";
+
+export interface Item {
+  id: bigint;
+  name: string;
+  count: number;
+  rarity: number;
+}
+
+export interface ItemDelta {
+  id?: bigint;
+  name?: string;
+  count?: number;
+  rarity?: number;
+}
+
+export function newItem(): Item {
+  return {
+    id: 0n,
+    name: "",
+    count: 0,
+    rarity: 0,
+  };
+}
+
+export function getItemID(e: Item): bigint {
+  return e.id;
+}
+
+export function deserializeItemDelta(r: delta.BinaryReader): ItemDelta {
+  const d: ItemDelta = {};
+  const mask = r.readUint64();
+  if (delta.hasField(mask, 0)) {
+    d.id = r.readInt64();
+  }
+  if (delta.hasField(mask, 1)) {
+    d.name = r.readString();
+  }
+  if (delta.hasField(mask, 2)) {
+    d.count = r.readInt32();
+  }
+  if (delta.hasField(mask, 3)) {
+    d.rarity = r.readUint8();
+  }
+  return d;
+}
+
+export function applyItemDelta(e: Item, d: ItemDelta): void {
+  if (d.id !== undefined) {
+    e.id = d.id;
+  }
+  if (d.name !== undefined) {
+    e.name = d.name;
+  }
+  if (d.count !== undefined) {
+    e.count = d.count;
+  }
+  if (d.rarity !== undefined) {
+    e.rarity = d.rarity;
+  }
+}
@@ -0,0 +1,81 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+import * as delta from "./delta";
+
+export interface Level {
+  id: bigint;
+  tiles: number[][];
+  spawns: Map<string, number[]>;
+  loot: Map<string, Map<number, number>>;
+  heights: number[][][];
+  chunks: Uint8Array[];
+}
+
+export interface LevelDelta {
+  id?: bigint;
+  tiles?: number[][];
+  spawns?: Map<string, number[]>;
+  loot?: Map<string, Map<number, number>>;
+  heights?: number[][][];
+  chunks?: Uint8Array[];
+}
+
+export function newLevel(): Level {
+  return {
+    id: 0n,
+    tiles: [],
+    spawns: new Map(),
+    loot: new Map(),
+    heights: [],
+    chunks: [],
+  };
+}
+
+export function getLevelID(e: Level): bigint {
+  return e.id;
+}
+
+export function deserializeLevelDelta(r: delta.BinaryReader): LevelDelta {
+  const d: LevelDelta = {};
+  const mask = r.readUint64();
+  if (delta.hasField(mask, 0)) {
+    d.id = r.readInt64();
+  }
+  if (delta.hasField(mask, 1)) {
+    d.tiles = delta.readSlice(r, (r) => delta.readSlice(r, (r) => r.readUint8()));
+  }
+  if (delta.hasField(mask, 2)) {
+    d.spawns = delta.readMap(r, (r) => r.readString(), (r) => delta.readSlice(r, (r) => r.readInt32()));
+  }
+  if (delta.hasField(mask, 3)) {
+    d.loot = delta.readMap(r, (r) => r.readString(), (r) => delta.readMap(r, (r) => r.readInt32(), (r) => r.readUint16()));
+  }
+  if (delta.hasField(mask, 4)) {
+    d.heights = delta.readSlice(r, (r) => delta.readSlice(r, (r) => delta.readSlice(r, (r) => r.readFloat32())));
+  }
+  if (delta.hasField(mask, 5)) {
+    d.chunks = delta.readSlice(r, (r) => r.readBytes());
+  }
+  return d;
+}
+
+export function applyLevelDelta(e: Level, d: LevelDelta): void {
+  if (d.id !== undefined) {
+    e.id = d.id;
+  }
+  if (d.tiles !== undefined) {
+    e.tiles = d.tiles;
+  }
+  if (d.spawns !== undefined) {
+    e.spawns = d.spawns;
+  }
+  if (d.loot !== undefined) {
+    e.loot = d.loot;
+  }
+  if (d.heights !== undefined) {
+    e.heights = d.heights;
+  }
+  if (d.chunks !== undefined) {
+    e.chunks = d.chunks;
+  }
+}
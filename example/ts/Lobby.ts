@@ -0,0 +1,75 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+import * as delta from "./delta";
+import { Player, PlayerDelta, applyPlayerDelta, deserializePlayerDelta, getPlayerID, newPlayer } from "./Player";
+import { Projectile, ProjectileDelta, applyProjectileDelta, deserializeProjectileDelta, getProjectileID, newProjectile } from "./Projectile";
+import { Unit, UnitDelta, applyUnitDelta, deserializeUnitDelta, newUnit } from "./Unit";
+
+export interface Lobby {
+  id: bigint;
+  name: string;
+  players: Player[];
+  units: Map<bigint, Unit>;
+  projectiles: Projectile[];
+}
+
+export interface LobbyDelta {
+  id?: bigint;
+  name?: string;
+  players?: delta.CollectionDelta<bigint, PlayerDelta>;
+  units?: delta.CollectionDelta<bigint, UnitDelta>;
+  projectiles?: delta.CollectionDelta<number, ProjectileDelta>;
+}
+
+export function newLobby(): Lobby {
+  return {
+    id: 0n,
+    name: "",
+    players: [],
+    units: new Map(),
+    projectiles: [],
+  };
+}
+
+export function getLobbyID(e: Lobby): bigint {
+  return e.id;
+}
+
+export function deserializeLobbyDelta(r: delta.BinaryReader): LobbyDelta {
+  const d: LobbyDelta = {};
+  const mask = r.readUint64();
+  if (delta.hasField(mask, 0)) {
+    d.id = r.readInt64();
+  }
+  if (delta.hasField(mask, 1)) {
+    d.name = r.readString();
+  }
+  if (delta.hasField(mask, 2)) {
+    d.players = delta.readCollection(r, (r) => r.readInt64(), deserializePlayerDelta);
+  }
+  if (delta.hasField(mask, 3)) {
+    d.units = delta.readCollection(r, (r) => r.readInt64(), deserializeUnitDelta);
+  }
+  if (delta.hasField(mask, 4)) {
+    d.projectiles = delta.readCollection(r, (r) => r.readUint32(), deserializeProjectileDelta);
+  }
+  return d;
+}
+
+export function applyLobbyDelta(e: Lobby, d: LobbyDelta): void {
+  if (d.id !== undefined) {
+    e.id = d.id;
+  }
+  if (d.name !== undefined) {
+    e.name = d.name;
+  }
+  if (d.players !== undefined) {
+    e.players = delta.applySlice(e.players, d.players, getPlayerID, newPlayer, applyPlayerDelta);
+  }
+  if (d.units !== undefined) {
+    delta.applyMap(e.units, d.units, newUnit, applyUnitDelta);
+  }
+  if (d.projectiles !== undefined) {
+    e.projectiles = delta.applySlice(e.projectiles, d.projectiles, getProjectileID, newProjectile, applyProjectileDelta);
+  }
+}
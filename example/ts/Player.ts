@@ -0,0 +1,108 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+import * as delta from "./delta";
+
+export interface Player {
+  id: bigint;
+  x: number;
+  y: number;
+  name: string;
+  health: number;
+  tags: string[];
+  weapon: unknown;
+  level: number;
+  score: number;
+}
+
+export interface PlayerDelta {
+  id?: bigint;
+  x?: number;
+  y?: number;
+  name?: string;
+  health?: number;
+  tags?: string[];
+  weapon?: delta.InterfaceDelta;
+  level?: number;
+  score?: number;
+}
+
+export function newPlayer(): Player {
+  return {
+    id: 0n,
+    x: 0,
+    y: 0,
+    name: "",
+    health: 0,
+    tags: [],
+    weapon: null,
+    level: 0,
+    score: 0,
+  };
+}
+
+export function getPlayerID(e: Player): bigint {
+  return e.id;
+}
+
+export function deserializePlayerDelta(r: delta.BinaryReader): PlayerDelta {
+  const d: PlayerDelta = {};
+  const mask = r.readUint64();
+  if (delta.hasField(mask, 0)) {
+    d.id = r.readInt64();
+  }
+  if (delta.hasField(mask, 1)) {
+    d.x = r.readFloat64();
+  }
+  if (delta.hasField(mask, 2)) {
+    d.y = r.readFloat64();
+  }
+  if (delta.hasField(mask, 3)) {
+    d.name = r.readString();
+  }
+  if (delta.hasField(mask, 4)) {
+    d.health = r.readInt32();
+  }
+  if (delta.hasField(mask, 5)) {
+    d.tags = delta.readSlice(r, (r) => r.readString());
+  }
+  if (delta.hasField(mask, 6)) {
+    d.weapon = delta.readInterface(r);
+  }
+  if (delta.hasField(mask, 7)) {
+    d.level = r.readInt32();
+  }
+  if (delta.hasField(mask, 8)) {
+    d.score = r.readInt32();
+  }
+  return d;
+}
+
+export function applyPlayerDelta(e: Player, d: PlayerDelta): void {
+  if (d.id !== undefined) {
+    e.id = d.id;
+  }
+  if (d.x !== undefined) {
+    e.x = d.x;
+  }
+  if (d.y !== undefined) {
+    e.y = d.y;
+  }
+  if (d.name !== undefined) {
+    e.name = d.name;
+  }
+  if (d.health !== undefined) {
+    e.health = d.health;
+  }
+  if (d.tags !== undefined) {
+    e.tags = d.tags;
+  }
+  if (d.weapon !== undefined) {
+    e.weapon = delta.applyInterface(e.weapon, d.weapon);
+  }
+  if (d.level !== undefined) {
+    e.level = d.level;
+  }
+  if (d.score !== undefined) {
+    e.score = d.score;
+  }
+}
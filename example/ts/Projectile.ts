@@ -0,0 +1,63 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+import * as delta from "./delta";
+
+export interface Projectile {
+  handle: number;
+  ownerID: bigint;
+  x: number;
+  y: number;
+}
+
+export interface ProjectileDelta {
+  handle?: number;
+  ownerID?: bigint;
+  x?: number;
+  y?: number;
+}
+
+export function newProjectile(): Projectile {
+  return {
+    handle: 0,
+    ownerID: 0n,
+    x: 0,
+    y: 0,
+  };
+}
+
+export function getProjectileID(e: Projectile): number {
+  return e.handle;
+}
+
+export function deserializeProjectileDelta(r: delta.BinaryReader): ProjectileDelta {
+  const d: ProjectileDelta = {};
+  const mask = r.readUint64();
+  if (delta.hasField(mask, 0)) {
+    d.handle = r.readUint32();
+  }
+  if (delta.hasField(mask, 1)) {
+    d.ownerID = r.readInt64();
+  }
+  if (delta.hasField(mask, 2)) {
+    d.x = r.readFloat32();
+  }
+  if (delta.hasField(mask, 3)) {
+    d.y = r.readFloat32();
+  }
+  return d;
+}
+
+export function applyProjectileDelta(e: Projectile, d: ProjectileDelta): void {
+  if (d.handle !== undefined) {
+    e.handle = d.handle;
+  }
+  if (d.ownerID !== undefined) {
+    e.ownerID = d.ownerID;
+  }
+  if (d.x !== undefined) {
+    e.x = d.x;
+  }
+  if (d.y !== undefined) {
+    e.y = d.y;
+  }
+}
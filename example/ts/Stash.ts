@@ -0,0 +1,118 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+import * as delta from "./delta";
+import { Item, ItemDelta, applyItemDelta, deserializeItemDelta, getItemID, newItem } from "./Item";
+
+export interface Stash {
+  id: bigint;
+  owner: bigint;
+  items: Item[];
+  slots: Map<string, Item>;
+  best: number;
+  grade: number;
+  grades: Map<number, number>;
+  history: number[];
+  locks: Set<string>;
+  cooldown: bigint;
+}
+
+export interface StashDelta {
+  id?: bigint;
+  owner?: bigint;
+  items?: delta.CollectionDelta<bigint, ItemDelta>;
+  slots?: delta.CollectionDelta<string, ItemDelta>;
+  best?: number;
+  grade?: number;
+  grades?: Map<number, number>;
+  history?: number[];
+  locks?: delta.SetDelta<string>;
+  cooldown?: bigint;
+}
+
+export function newStash(): Stash {
+  return {
+    id: 0n,
+    owner: 0n,
+    items: [],
+    slots: new Map(),
+    best: 0,
+    grade: 0,
+    grades: new Map(),
+    history: [],
+    locks: new Set(),
+    cooldown: 0n,
+  };
+}
+
+export function getStashID(e: Stash): bigint {
+  return e.id;
+}
+
+export function deserializeStashDelta(r: delta.BinaryReader): StashDelta {
+  const d: StashDelta = {};
+  const mask = r.readUint64();
+  if (delta.hasField(mask, 0)) {
+    d.id = r.readInt64();
+  }
+  if (delta.hasField(mask, 1)) {
+    d.owner = r.readInt64();
+  }
+  if (delta.hasField(mask, 2)) {
+    d.items = delta.readCollection(r, (r) => r.readInt64(), deserializeItemDelta);
+  }
+  if (delta.hasField(mask, 3)) {
+    d.slots = delta.readCollection(r, (r) => r.readString(), deserializeItemDelta);
+  }
+  if (delta.hasField(mask, 4)) {
+    d.best = r.readUint8();
+  }
+  if (delta.hasField(mask, 5)) {
+    d.grade = r.readInt32();
+  }
+  if (delta.hasField(mask, 6)) {
+    d.grades = delta.readMap(r, (r) => r.readUint8(), (r) => r.readInt32());
+  }
+  if (delta.hasField(mask, 7)) {
+    d.history = delta.readSlice(r, (r) => r.readInt32());
+  }
+  if (delta.hasField(mask, 8)) {
+    d.locks = delta.readSet(r, (r) => r.readString());
+  }
+  if (delta.hasField(mask, 9)) {
+    d.cooldown = r.readInt64();
+  }
+  return d;
+}
+
+export function applyStashDelta(e: Stash, d: StashDelta): void {
+  if (d.id !== undefined) {
+    e.id = d.id;
+  }
+  if (d.owner !== undefined) {
+    e.owner = d.owner;
+  }
+  if (d.items !== undefined) {
+    e.items = delta.applySlice(e.items, d.items, getItemID, newItem, applyItemDelta);
+  }
+  if (d.slots !== undefined) {
+    delta.applyMap(e.slots, d.slots, newItem, applyItemDelta);
+  }
+  if (d.best !== undefined) {
+    e.best = d.best;
+  }
+  if (d.grade !== undefined) {
+    e.grade = d.grade;
+  }
+  if (d.grades !== undefined) {
+    e.grades = d.grades;
+  }
+  if (d.history !== undefined) {
+    e.history = d.history;
+  }
+  if (d.locks !== undefined) {
+    delta.applySet(e.locks, d.locks);
+  }
+  if (d.cooldown !== undefined) {
+    e.cooldown = d.cooldown;
+  }
+}
@@ -0,0 +1,90 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+import * as delta from "./delta";
+
+export interface Stats<T> {
+  id: bigint;
+  current: T;
+  max: T;
+  history: T[];
+  byName: Map<string, T>;
+  counts: Map<T, number>;
+  bonus: Map<string, number>;
+}
+
+export interface StatsDelta<T> {
+  id?: bigint;
+  current?: T;
+  max?: T;
+  history?: T[];
+  byName?: Map<string, T>;
+  counts?: Map<T, number>;
+  bonus?: Map<string, number>;
+}
+
+export function newStats<T>(zeroT: T): Stats<T> {
+  return {
+    id: 0n,
+    current: zeroT,
+    max: zeroT,
+    history: [],
+    byName: new Map(),
+    counts: new Map(),
+    bonus: new Map(),
+  };
+}
+
+export function getStatsID<T>(e: Stats<T>): bigint {
+  return e.id;
+}
+
+export function deserializeStatsDelta<T>(r: delta.BinaryReader, readT: delta.Reader<T>): StatsDelta<T> {
+  const d: StatsDelta<T> = {};
+  const mask = r.readUint64();
+  if (delta.hasField(mask, 0)) {
+    d.id = r.readInt64();
+  }
+  if (delta.hasField(mask, 1)) {
+    d.current = readT(r);
+  }
+  if (delta.hasField(mask, 2)) {
+    d.max = readT(r);
+  }
+  if (delta.hasField(mask, 3)) {
+    d.history = delta.readSlice(r, readT);
+  }
+  if (delta.hasField(mask, 4)) {
+    d.byName = delta.readMap(r, (r) => r.readString(), readT);
+  }
+  if (delta.hasField(mask, 5)) {
+    d.counts = delta.readMap(r, readT, (r) => r.readInt32());
+  }
+  if (delta.hasField(mask, 6)) {
+    d.bonus = delta.readMap(r, (r) => r.readString(), (r) => r.readInt32());
+  }
+  return d;
+}
+
+export function applyStatsDelta<T>(e: Stats<T>, d: StatsDelta<T>): void {
+  if (d.id !== undefined) {
+    e.id = d.id;
+  }
+  if (d.current !== undefined) {
+    e.current = d.current;
+  }
+  if (d.max !== undefined) {
+    e.max = d.max;
+  }
+  if (d.history !== undefined) {
+    e.history = d.history;
+  }
+  if (d.byName !== undefined) {
+    e.byName = d.byName;
+  }
+  if (d.counts !== undefined) {
+    e.counts = d.counts;
+  }
+  if (d.bonus !== undefined) {
+    e.bonus = d.bonus;
+  }
+}
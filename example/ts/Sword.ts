@@ -0,0 +1,51 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+import * as delta from "./delta";
+
+export interface Sword {
+  id: bigint;
+  sharpness: number;
+}
+
+export interface SwordDelta {
+  id?: bigint;
+  sharpness?: number;
+}
+
+export function newSword(): Sword {
+  return {
+    id: 0n,
+    sharpness: 0,
+  };
+}
+
+export function getSwordID(e: Sword): bigint {
+  return e.id;
+}
+
+export function deserializeSwordDelta(r: delta.BinaryReader): SwordDelta {
+  const d: SwordDelta = {};
+  const mask = r.readUint64();
+  if (delta.hasField(mask, 0)) {
+    d.id = r.readInt64();
+  }
+  if (delta.hasField(mask, 1)) {
+    d.sharpness = r.readInt32();
+  }
+  return d;
+}
+
+export function applySwordDelta(e: Sword, d: SwordDelta): void {
+  if (d.id !== undefined) {
+    e.id = d.id;
+  }
+  if (d.sharpness !== undefined) {
+    e.sharpness = d.sharpness;
+  }
+}
+
+delta.registerType(1, {
+  create: newSword,
+  read: deserializeSwordDelta,
+  apply: (e, d) => applySwordDelta(e as Sword, d as SwordDelta),
+});
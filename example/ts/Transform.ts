@@ -0,0 +1,63 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+import * as delta from "./delta";
+
+export interface Transform {
+  id: bigint;
+  position: unknown;
+  velocity: unknown;
+  updated: unknown;
+}
+
+export interface TransformDelta {
+  id?: bigint;
+  position?: unknown;
+  velocity?: unknown;
+  updated?: unknown;
+}
+
+export function newTransform(): Transform {
+  return {
+    id: 0n,
+    position: null,
+    velocity: null,
+    updated: null,
+  };
+}
+
+export function getTransformID(e: Transform): bigint {
+  return e.id;
+}
+
+export function deserializeTransformDelta(r: delta.BinaryReader): TransformDelta {
+  const d: TransformDelta = {};
+  const mask = r.readUint64();
+  if (delta.hasField(mask, 0)) {
+    d.id = r.readInt64();
+  }
+  if (delta.hasField(mask, 1)) {
+    d.position = delta.readCodec("Vec3", r);
+  }
+  if (delta.hasField(mask, 2)) {
+    d.velocity = delta.readCodec("Vec3", r);
+  }
+  if (delta.hasField(mask, 3)) {
+    d.updated = delta.readCodec("time", r);
+  }
+  return d;
+}
+
+export function applyTransformDelta(e: Transform, d: TransformDelta): void {
+  if (d.id !== undefined) {
+    e.id = d.id;
+  }
+  if (d.position !== undefined) {
+    e.position = d.position;
+  }
+  if (d.velocity !== undefined) {
+    e.velocity = d.velocity;
+  }
+  if (d.updated !== undefined) {
+    e.updated = d.updated;
+  }
+}
@@ -0,0 +1,72 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+import * as delta from "./delta";
+
+export interface Unit {
+  id: bigint;
+  kind: string;
+  hp: number;
+  buffs: Set<string>;
+  flags: Set<number>;
+}
+
+export interface UnitDelta {
+  id?: bigint;
+  kind?: string;
+  hp?: number;
+  buffs?: delta.SetDelta<string>;
+  flags?: delta.SetDelta<number>;
+}
+
+export function newUnit(): Unit {
+  return {
+    id: 0n,
+    kind: "",
+    hp: 0,
+    buffs: new Set(),
+    flags: new Set(),
+  };
+}
+
+export function getUnitID(e: Unit): bigint {
+  return e.id;
+}
+
+export function deserializeUnitDelta(r: delta.BinaryReader): UnitDelta {
+  const d: UnitDelta = {};
+  const mask = r.readUint64();
+  if (delta.hasField(mask, 0)) {
+    d.id = r.readInt64();
+  }
+  if (delta.hasField(mask, 1)) {
+    d.kind = r.readString();
+  }
+  if (delta.hasField(mask, 2)) {
+    d.hp = r.readInt32();
+  }
+  if (delta.hasField(mask, 3)) {
+    d.buffs = delta.readSet(r, (r) => r.readString());
+  }
+  if (delta.hasField(mask, 4)) {
+    d.flags = delta.readSet(r, (r) => r.readInt32());
+  }
+  return d;
+}
+
+export function applyUnitDelta(e: Unit, d: UnitDelta): void {
+  if (d.id !== undefined) {
+    e.id = d.id;
+  }
+  if (d.kind !== undefined) {
+    e.kind = d.kind;
+  }
+  if (d.hp !== undefined) {
+    e.hp = d.hp;
+  }
+  if (d.buffs !== undefined) {
+    delta.applySet(e.buffs, d.buffs);
+  }
+  if (d.flags !== undefined) {
+    delta.applySet(e.flags, d.flags);
+  }
+}
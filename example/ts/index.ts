@@ -0,0 +1,15 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+export * from "./delta";
+export * from "./Item";
+export * from "./GameState";
+export * from "./Level";
+export * from "./Unit";
+export * from "./Lobby";
+export * from "./Player";
+export * from "./Projectile";
+export * from "./Stash";
+export * from "./Stats";
+export * from "./Transform";
+export * from "./Sword";
+export * from "./Bow";
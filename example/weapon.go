@@ -0,0 +1,27 @@
+package example
+
+// Weapon is held by players; its concrete type varies at runtime
+type Weapon interface {
+	Damage() int32
+}
+
+// delta:entity typeid=1
+type Sword struct {
+	ID        int64
+	Sharpness int32
+}
+
+func (s *Sword) Damage() int32 {
+	return s.Sharpness * 2
+}
+
+// delta:entity typeid=2
+type Bow struct {
+	ID     int64
+	Arrows int32
+	Range  float32
+}
+
+func (b *Bow) Damage() int32 {
+	return 5
+}
@@ -0,0 +1,369 @@
+package gen
+
+import (
+	_ "embed"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// csRuntime is the C# runtime decoding the varint, collection, set and
+// interface encodings of BinaryWriter with a System.IO.BinaryReader, written
+// next to the generated classes as Delta.cs
+//
+//go:embed csharp/Delta.cs
+var csRuntime []byte
+
+// csFile is the data passed to the "class" template: an entity, the C#
+// namespace of its package and the namespaces of the entities it holds
+type csFile struct {
+	StructInfo
+	Namespace string
+	Usings    []string
+}
+
+// csNamespace returns the C# namespace of the classes generated for a Go
+// package, e.g. Inventory for package inventory
+func csNamespace(pkg string) string {
+	runes := []rune(pkg)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// csTypeOf returns the C# type decoded for a Go type
+func csTypeOf(typeStr string) string {
+	switch typeStr {
+	case "bool", "byte", "string":
+		return typeStr
+	case "int8":
+		return "sbyte"
+	case "int16":
+		return "short"
+	case "int32", "rune":
+		return "int"
+	case "int64":
+		return "long"
+	case "uint8":
+		return "byte"
+	case "uint16":
+		return "ushort"
+	case "uint32":
+		return "uint"
+	case "uint64":
+		return "ulong"
+	case "float32":
+		return "float"
+	case "float64":
+		return "double"
+	case "[]byte":
+		return "byte[]"
+	}
+	switch {
+	case isSliceType(typeStr):
+		return fmt.Sprintf("List<%s>", csTypeOf(getSliceElementType(typeStr)))
+	case isMapType(typeStr):
+		return fmt.Sprintf("Dictionary<%s, %s>", csTypeOf(getMapKeyType(typeStr)), csTypeOf(getMapValueType(typeStr)))
+	}
+	// A type parameter
+	return typeStr
+}
+
+// csType returns the C# type of a field in the entity class
+func csType(f FieldInfo) string {
+	switch {
+	case hasCodec(f), f.Interface:
+		return "object?"
+	case f.Elem != "" && isSliceType(f.Type):
+		return fmt.Sprintf("List<%s>", tsElem(f.Elem))
+	case f.Elem != "":
+		return fmt.Sprintf("Dictionary<%s, %s>", csTypeOf(getMapKeyType(f.Type)), tsElem(f.Elem))
+	case isSetType(f.Type):
+		return fmt.Sprintf("HashSet<%s>", csTypeOf(getMapKeyType(f.Type)))
+	}
+	return csTypeOf(f.Type)
+}
+
+// csDeltaType returns the nullable C# type of a field in the delta class,
+// which is null when the field is unchanged
+func csDeltaType(f FieldInfo) string {
+	switch {
+	case hasCodec(f):
+		return "object?"
+	case f.Interface:
+		return "InterfaceDelta?"
+	case f.Elem != "":
+		return fmt.Sprintf("CollectionDelta<%s, %sDelta>?", csTypeOf(getCollectionKeyType(f)), tsElem(f.Elem))
+	case isSetType(f.Type):
+		return fmt.Sprintf("SetDelta<%s>?", csTypeOf(getMapKeyType(f.Type)))
+	case isTypeParam(f.Type):
+		// T? is not nullable when T is a value type
+		return fmt.Sprintf("Optional<%s>?", f.Type)
+	}
+	return csTypeOf(f.Type) + "?"
+}
+
+// csInit returns the initializer of a field in the entity class, matching the
+// Go zero value except that nil collections are empty. Fields whose zero value
+// is the C# default have none.
+func csInit(f FieldInfo) string {
+	switch {
+	case hasCodec(f), f.Interface:
+		return ""
+	case isTypeParam(f.Type):
+		return " = default!"
+	case f.Type == "string":
+		return ` = ""`
+	case f.Type == "[]byte":
+		return " = new byte[0]"
+	case f.Elem != "" || isSetType(f.Type) || isSliceType(f.Type) || isMapType(f.Type):
+		return fmt.Sprintf(" = new %s()", csType(f))
+	}
+	return ""
+}
+
+// csRead returns an expression reading the delta of a field from r
+func csRead(f FieldInfo, r string) string {
+	switch {
+	case f.Codec != "":
+		return fmt.Sprintf("Codecs.Read(%q, %s)", f.Codec, r)
+	case f.Marshaler:
+		return fmt.Sprintf("Codecs.Read(%q, %s)", f.Type, r)
+	case f.Interface:
+		return fmt.Sprintf("InterfaceDelta.Read(%s)", r)
+	case f.Elem != "":
+		return fmt.Sprintf("DeltaReader.ReadCollection(%s, %s, %sDelta.Deserialize)", r, csReader(getCollectionKeyType(f), 1), tsElem(f.Elem))
+	case isSetType(f.Type):
+		return fmt.Sprintf("DeltaReader.ReadSet(%s, %s)", r, csReader(getMapKeyType(f.Type), 1))
+	case isTypeParam(f.Type):
+		return fmt.Sprintf("new Optional<%s>(read%s(%s))", f.Type, f.Type, r)
+	}
+	return csReadType(f.Type, r, 1)
+}
+
+// csReadType returns an expression reading a value of a Go type from r.
+// Readers of nested values are lambdas taking r<depth>, so that no lambda
+// parameter shadows another.
+func csReadType(typeStr, r string, depth int) string {
+	switch typeStr {
+	case "bool":
+		return r + ".ReadBoolean()"
+	case "int8":
+		return r + ".ReadSByte()"
+	case "int16":
+		return r + ".ReadInt16()"
+	case "int32", "rune":
+		return r + ".ReadInt32()"
+	case "int64":
+		return r + ".ReadInt64()"
+	case "uint8", "byte":
+		return r + ".ReadByte()"
+	case "uint16":
+		return r + ".ReadUInt16()"
+	case "uint32":
+		return r + ".ReadUInt32()"
+	case "uint64":
+		return r + ".ReadUInt64()"
+	case "float32":
+		return r + ".ReadSingle()"
+	case "float64":
+		return r + ".ReadDouble()"
+	case "string":
+		return r + ".ReadDeltaString()"
+	case "[]byte":
+		return r + ".ReadDeltaBytes()"
+	}
+	switch {
+	case isSliceType(typeStr):
+		return fmt.Sprintf("DeltaReader.ReadSlice(%s, %s)", r, csReader(getSliceElementType(typeStr), depth))
+	case isMapType(typeStr):
+		return fmt.Sprintf("DeltaReader.ReadMap(%s, %s, %s)", r, csReader(getMapKeyType(typeStr), depth), csReader(getMapValueType(typeStr), depth))
+	}
+	return fmt.Sprintf("read%s(%s)", typeStr, r)
+}
+
+// csReader returns a Func<BinaryReader, T> reading a value of a Go type
+func csReader(typeStr string, depth int) string {
+	if isTypeParam(typeStr) {
+		return "read" + typeStr
+	}
+	r := fmt.Sprintf("r%d", depth)
+	return fmt.Sprintf("%s => %s", r, csReadType(typeStr, r, depth+1))
+}
+
+// csApply returns the statement applying the delta of a field, a field of the
+// delta class, to e
+func csApply(f FieldInfo) string {
+	name := f.Name
+	switch {
+	case hasCodec(f):
+		return fmt.Sprintf("e.%s = %s;", name, name)
+	case f.Interface:
+		return fmt.Sprintf("e.%s = %s.ApplyTo(e.%s);", name, name, name)
+	case f.Elem != "" && isSliceType(f.Type):
+		elem := tsElem(f.Elem)
+		return fmt.Sprintf("e.%s = %s.ApplyTo(e.%s, x => x.GetID(), () => new %s(), (x, xd) => xd.ApplyTo(x));", name, name, name, elem)
+	case f.Elem != "":
+		elem := tsElem(f.Elem)
+		return fmt.Sprintf("%s.ApplyTo(e.%s, () => new %s(), (x, xd) => xd.ApplyTo(x));", name, name, elem)
+	case isSetType(f.Type):
+		return fmt.Sprintf("%s.ApplyTo(e.%s);", name, name)
+	case isTypeParam(f.Type):
+		return fmt.Sprintf("e.%s = %s.Value.Value;", name, name)
+	}
+	switch csTypeOf(f.Type) {
+	case "bool", "sbyte", "short", "int", "long", "byte", "ushort", "uint", "ulong", "float", "double":
+		return fmt.Sprintf("e.%s = %s.Value;", name, name)
+	}
+	return fmt.Sprintf("e.%s = %s;", name, name)
+}
+
+// csParams returns the type parameter list of a generic entity, e.g. "<T>"
+func csParams(s StructInfo) string {
+	return tsParams(s)
+}
+
+// csConstraints returns the constraints of the type parameters of a generic
+// entity, which may be dictionary keys
+func csConstraints(s StructInfo) string {
+	var b strings.Builder
+	for _, p := range s.Params {
+		fmt.Fprintf(&b, " where %s : notnull", p.Name)
+	}
+	return b.String()
+}
+
+// csReaderParams returns the extra parameters of <Name>Delta.Deserialize
+// taking a reader for each type parameter of a generic entity, e.g.
+// ", Func<BinaryReader, T> readT"
+func csReaderParams(s StructInfo) string {
+	var b strings.Builder
+	for _, p := range s.Params {
+		fmt.Fprintf(&b, ", Func<BinaryReader, %s> read%s", p.Name, p.Name)
+	}
+	return b.String()
+}
+
+// csFiles renders the C# classes of structs: one file per entity holding the
+// entity and delta classes, and the runtime, per output directory
+func csFiles(output string, structs []StructInfo, tmpl *template.Template) ([]File, error) {
+	if strings.HasSuffix(output, ".go") || strings.HasSuffix(output, ".cs") {
+		return nil, fmt.Errorf("output %s must be a directory for C#", output)
+	}
+
+	dirs, byDir, err := groupDirs(output, structs, ".cs")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []File
+	for _, dir := range dirs {
+		namespaces := make(map[string]string)
+		for _, s := range byDir[dir] {
+			namespaces[s.Name] = csNamespace(s.PackageName)
+		}
+
+		files = append(files, File{Path: filepath.Join(dir, "Delta.cs"), Content: csRuntime})
+		for _, s := range byDir[dir] {
+			data := csFile{StructInfo: s, Namespace: csNamespace(s.PackageName)}
+			for _, f := range s.Fields {
+				ns, ok := namespaces[tsElem(f.Elem)]
+				if f.Elem != "" && ok && ns != data.Namespace && !slices.Contains(data.Usings, ns) {
+					data.Usings = append(data.Usings, ns)
+				}
+			}
+			slices.Sort(data.Usings)
+
+			var buf strings.Builder
+			if err := tmpl.ExecuteTemplate(&buf, "class", data); err != nil {
+				return nil, fmt.Errorf("struct %s: executing template: %w", s.Name, err)
+			}
+			files = append(files, File{Path: filepath.Join(dir, s.Name+".cs"), Content: []byte(buf.String())})
+		}
+	}
+	return files, nil
+}
+
+var csTemplates = template.Must(template.New("class").Funcs(template.FuncMap{
+	"csType":         csType,
+	"csTypeOf":       csTypeOf,
+	"csDeltaType":    csDeltaType,
+	"csInit":         csInit,
+	"csRead":         csRead,
+	"csApply":        csApply,
+	"csParams":       csParams,
+	"csConstraints":  csConstraints,
+	"csReaderParams": csReaderParams,
+}).Parse(`
+{{- define "class"}}// Code generated by deltagen. DO NOT EDIT.
+
+#nullable enable
+
+using System;
+using System.Collections.Generic;
+using System.IO;
+using Delta;
+{{- range .Usings}}
+using {{.}};
+{{- end}}
+
+namespace {{.Namespace}}
+{
+    public partial class {{.Name}}{{csParams .StructInfo}}{{csConstraints .StructInfo}}
+    {
+    {{- range .Fields}}
+        public {{csType .}} {{.Name}}{{csInit .}};
+    {{- end}}
+
+        public {{csTypeOf .IDType}} GetID()
+        {
+            return {{.IDField}};
+        }
+    {{- if .TypeID}}
+
+        /// <summary>Registers {{.Name}} under type ID {{.TypeID}} so interface fields holding it can be decoded.</summary>
+        public static void RegisterType()
+        {
+            TypeRegistry.Register({{.TypeID}}, new RegisteredType(
+                () => new {{.Name}}(),
+                r => {{.Name}}Delta.Deserialize(r),
+                (e, d) => (({{.Name}}Delta)d).ApplyTo(({{.Name}})e)));
+        }
+    {{- end}}
+    }
+
+    public partial class {{.Name}}Delta{{csParams .StructInfo}}{{csConstraints .StructInfo}}
+    {
+    {{- range .Fields}}
+        public {{csDeltaType .}} {{.Name}};
+    {{- end}}
+
+        public static {{.Name}}Delta{{csParams .StructInfo}} Deserialize(BinaryReader r{{csReaderParams .StructInfo}})
+        {
+            var d = new {{.Name}}Delta{{csParams .StructInfo}}();
+            var mask = r.ReadUInt64();
+        {{- range $i, $field := .Fields}}
+            if (DeltaReader.HasField(mask, {{$i}}))
+            {
+                d.{{$field.Name}} = {{csRead $field "r"}};
+            }
+        {{- end}}
+            return d;
+        }
+
+        public void ApplyTo({{.Name}}{{csParams .StructInfo}} e)
+        {
+        {{- range .Fields}}
+            if ({{.Name}} != null)
+            {
+                {{csApply .}}
+            }
+        {{- end}}
+        }
+    }
+    {{- block "extra" .}}{{end}}
+}
+{{end}}
+`))
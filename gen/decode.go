@@ -0,0 +1,340 @@
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cbodonnell/delta"
+)
+
+// DecodedDelta is a delta decoded with a Schema, recording where each part was
+// found in the encoded bytes
+type DecodedDelta struct {
+	Entity string         `json:"entity"`
+	Offset int            `json:"offset"`
+	Size   int            `json:"size"`
+	Mask   uint64         `json:"mask"`
+	Fields []DecodedField `json:"fields"`
+}
+
+// DecodedField is a field present in a decoded delta. Value holds the Go value
+// of primitives, []any for slices, []MapEntry for maps, and *SetValue,
+// *CollectionValue or *InterfaceValue for those encodings. It is nil or partly
+// decoded when decoding failed.
+type DecodedField struct {
+	Name   string `json:"name"`
+	Number int    `json:"number"`
+	Type   string `json:"type"`
+	Offset int    `json:"offset"`
+	Size   int    `json:"size"`
+	Value  any    `json:"value"`
+}
+
+// MapEntry is an entry of a decoded map
+type MapEntry struct {
+	Key   any `json:"key"`
+	Value any `json:"value"`
+}
+
+// SetValue is a decoded set delta
+type SetValue struct {
+	Added   []any `json:"added"`
+	Removed []any `json:"removed"`
+}
+
+// CollectionValue is a decoded collection delta. Order is nil unless the
+// delta carries one.
+type CollectionValue struct {
+	Added   []CollectionEntry `json:"added"`
+	Changed []CollectionEntry `json:"changed"`
+	Removed []any             `json:"removed"`
+	Order   []any             `json:"order,omitempty"`
+}
+
+// CollectionEntry is the delta of an entity added to or changed in a
+// collection
+type CollectionEntry struct {
+	Key   any           `json:"key"`
+	Delta *DecodedDelta `json:"delta"`
+}
+
+// InterfaceValue is a decoded interface delta. Delta is nil when the field
+// was set to nil.
+type InterfaceValue struct {
+	TypeID uint32        `json:"typeID"`
+	Full   bool          `json:"full"`
+	Delta  *DecodedDelta `json:"delta,omitempty"`
+}
+
+// Entity returns the entity with the given name, which is qualified by its
+// package import path when several packages have entities of that name
+func (s Schema) Entity(name string) (*EntitySchema, error) {
+	var found *EntitySchema
+	for i := range s.Entities {
+		e := &s.Entities[i]
+		if e.Name != name && e.Package+"."+e.Name != name {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("entity %s is ambiguous: qualify it as %s.%s or %s.%s", name, found.Package, found.Name, e.Package, e.Name)
+		}
+		found = e
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no entity named %s in the schema", name)
+	}
+	return found, nil
+}
+
+// DecodeDelta decodes the delta of the named entity at the start of data
+// without the generated code, using only the schema. Bytes following the delta
+// are not read. When decoding fails part way through, the fields decoded so
+// far are returned along with the error.
+func DecodeDelta(schema Schema, entity string, data []byte) (*DecodedDelta, error) {
+	e, err := schema.Entity(entity)
+	if err != nil {
+		return nil, err
+	}
+	d := &decoder{schema: schema, data: bytes.NewReader(data), size: len(data)}
+	d.br = delta.NewBinaryReader(d.data)
+	return d.delta(e)
+}
+
+// decoder reads values described by a schema, tracking the offset into the
+// data
+type decoder struct {
+	schema Schema
+	data   *bytes.Reader
+	br     *delta.BinaryReader
+	size   int
+}
+
+// offset returns the number of bytes read so far
+func (d *decoder) offset() int {
+	return d.size - d.data.Len()
+}
+
+// delta decodes the delta of entity e
+func (d *decoder) delta(e *EntitySchema) (*DecodedDelta, error) {
+	dd := &DecodedDelta{Entity: e.Name, Offset: d.offset(), Fields: []DecodedField{}}
+	defer func() { dd.Size = d.offset() - dd.Offset }()
+
+	mask, err := d.br.ReadUint64()
+	if err != nil {
+		return dd, delta.WrapDecodeError(e.Name, "", int64(dd.Offset), err)
+	}
+	dd.Mask = mask
+
+	for _, f := range e.Fields {
+		if f.Number >= 64 || mask&(1<<f.Number) == 0 {
+			continue
+		}
+		mask &^= 1 << f.Number
+
+		field := DecodedField{Name: f.Name, Number: f.Number, Type: f.Type, Offset: d.offset()}
+		field.Value, err = d.value(f.Encoding)
+		field.Size = d.offset() - field.Offset
+		dd.Fields = append(dd.Fields, field)
+		if err != nil {
+			return dd, delta.WrapDecodeError(e.Name, f.Name, int64(field.Offset), err)
+		}
+	}
+	if mask != 0 {
+		return dd, fmt.Errorf("%s: presence mask %#x has bits set for fields not in the schema", e.Name, mask)
+	}
+	return dd, nil
+}
+
+// value decodes a value with encoding enc. Errors are *delta.DecodeError
+// values reporting the offset of the innermost value that could not be
+// decoded.
+func (d *decoder) value(enc Encoding) (any, error) {
+	start := d.offset()
+	v, err := d.read(enc)
+	if err == nil {
+		return v, nil
+	}
+	if !errors.As(err, new(*delta.DecodeError)) {
+		// Not a read failure, such as an encoding that cannot be decoded
+		return nil, &delta.DecodeError{Offset: int64(start), Err: err}
+	}
+	switch enc.Kind {
+	case "slice", "map", "set", "collection", "interface":
+		// Partly decoded
+		return v, err
+	}
+	return nil, err
+}
+
+func (d *decoder) read(enc Encoding) (any, error) {
+	switch {
+	case (enc.Kind == "slice" || enc.Kind == "map") && enc.Elem == nil,
+		(enc.Kind == "map" || enc.Kind == "set" || enc.Kind == "collection") && enc.Key == nil:
+		return nil, fmt.Errorf("%s encoding is missing its key or element encoding", enc.Kind)
+	}
+
+	switch enc.Kind {
+	case "bool":
+		return d.br.ReadBool()
+	case "int8":
+		return d.br.ReadInt8()
+	case "int16":
+		return d.br.ReadInt16()
+	case "int32":
+		return d.br.ReadInt32()
+	case "int64":
+		return d.br.ReadInt64()
+	case "uint8":
+		return d.br.ReadUint8()
+	case "uint16":
+		return d.br.ReadUint16()
+	case "uint32":
+		return d.br.ReadUint32()
+	case "uint64":
+		return d.br.ReadUint64()
+	case "float32":
+		return d.br.ReadFloat32()
+	case "float64":
+		return d.br.ReadFloat64()
+	case "string":
+		return d.br.ReadString()
+	case "bytes":
+		return d.br.ReadBytes()
+	case "slice":
+		return d.slice(*enc.Elem)
+	case "map":
+		return d.mapEntries(*enc.Key, *enc.Elem)
+	case "set":
+		return d.set(*enc.Key)
+	case "collection":
+		return d.collection(*enc.Key, enc.Entity)
+	case "interface":
+		return d.iface()
+	case "codec":
+		return nil, fmt.Errorf("values of codec %q cannot be decoded without the codec", enc.Codec)
+	case "param":
+		return nil, fmt.Errorf("values of type parameter %s cannot be decoded without its type argument", enc.Param)
+	}
+	return nil, fmt.Errorf("unknown encoding %q", enc.Kind)
+}
+
+// slice decodes a varint length followed by that many elements
+func (d *decoder) slice(elem Encoding) ([]any, error) {
+	length, err := d.length()
+	if err != nil {
+		return nil, err
+	}
+	s := make([]any, 0, length)
+	for i := 0; i < length; i++ {
+		v, err := d.value(elem)
+		if err != nil {
+			return s, err
+		}
+		s = append(s, v)
+	}
+	return s, nil
+}
+
+// mapEntries decodes a varint length followed by that many keys and values
+func (d *decoder) mapEntries(key, elem Encoding) ([]MapEntry, error) {
+	length, err := d.length()
+	if err != nil {
+		return nil, err
+	}
+	m := make([]MapEntry, 0, length)
+	for i := 0; i < length; i++ {
+		k, err := d.value(key)
+		if err != nil {
+			return m, err
+		}
+		v, err := d.value(elem)
+		m = append(m, MapEntry{Key: k, Value: v})
+		if err != nil {
+			return m, err
+		}
+	}
+	return m, nil
+}
+
+// set decodes the added keys followed by the removed keys
+func (d *decoder) set(key Encoding) (*SetValue, error) {
+	sv := &SetValue{}
+	var err error
+	if sv.Added, err = d.slice(key); err != nil {
+		return sv, err
+	}
+	sv.Removed, err = d.slice(key)
+	return sv, err
+}
+
+// collection decodes a collection delta of the named entity
+func (d *decoder) collection(key Encoding, entity string) (*CollectionValue, error) {
+	e, err := d.schema.Entity(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	cv := &CollectionValue{}
+	for _, entries := range []*[]CollectionEntry{&cv.Added, &cv.Changed} {
+		length, err := d.length()
+		if err != nil {
+			return cv, err
+		}
+		*entries = make([]CollectionEntry, 0, length)
+		for i := 0; i < length; i++ {
+			k, err := d.value(key)
+			if err != nil {
+				return cv, err
+			}
+			dd, err := d.delta(e)
+			*entries = append(*entries, CollectionEntry{Key: k, Delta: dd})
+			if err != nil {
+				return cv, delta.WrapDecodeError(e.Name, fmt.Sprintf("[%v]", k), int64(dd.Offset), err)
+			}
+		}
+	}
+
+	if cv.Removed, err = d.slice(key); err != nil {
+		return cv, err
+	}
+	hasOrder, err := d.br.ReadBool()
+	if err != nil || !hasOrder {
+		return cv, err
+	}
+	cv.Order, err = d.slice(key)
+	return cv, err
+}
+
+// iface decodes an interface delta, looking up the entity by its type ID
+func (d *decoder) iface() (*InterfaceValue, error) {
+	typeID, err := d.br.ReadVarUint32()
+	if err != nil || typeID == 0 {
+		return &InterfaceValue{}, err
+	}
+	iv := &InterfaceValue{TypeID: typeID}
+	if iv.Full, err = d.br.ReadBool(); err != nil {
+		return iv, err
+	}
+
+	for i := range d.schema.Entities {
+		if e := &d.schema.Entities[i]; e.TypeID == typeID {
+			iv.Delta, err = d.delta(e)
+			return iv, err
+		}
+	}
+	return iv, fmt.Errorf("no entity with type ID %d in the schema", typeID)
+}
+
+// length reads a varint length, which must not exceed the remaining data
+func (d *decoder) length() (int, error) {
+	length, err := d.br.ReadVarUint32()
+	if err != nil {
+		return 0, err
+	}
+	if int(length) > d.data.Len() {
+		return 0, fmt.Errorf("length %d exceeds the %d bytes remaining: %w", length, d.data.Len(), io.ErrUnexpectedEOF)
+	}
+	return int(length), nil
+}
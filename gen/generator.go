@@ -0,0 +1,346 @@
+// Package gen finds structs annotated with a "// delta:entity" comment and
+// generates the code implementing delta.Entity and delta.Delta for them. It
+// is the library behind the deltagen command, for build tools that generate
+// delta code alongside other code.
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/scanner"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// Config controls which structs Generate generates code for and where the
+// files are written
+type Config struct {
+	// Input is a Go source file, a package directory, or a directory followed
+	// by /... to include all packages beneath it
+	Input string
+
+	// Output is empty to place <package>_deltagen.go next to each package's
+	// sources, a directory to place them in, or a .go file when a single
+	// package is generated
+	Output string
+
+	// Types limits generation to the annotated structs with these names, which
+	// must all exist. All annotated structs are generated when it is empty.
+	// Go code written to one file per package still holds every entity of the
+	// packages declaring them, so Types selects the packages to regenerate;
+	// only an Output .go file holds the named structs alone.
+	Types []string
+
+	// Package limits generation to the named package, as when deltagen is run
+	// by go generate
+	Package string
+
+	// Templates lists template files or glob patterns that add to or override
+	// the built-in templates of the language
+	Templates []string
+
+	// Lang is the language of the generated code: "go" (the default), or
+	// "typescript" or "csharp" for clients decoding and applying the deltas
+	// that the Go code serializes. Client code is written to the Output
+	// directory, or next to each package's sources when it is empty.
+	Lang string
+
+	// Schema is the path of a JSON file describing the wire format of the
+	// generated entities, returned along with the code when it is not empty
+	Schema string
+}
+
+// File is a generated source file
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// PackageInfo groups the structs generated into a single output file. It is
+// the data passed to the "file" template.
+type PackageInfo struct {
+	Name    string
+	Dir     string
+	Structs []StructInfo
+	Imports []string // import specs needed by the structs, sorted
+}
+
+// Generate parses and validates the structs selected by cfg and returns one
+// gofmt-formatted file per package containing the code for all of its
+// structs, or the client code for cfg.Lang, followed by the schema when
+// requested. Nothing is written to disk. Unsupported fields are reported as
+// Diagnostics.
+func Generate(cfg Config) ([]File, error) {
+	structs, err := Parse(cfg.Input)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Package != "" {
+		structs = filterPackage(structs, cfg.Package)
+	}
+
+	var base *template.Template
+	switch cfg.Lang {
+	case "", "go":
+		base = templates
+	case "typescript", "ts":
+		base = tsTemplates
+	case "csharp", "cs":
+		base = csTemplates
+	default:
+		return nil, fmt.Errorf("unsupported language %q", cfg.Lang)
+	}
+
+	if len(cfg.Types) > 0 {
+		selected, err := filterTypes(structs, cfg.Types)
+		if err != nil {
+			return nil, err
+		}
+		// Rewriting a package's file with only the selected structs would
+		// delete the code of its other entities, which they may refer to
+		if base == templates && !strings.HasSuffix(cfg.Output, ".go") {
+			selected = packagesOf(structs, selected)
+		}
+		structs = selected
+	}
+	if err := Validate(structs); err != nil {
+		return nil, err
+	}
+
+	tmpl := base
+	if len(cfg.Templates) > 0 {
+		if tmpl, err = loadTemplates(base, cfg.Templates); err != nil {
+			return nil, err
+		}
+	}
+
+	var files []File
+	switch base {
+	case tsTemplates:
+		files, err = tsFiles(cfg.Output, wireStructs(structs), tmpl)
+	case csTemplates:
+		files, err = csFiles(cfg.Output, wireStructs(structs), tmpl)
+	default:
+		files, err = goFiles(cfg.Output, structs, tmpl)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Schema != "" {
+		schema, err := json.MarshalIndent(BuildSchema(structs), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Path: cfg.Schema, Content: append(schema, '\n')})
+	}
+	return files, nil
+}
+
+// wireStructs returns copies of structs whose field and ID types are replaced
+// by the types they are encoded as, for the generators of client code, which
+// decode named primitive types as their underlying types
+func wireStructs(structs []StructInfo) []StructInfo {
+	wired := make([]StructInfo, len(structs))
+	for i, s := range structs {
+		s.Fields = slices.Clone(s.Fields)
+		for j := range s.Fields {
+			s.Fields[j] = wireField(s.Fields[j])
+		}
+		if s.idWire != "" {
+			s.IDType = s.idWire
+		}
+		wired[i] = s
+	}
+	return wired
+}
+
+// wireField returns f with its type and the ID type of its entity elements
+// replaced by the types they are encoded as. Codec fields keep their type, which names the codec of DeltaMarshaler types.
+func wireField(f FieldInfo) FieldInfo {
+	if !hasCodec(f) {
+		f.Type = wireType(f)
+	}
+	if f.elemIDWire != "" {
+		f.ElemID = f.elemIDWire
+	}
+	return f
+}
+
+// goFiles renders one file per package containing the code for all of its
+// structs
+func goFiles(output string, structs []StructInfo, tmpl *template.Template) ([]File, error) {
+	packages := groupPackages(structs)
+	if strings.HasSuffix(output, ".go") && len(packages) > 1 {
+		return nil, fmt.Errorf("output file %s cannot hold %d packages", output, len(packages))
+	}
+
+	var files []File
+	written := make(map[string]string)
+	for _, pkg := range packages {
+		outputPath := outputFile(output, pkg)
+		if dir, ok := written[outputPath]; ok {
+			return nil, fmt.Errorf("packages in %s and %s would both be written to %s", dir, pkg.Dir, outputPath)
+		}
+		written[outputPath] = pkg.Dir
+
+		src, err := render(pkg, tmpl)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Path: outputPath, Content: src})
+	}
+	return files, nil
+}
+
+// filterPackage returns the structs declared in the named package
+func filterPackage(structs []StructInfo, name string) []StructInfo {
+	var filtered []StructInfo
+	for _, s := range structs {
+		if s.PackageName == name {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered
+}
+
+// packagesOf returns the structs of all that are declared in the packages of
+// selected, preserving their order
+func packagesOf(all, selected []StructInfo) []StructInfo {
+	wanted := make(map[string]bool)
+	for _, s := range selected {
+		wanted[s.Dir+":"+s.PackageName] = true
+	}
+	var structs []StructInfo
+	for _, s := range all {
+		if wanted[s.Dir+":"+s.PackageName] {
+			structs = append(structs, s)
+		}
+	}
+	return structs
+}
+
+// filterTypes returns the structs with the given names, which must all exist
+func filterTypes(structs []StructInfo, names []string) ([]StructInfo, error) {
+	wanted := make(map[string]bool)
+	for _, name := range names {
+		wanted[strings.TrimSpace(name)] = false
+	}
+
+	var filtered []StructInfo
+	for _, s := range structs {
+		if _, ok := wanted[s.Name]; ok {
+			wanted[s.Name] = true
+			filtered = append(filtered, s)
+		}
+	}
+
+	for name, found := range wanted {
+		if !found {
+			return nil, fmt.Errorf("no annotated struct named %s", name)
+		}
+	}
+	return filtered, nil
+}
+
+// render executes the template for pkg and formats the result with gofmt
+func render(pkg PackageInfo, tmpl *template.Template) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "file", pkg); err != nil {
+		return nil, fmt.Errorf("package %s in %s: executing template: %w", pkg.Name, pkg.Dir, err)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("package %s in %s: formatting generated code: %w%s", pkg.Name, pkg.Dir, err, sourceContext(buf.Bytes(), err))
+	}
+	return src, nil
+}
+
+// sourceContext returns the generated lines surrounding the first error in err,
+// so template bugs can be located without writing the broken file to disk
+func sourceContext(src []byte, err error) string {
+	var list scanner.ErrorList
+	if !errors.As(err, &list) || len(list) == 0 {
+		return ""
+	}
+
+	lines := strings.Split(string(src), "\n")
+	line := list[0].Pos.Line
+	var b strings.Builder
+	for i := max(line-3, 1); i <= min(line+2, len(lines)); i++ {
+		marker := "  "
+		if i == line {
+			marker = "> "
+		}
+		fmt.Fprintf(&b, "\n%s%4d | %s", marker, i, lines[i-1])
+	}
+	return b.String()
+}
+
+// groupPackages groups structs by package, preserving the order they were parsed in
+func groupPackages(structs []StructInfo) []PackageInfo {
+	var packages []PackageInfo
+	index := make(map[string]int)
+	for _, s := range structs {
+		key := s.Dir + ":" + s.PackageName
+		i, ok := index[key]
+		if !ok {
+			i = len(packages)
+			index[key] = i
+			packages = append(packages, PackageInfo{Name: s.PackageName, Dir: s.Dir})
+		}
+		packages[i].Structs = append(packages[i].Structs, s)
+		for _, imp := range s.Imports {
+			if !slices.Contains(packages[i].Imports, imp) {
+				packages[i].Imports = append(packages[i].Imports, imp)
+			}
+		}
+	}
+	for i := range packages {
+		slices.Sort(packages[i].Imports)
+	}
+	return packages
+}
+
+// groupDirs groups structs by the directory their client modules are written
+// to, output or else the package directory, preserving the order they were
+// parsed in. Modules are named after their struct, so names must be unique
+// within a directory.
+func groupDirs(output string, structs []StructInfo, ext string) ([]string, map[string][]StructInfo, error) {
+	var dirs []string
+	byDir := make(map[string][]StructInfo)
+	for _, s := range structs {
+		dir := output
+		if dir == "" {
+			dir = s.Dir
+		}
+		if _, ok := byDir[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		for _, other := range byDir[dir] {
+			if other.Name == s.Name {
+				return nil, nil, fmt.Errorf("structs %s in %s and %s would both be written to %s", s.Name, other.Dir, s.Dir, filepath.Join(dir, s.Name+ext))
+			}
+		}
+		byDir[dir] = append(byDir[dir], s)
+	}
+	return dirs, byDir, nil
+}
+
+// outputFile returns the path of the generated file for pkg
+func outputFile(output string, pkg PackageInfo) string {
+	switch {
+	case output == "":
+		return filepath.Join(pkg.Dir, pkg.Name+"_deltagen.go")
+	case strings.HasSuffix(output, ".go"):
+		return output
+	default:
+		return filepath.Join(output, pkg.Name+"_deltagen.go")
+	}
+}
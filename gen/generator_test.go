@@ -0,0 +1,161 @@
+package gen
+
+import (
+	"bytes"
+	"go/format"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate_Types(t *testing.T) {
+	// An output file holds only the selected structs
+	files, err := Generate(Config{Input: "testdata/fleet/ships", Output: filepath.Join("out", "ship.go"), Types: []string{"Ship"}})
+	if err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("Generate() returned %d files, want 1", len(files))
+	}
+	if !bytes.Contains(files[0].Content, []byte("type ShipDelta struct")) {
+		t.Errorf("Generate() did not generate the selected Ship")
+	}
+	if bytes.Contains(files[0].Content, []byte("DockDelta")) {
+		t.Errorf("Generate() generated Dock, which was not selected")
+	}
+
+	_, err = Generate(Config{Input: "testdata/fleet/ships", Types: []string{"Ship", "Boat"}})
+	if err == nil || !strings.Contains(err.Error(), "no annotated struct named Boat") {
+		t.Errorf("Generate() of an unknown type error = %v, want it to name Boat", err)
+	}
+}
+
+func TestGenerate_TypesKeepPackage(t *testing.T) {
+	// Two go:generate directives selecting different types of a package
+	// write the same file, which must hold both, and Dock refers to Ship
+	var generated [][]byte
+	for _, name := range []string{"Ship", "Dock"} {
+		files, err := Generate(Config{Input: "testdata/fleet/...", Types: []string{name}})
+		if err != nil {
+			t.Fatalf("Generate(%s) failed: %v", name, err)
+		}
+		if len(files) != 1 || filepath.Base(files[0].Path) != "ships_deltagen.go" {
+			t.Fatalf("Generate(%s) returned %d files, want only ships_deltagen.go", name, len(files))
+		}
+		for _, want := range []string{"type ShipDelta struct", "type DockDelta struct"} {
+			if !bytes.Contains(files[0].Content, []byte(want)) {
+				t.Errorf("Generate(%s) output does not contain %q", name, want)
+			}
+		}
+		generated = append(generated, files[0].Content)
+	}
+	if !bytes.Equal(generated[0], generated[1]) {
+		t.Errorf("Generate() of Ship and of Dock wrote different files")
+	}
+}
+
+func TestGenerate_Output(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		output string
+		want   []string
+	}{
+		{"next to sources", "testdata/fleet/ships", "", []string{filepath.Join("testdata", "fleet", "ships", "ships_deltagen.go")}},
+		{"file", "testdata/fleet/ships", filepath.Join("out", "fleet.go"), []string{filepath.Join("out", "fleet.go")}},
+		{"directory", "testdata/fleet/...", "out", []string{filepath.Join("out", "crew_deltagen.go"), filepath.Join("out", "ships_deltagen.go")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := Generate(Config{Input: tt.input, Output: tt.output})
+			if err != nil {
+				t.Fatalf("Generate() failed: %v", err)
+			}
+			var got []string
+			for _, f := range files {
+				got = append(got, f.Path)
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("Generate() returned %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Both packages cannot share a single output file
+	_, err := Generate(Config{Input: "testdata/fleet/...", Output: "fleet.go"})
+	if err == nil || !strings.Contains(err.Error(), "cannot hold 2 packages") {
+		t.Errorf("Generate() of two packages into one file error = %v, want it to refuse", err)
+	}
+}
+
+func TestGenerate_Package(t *testing.T) {
+	// go generate runs deltagen on the package of the file holding the
+	// directive, which selects its structs out of those loaded
+	files, err := Generate(Config{Input: "testdata/fleet/...", Package: "crew"})
+	if err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+	if len(files) != 1 || filepath.Base(files[0].Path) != "crew_deltagen.go" {
+		t.Errorf("Generate() returned %d files, want only crew_deltagen.go", len(files))
+	}
+}
+
+func TestGenerate_Templates(t *testing.T) {
+	files, err := Generate(Config{Input: "testdata/fleet/ships", Templates: []string{"testdata/templates/*.tmpl"}})
+	if err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("Generate() returned %d files, want 1", len(files))
+	}
+	src := string(files[0].Content)
+
+	// The hooks add an import and a method after each entity
+	for _, want := range []string{
+		"\t\"fmt\"\n",
+		"func (e *Ship) String() string {\n\treturn fmt.Sprintf(\"Ship(%v)\", e.ID)\n}",
+		"func (e *Dock) String() string {\n\treturn fmt.Sprintf(\"Dock(%v)\", e.ID)\n}",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("Generate() output does not contain %q", want)
+		}
+	}
+
+	// A template named like a built-in one replaces it
+	if !strings.Contains(src, "cp.Ships = append([]Ship(nil), e.Ships...) // custom clone") {
+		t.Errorf("Generate() did not use the overriding clone template")
+	}
+	if strings.Contains(src, "cp.Ships[i] = *e.Ships[i].Clone()") {
+		t.Errorf("Generate() still used the built-in clone template")
+	}
+
+	// The built-in templates are not changed for later calls
+	files, err = Generate(Config{Input: "testdata/fleet/ships"})
+	if err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+	if bytes.Contains(files[0].Content, []byte(") String() string")) {
+		t.Errorf("Generate() without templates used the hooks of an earlier call")
+	}
+
+	_, err = Generate(Config{Input: "testdata/fleet/ships", Templates: []string{"testdata/templates/*.missing"}})
+	if err == nil || !strings.Contains(err.Error(), "no template files match") {
+		t.Errorf("Generate() with an unmatched pattern error = %v, want it to refuse", err)
+	}
+}
+
+func TestGenerate_Formatted(t *testing.T) {
+	files, err := Generate(Config{Input: "testdata/fleet/..."})
+	if err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+	for _, f := range files {
+		formatted, err := format.Source(f.Content)
+		if err != nil {
+			t.Fatalf("%s does not parse: %v", f.Path, err)
+		}
+		if !bytes.Equal(formatted, f.Content) {
+			t.Errorf("%s is not gofmt-formatted", f.Path)
+		}
+	}
+}
@@ -0,0 +1,861 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// deltaPath is the import path of the delta package, which generated files
+// always import
+const deltaPath = "github.com/cbodonnell/delta"
+
+// StructInfo describes an annotated entity struct. It is the data passed to
+// the "entity" template, and to the "extra" hook after it.
+type StructInfo struct {
+	Name        string
+	Fields      []FieldInfo // fields included in the delta, in the order of their presence bits
+	PackageName string
+	Dir         string         // directory of the package sources
+	Pos         token.Position // position of the struct name, for diagnostics
+	IDField     string         // name of the identity field
+	IDType      string         // type of the identity field, returned by GetID
+	Skipped     []FieldInfo    // fields tagged delta:"-" and unexported fields, which Clone copies but deltas leave out
+	TypeID      uint32         // registered type ID, or 0 if the entity is not registered
+	TypeParams  string         // type parameter list of generic structs, e.g. "[T delta.Primitive]"
+	TypeArgs    string         // type parameters as type arguments, e.g. "[T]"
+	Params      []TypeParam    // type parameters of generic structs, in declaration order
+	Imports     []string       // import specs of packages referenced by field types
+
+	obj      *types.TypeName
+	idType   types.Type
+	idWire   string       // IDType with a named type replaced by its underlying type
+	problems []Diagnostic // problems found while parsing, reported by Validate
+	params   []string     // import paths referenced by the type parameter constraints
+}
+
+// TypeParam is a type parameter of a generic entity struct
+type TypeParam struct {
+	Name       string
+	Constraint string         // constraint as written, checked to admit only primitive types
+	Pos        token.Position // position of the parameter, for diagnostics
+
+	constraint types.Type
+}
+
+// FieldInfo describes a field of an entity struct, with embedded fields
+// promoted under their own names. The templates select the code generated for
+// it from its type and the options of its delta struct tag.
+type FieldInfo struct {
+	Name      string
+	Type      string         // type as written in the entity's package, qualified by import name
+	Pos       token.Position // position of the field declaration
+	Elem      string         // entity type of slice or map elements diffed by ID
+	ElemID    string         // identity type of Elem
+	Interface bool           // an interface type held by registered entities
+	Generic   bool           // the type refers to a type parameter of the struct
+	Codec     string         // name of the codec registered for the field, from delta:"codec=name"
+	Marshaler bool           // the type implements delta.DeltaMarshaler
+	Min       string         // lower bound checked before applying, from delta:"min=N"
+	Max       string         // upper bound checked before applying, from delta:"max=N"
+	MaxLen    string         // maximum length checked before applying, from delta:"maxlen=N"
+
+	typ          types.Type
+	wire         string   // Type with named primitive types replaced by their underlying types
+	elemIDWire   string   // ElemID with a named type replaced by its underlying type
+	unregistered []string // entities implementing an Interface field without a typeid
+	imports      []string // import paths referenced by Type
+}
+
+// Parse loads the packages matched by input with full type information and
+// returns their annotated structs. Input is a Go source file, a package
+// directory, a directory followed by /... to include all packages beneath it,
+// or any package pattern understood by the go command. Build constraints are
+// honored and test files are ignored.
+func Parse(input string) ([]StructInfo, error) {
+	pattern, only, err := loadPattern(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &packageParser{
+		fset:      token.NewFileSet(),
+		imports:   make(map[*types.Package]*importTable),
+		annotated: make(map[*types.TypeName]*StructInfo),
+	}
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedTypesInfo,
+		Fset: p.fset,
+	}
+	pkgs, err := packages.Load(cfg, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no packages match %s", input)
+	}
+
+	var structs []*StructInfo
+	for _, pkg := range pkgs {
+		if err := packageError(pkg); err != nil {
+			return nil, err
+		}
+		found, err := p.entities(pkg)
+		if err != nil {
+			return nil, err
+		}
+		structs = append(structs, found...)
+	}
+
+	var result []StructInfo
+	for _, s := range structs {
+		p.resolveFieldTypes(s)
+		s.Imports = p.importSpecs(s)
+		if only != "" {
+			if abs, _ := filepath.Abs(s.Pos.Filename); abs != only {
+				continue
+			}
+		}
+		result = append(result, *s)
+	}
+	return result, nil
+}
+
+// loadPattern converts input to a package pattern. When input is a Go source
+// file, its package is loaded and the absolute path of the file is returned to
+// restrict generation to the structs it declares.
+func loadPattern(input string) (string, string, error) {
+	root, recursive := strings.CutSuffix(filepath.ToSlash(input), "...")
+	info, err := os.Stat(filepath.Clean(root))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", "", err
+		}
+		// Not a path, so an import path pattern
+		return input, "", nil
+	}
+
+	dir := filepath.Clean(root)
+	only := ""
+	if !info.IsDir() {
+		if only, err = filepath.Abs(dir); err != nil {
+			return "", "", err
+		}
+		dir = filepath.Dir(dir)
+	}
+	if !filepath.IsAbs(dir) {
+		dir = "./" + filepath.ToSlash(dir)
+	}
+	if recursive {
+		dir = strings.TrimSuffix(dir, "/") + "/..."
+	}
+	return dir, only, nil
+}
+
+// packageError returns the first error reported while loading pkg. Type errors
+// in previously generated files are ignored, since they are about to be
+// replaced.
+func packageError(pkg *packages.Package) error {
+	for _, e := range pkg.Errors {
+		if e.Kind == packages.TypeError {
+			file, _, _ := strings.Cut(e.Pos, ":")
+			if strings.HasSuffix(file, "_deltagen.go") {
+				continue
+			}
+		}
+		return e
+	}
+	return nil
+}
+
+// packageParser collects the annotated structs of the loaded packages and
+// assigns the names under which generated files import other packages
+type packageParser struct {
+	fset      *token.FileSet
+	imports   map[*types.Package]*importTable
+	annotated map[*types.TypeName]*StructInfo
+}
+
+// entities returns the annotated structs declared in pkg
+func (p *packageParser) entities(pkg *packages.Package) ([]*StructInfo, error) {
+	var structs []*StructInfo
+
+	for _, node := range pkg.Syntax {
+		if strings.HasSuffix(p.fset.Position(node.Pos()).Filename, "_deltagen.go") {
+			continue
+		}
+
+		for _, decl := range node.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range gen.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				if _, ok := ts.Type.(*ast.StructType); !ok {
+					continue
+				}
+
+				// Check for delta:entity comment
+				options, ok := entityDirective(gen.Doc)
+				if !ok {
+					continue
+				}
+
+				obj, ok := pkg.TypesInfo.Defs[ts.Name].(*types.TypeName)
+				if !ok {
+					continue
+				}
+				named, ok := obj.Type().(*types.Named)
+				if !ok {
+					continue
+				}
+				st, ok := named.Underlying().(*types.Struct)
+				if !ok {
+					continue
+				}
+
+				s := &StructInfo{
+					Name:        obj.Name(),
+					PackageName: pkg.Name,
+					Dir:         relativePath(filepath.Dir(p.fset.Position(node.Pos()).Filename)),
+					Pos:         p.position(ts.Name.Pos()),
+					obj:         obj,
+				}
+
+				if params := named.TypeParams(); params.Len() > 0 {
+					var list, names []string
+					for i := 0; i < params.Len(); i++ {
+						tp := params.At(i)
+						constraint, imports := p.typeString(tp.Constraint(), pkg.Types)
+						s.params = append(s.params, imports...)
+						s.Params = append(s.Params, TypeParam{
+							Name:       tp.Obj().Name(),
+							Constraint: constraint,
+							Pos:        p.position(tp.Obj().Pos()),
+							constraint: tp.Constraint(),
+						})
+						list = append(list, tp.Obj().Name()+" "+constraint)
+						names = append(names, tp.Obj().Name())
+					}
+					s.TypeParams = "[" + strings.Join(list, ", ") + "]"
+					s.TypeArgs = "[" + strings.Join(names, ", ") + "]"
+				}
+
+				if typeID, ok := options["typeid"]; ok {
+					id, err := strconv.ParseUint(typeID, 10, 32)
+					if err != nil || id == 0 {
+						return nil, fmt.Errorf("struct %s in package %s: typeid must be a positive integer, got %q", s.Name, pkg.Name, typeID)
+					}
+					s.TypeID = uint32(id)
+				}
+
+				fields, problems, err := p.structFields(pkg.Types, st, 0, false)
+				s.problems = problems
+				if err != nil {
+					return nil, fmt.Errorf("struct %s in package %s: %w", s.Name, pkg.Name, err)
+				}
+				fields, err = promote(fields)
+				if err != nil {
+					return nil, fmt.Errorf("struct %s in package %s: %w", s.Name, pkg.Name, err)
+				}
+
+				var included []embeddedField
+				for _, f := range fields {
+					ft := unalias(f.Type)
+					typeStr, imports := p.typeString(ft, pkg.Types)
+					info := FieldInfo{
+						Name:    f.Name,
+						Type:    typeStr,
+						Pos:     f.Pos,
+						Codec:   tagValue(f.Tag, "codec"),
+						Min:     tagValue(f.Tag, "min"),
+						Max:     tagValue(f.Tag, "max"),
+						MaxLen:  tagValue(f.Tag, "maxlen"),
+						typ:     ft,
+						wire:    p.wireString(ft, pkg.Types),
+						imports: imports,
+					}
+					info.Marshaler = info.Codec == "" && isMarshaler(ft)
+					if f.Skip {
+						s.Skipped = append(s.Skipped, info)
+						continue
+					}
+					included = append(included, f)
+					s.Fields = append(s.Fields, info)
+				}
+
+				for i := range s.Fields {
+					s.Fields[i].Generic = mentionsTypeParam(s.Fields[i].typ)
+				}
+
+				// Ensure the struct has an identity field
+				id, err := identityField(included)
+				if err != nil {
+					return nil, fmt.Errorf("struct %s in package %s: %w", s.Name, pkg.Name, err)
+				}
+				s.IDField = id.Name
+				s.idType = unalias(id.Type)
+				s.IDType, _ = p.typeString(s.idType, pkg.Types)
+				s.idWire = p.wireString(s.idType, pkg.Types)
+
+				// Only add structs that have at least one field
+				if len(s.Fields) > 0 {
+					structs = append(structs, s)
+					p.annotated[obj] = s
+				}
+			}
+		}
+	}
+	return structs, nil
+}
+
+// resolveFieldTypes marks slice and map fields whose elements are entities, so
+// they are diffed element-wise by ID, and fields whose type is an interface
+// held by registered entities. Elements may be entities annotated in any of the
+// loaded packages, or entities generated earlier in other packages. Fields
+// encoded by a codec are left as they are.
+func (p *packageParser) resolveFieldTypes(s *StructInfo) {
+	for _, fields := range [][]FieldInfo{s.Fields, s.Skipped} {
+		for j := range fields {
+			f := &fields[j]
+			if f.Codec != "" || f.Marshaler {
+				continue
+			}
+			if isEntityInterface(f.typ) {
+				f.Interface = true
+				f.unregistered = p.unregisteredImplementers(f.typ, s.obj.Pkg())
+				continue
+			}
+
+			var elem types.Type
+			switch t := f.typ.Underlying().(type) {
+			case *types.Slice:
+				elem = t.Elem()
+			case *types.Map:
+				elem = t.Elem()
+			default:
+				continue
+			}
+			elem = unalias(elem)
+			if id, ok := p.entityID(elem); ok {
+				f.Elem, _ = p.typeString(elem, s.obj.Pkg())
+				var imports []string
+				f.ElemID, imports = p.typeString(id, s.obj.Pkg())
+				f.imports = append(f.imports, imports...)
+				f.elemIDWire = p.wireString(id, s.obj.Pkg())
+			}
+		}
+	}
+}
+
+// entityID returns the identity type of t if it is an entity struct
+func (p *packageParser) entityID(t types.Type) (types.Type, bool) {
+	named, ok := t.(*types.Named)
+	if !ok || named.TypeArgs().Len() > 0 || named.Obj().Pkg() == nil {
+		return nil, false
+	}
+	if s, ok := p.annotated[named.Obj()]; ok {
+		return s.idType, true
+	}
+
+	// An entity of a package that was not loaded has generated methods and a
+	// delta type next to it
+	if named.Obj().Pkg().Scope().Lookup(named.Obj().Name()+"Delta") == nil {
+		return nil, false
+	}
+	methods := types.NewMethodSet(types.NewPointer(named))
+	for _, name := range []string{"Clone", "Delta", "ApplyDelta"} {
+		if methods.Lookup(nil, name) == nil {
+			return nil, false
+		}
+	}
+	getID := methods.Lookup(nil, "GetID")
+	if getID == nil {
+		return nil, false
+	}
+	sig, ok := getID.Type().(*types.Signature)
+	if !ok || sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+		return nil, false
+	}
+	return sig.Results().At(0).Type(), true
+}
+
+// unregisteredImplementers returns the names, as written in package from, of
+// the annotated structs without a typeid whose pointers implement the interface
+// t. Their values could be assigned to a field of type t but not encoded.
+func (p *packageParser) unregisteredImplementers(t types.Type, from *types.Package) []string {
+	iface := t.Underlying().(*types.Interface)
+	var names []string
+	for obj, s := range p.annotated {
+		if s.TypeID != 0 || s.TypeParams != "" || !types.Implements(types.NewPointer(obj.Type()), iface) {
+			continue
+		}
+		name := obj.Name()
+		if obj.Pkg() != from {
+			name = obj.Pkg().Name() + "." + name
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// isEntityInterface returns true if t is a named, non-empty interface type
+// other than error, whose values are expected to be registered entities
+func isEntityInterface(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	iface, ok := named.Underlying().(*types.Interface)
+	return ok && iface.NumMethods() > 0
+}
+
+// isMarshaler returns true if t is a named type declaring the Equal, Clone and
+// Encode methods of delta.DeltaMarshaler[t], with a Decode method of
+// delta.DeltaUnmarshaler on *t. Their signatures are checked as the reflective
+// path checks them, so that both agree on which fields use the methods.
+func isMarshaler(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	if _, ok := named.Underlying().(*types.Interface); ok {
+		return false
+	}
+	values := types.NewMethodSet(named)
+	pointers := types.NewMethodSet(types.NewPointer(named))
+	isNamed := func(t types.Type) bool { return types.Identical(t, named) }
+	isBool := func(t types.Type) bool { return types.Identical(t, types.Typ[types.Bool]) }
+	return hasMethod(values, "Equal", isNamed, isBool) &&
+		hasMethod(values, "Clone", nil, isNamed) &&
+		hasMethod(values, "Encode", isDeltaPointer("BinaryWriter"), isError) &&
+		hasMethod(pointers, "Decode", isDeltaPointer("BinaryReader"), isError)
+}
+
+// hasMethod returns true if methods holds the named method, taking a single
+// parameter accepted by param, or none when param is nil, and returning a
+// single result accepted by result
+func hasMethod(methods *types.MethodSet, name string, param, result func(types.Type) bool) bool {
+	sel := methods.Lookup(nil, name)
+	if sel == nil {
+		return false
+	}
+	sig, ok := sel.Type().(*types.Signature)
+	if !ok || sig.Variadic() || sig.Results().Len() != 1 || !result(sig.Results().At(0).Type()) {
+		return false
+	}
+	if param == nil {
+		return sig.Params().Len() == 0
+	}
+	return sig.Params().Len() == 1 && param(sig.Params().At(0).Type())
+}
+
+// isDeltaPointer returns a function reporting whether a type is a pointer to
+// the named type of the delta package
+func isDeltaPointer(name string) func(types.Type) bool {
+	return func(t types.Type) bool {
+		ptr, ok := t.(*types.Pointer)
+		if !ok {
+			return false
+		}
+		named, ok := ptr.Elem().(*types.Named)
+		return ok && named.Obj().Name() == name && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == deltaPath
+	}
+}
+
+// isError returns true if t is the predeclared error type
+func isError(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// importTable assigns the names under which a package's generated file
+// imports other packages, aliasing packages whose names collide
+type importTable struct {
+	names   map[string]string // import path by name
+	paths   map[string]string // name by import path
+	aliased map[string]bool   // import paths whose name differs from the package name
+}
+
+// typeString formats t as written in package from, qualifying types of other
+// packages by their import name, and returns the import paths it refers to
+func (p *packageParser) typeString(t types.Type, from *types.Package) (string, []string) {
+	table, ok := p.imports[from]
+	if !ok {
+		table = &importTable{
+			names:   map[string]string{"io": "io", "delta": deltaPath},
+			paths:   map[string]string{"io": "io", deltaPath: "delta"},
+			aliased: make(map[string]bool),
+		}
+		p.imports[from] = table
+	}
+
+	var imports []string
+	str := types.TypeString(t, func(other *types.Package) string {
+		if other == from {
+			return ""
+		}
+		name, ok := table.paths[other.Path()]
+		if !ok {
+			name = other.Name()
+			for i := 2; table.names[name] != ""; i++ {
+				name = other.Name() + strconv.Itoa(i)
+			}
+			table.names[name] = other.Path()
+			table.paths[other.Path()] = name
+			table.aliased[other.Path()] = name != other.Name()
+		}
+		imports = append(imports, other.Path())
+		return name
+	})
+	return str, imports
+}
+
+// wireString formats t like typeString, replacing named types whose underlying
+// type is a primitive, such as time.Duration, with that primitive type, which
+// is how their values are encoded
+func (p *packageParser) wireString(t types.Type, from *types.Package) string {
+	switch t := types.Unalias(t).(type) {
+	case *types.Named:
+		if basic, ok := t.Underlying().(*types.Basic); ok {
+			return basic.Name()
+		}
+	case *types.Slice:
+		return "[]" + p.wireString(t.Elem(), from)
+	case *types.Map:
+		return "map[" + p.wireString(t.Key(), from) + "]" + p.wireString(t.Elem(), from)
+	}
+	str, _ := p.typeString(unalias(t), from)
+	return str
+}
+
+// unalias returns t with aliases replaced by the types they denote, including
+// the aliases of slice, array, map, pointer and channel elements, so that
+// field types are written and checked as the types the aliases stand for
+func unalias(t types.Type) types.Type {
+	if t == types.Universe.Lookup("any").Type() {
+		return t // kept to be written as any
+	}
+	switch t := types.Unalias(t).(type) {
+	case *types.Slice:
+		return types.NewSlice(unalias(t.Elem()))
+	case *types.Array:
+		return types.NewArray(unalias(t.Elem()), t.Len())
+	case *types.Map:
+		return types.NewMap(unalias(t.Key()), unalias(t.Elem()))
+	case *types.Pointer:
+		return types.NewPointer(unalias(t.Elem()))
+	case *types.Chan:
+		return types.NewChan(t.Dir(), unalias(t.Elem()))
+	default:
+		return t
+	}
+}
+
+// importSpecs returns the import specs of the packages that the generated
+// code for s refers to. Skipped fields are only mentioned by Clone when they
+// are deep copied.
+func (p *packageParser) importSpecs(s *StructInfo) []string {
+	paths := append([]string(nil), s.params...)
+	for _, f := range s.Fields {
+		paths = append(paths, f.imports...)
+	}
+	for _, f := range s.Skipped {
+		if hasCodec(f) || isCollectionType(f.Type) {
+			paths = append(paths, f.imports...)
+		}
+	}
+
+	table := p.imports[s.obj.Pkg()]
+	var specs []string
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		if path == deltaPath || seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		spec := strconv.Quote(path)
+		if table.aliased[path] {
+			spec = table.paths[path] + " " + spec
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// embeddedField is a candidate field of an entity, possibly promoted from an
+// embedded struct at the given depth
+type embeddedField struct {
+	Name  string
+	Type  types.Type
+	Tag   []string
+	Depth int
+	Skip  bool // excluded from the delta, but still deep copied by Clone
+	Pos   token.Position
+}
+
+// structFields lists the fields of st, flattening embedded structs in place,
+// and reports embedded structs that contribute no fields. Unexported fields of
+// structs embedded from other packages are not accessible from pkg and are
+// left out. The fields of skipped embedded structs are listed as skipped, so
+// Clone deep copies them.
+func (p *packageParser) structFields(pkg *types.Package, st *types.Struct, depth int, skipped bool) ([]embeddedField, []Diagnostic, error) {
+	var fields []embeddedField
+	var problems []Diagnostic
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		tag := tagOptions(st.Tag(i))
+		skip := skipped || hasTagOption(tag, "-")
+
+		// Flatten anonymous fields (embedded structs). Embedded types that are
+		// not structs are fields named after their type.
+		if f.Embedded() {
+			_, isPointer := f.Type().(*types.Pointer)
+			embedded, isStruct := f.Type().Underlying().(*types.Struct)
+			switch {
+			case isPointer && skip:
+				// Skipped embedded pointers are copied by value
+				continue
+			case isPointer:
+				return nil, nil, fmt.Errorf("embedded pointer %s is not supported", types.TypeString(f.Type(), types.RelativeTo(pkg)))
+			case isStruct:
+				promoted, nested, err := p.structFields(pkg, embedded, depth+1, skip)
+				if err != nil {
+					return nil, nil, err
+				}
+				if len(promoted) == 0 && embedded.NumFields() > 0 && !skip {
+					problems = append(problems, Diagnostic{
+						Pos:     p.position(f.Pos()),
+						Message: fmt.Sprintf("embedded struct %s has no fields accessible from package %s: declare it as a named field of a supported type, or tag it delta:\"-\" to exclude it", types.TypeString(f.Type(), types.RelativeTo(pkg)), pkg.Name()),
+					})
+				}
+				fields = append(fields, promoted...)
+				problems = append(problems, nested...)
+				continue
+			}
+		}
+
+		// Skip unexported fields (starting with lowercase) unless opted in
+		if !f.Exported() {
+			if f.Pkg() != pkg {
+				// Not accessible from the entity's package
+				if hasTagOption(tag, "include") {
+					return nil, nil, fmt.Errorf("unexported field %s of package %s cannot be included", f.Name(), f.Pkg().Name())
+				}
+				continue
+			}
+			if !hasTagOption(tag, "include") {
+				skip = true
+			}
+		}
+
+		fields = append(fields, embeddedField{
+			Name:  f.Name(),
+			Type:  f.Type(),
+			Tag:   tag,
+			Depth: depth,
+			Skip:  skip,
+			Pos:   p.position(f.Pos()),
+		})
+	}
+	return fields, problems, nil
+}
+
+// position returns the position of pos, relative to the working directory when
+// it lies beneath it
+func (p *packageParser) position(pos token.Pos) token.Position {
+	position := p.fset.Position(pos)
+	position.Filename = relativePath(position.Filename)
+	return position
+}
+
+// relativePath returns path relative to the working directory when it lies
+// beneath it, so diagnostics and output paths stay short
+func relativePath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil || path == "" {
+		return path
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
+
+// promote applies Go's field promotion rules: a field at a shallower depth
+// shadows fields of the same name from deeper embedded structs, and fields of
+// the same name at the same depth are ambiguous
+func promote(fields []embeddedField) ([]embeddedField, error) {
+	shallowest := make(map[string]int)
+	count := make(map[string]int)
+	for _, f := range fields {
+		d, ok := shallowest[f.Name]
+		switch {
+		case !ok || f.Depth < d:
+			shallowest[f.Name] = f.Depth
+			count[f.Name] = 1
+		case f.Depth == d:
+			count[f.Name]++
+		}
+	}
+
+	var promoted []embeddedField
+	for _, f := range fields {
+		if f.Depth != shallowest[f.Name] {
+			continue
+		}
+		if count[f.Name] > 1 {
+			return nil, fmt.Errorf("ambiguous field %s promoted from multiple embedded structs", f.Name)
+		}
+		promoted = append(promoted, f)
+	}
+	return promoted, nil
+}
+
+// identityField returns the field tagged delta:"id", or the field named ID when
+// no field is tagged
+func identityField(fields []embeddedField) (embeddedField, error) {
+	var id *embeddedField
+	for i, f := range fields {
+		if !hasTagOption(f.Tag, "id") {
+			continue
+		}
+		if id != nil {
+			return embeddedField{}, fmt.Errorf("fields %s and %s are both tagged delta:\"id\"", id.Name, f.Name)
+		}
+		id = &fields[i]
+	}
+	if id == nil {
+		for i, f := range fields {
+			if f.Name == "ID" {
+				id = &fields[i]
+				break
+			}
+		}
+	}
+	if id == nil {
+		return embeddedField{}, fmt.Errorf("does not have an ID field or a field tagged delta:\"id\"")
+	}
+	if basic, ok := id.Type.Underlying().(*types.Basic); !ok || !isIDType(basic.Name()) {
+		return embeddedField{}, fmt.Errorf("identity field %s has type %s, want a string or integer type", id.Name, id.Type)
+	}
+	return *id, nil
+}
+
+// isIDType returns true if the type can be used as an entity identity
+func isIDType(typeStr string) bool {
+	switch typeStr {
+	case "string", "int8", "int16", "int32", "int64",
+		"uint8", "byte", "uint16", "uint32", "uint64":
+		return true
+	}
+	return false
+}
+
+// tagOptions returns the comma-separated options of the delta key of a struct tag
+func tagOptions(tag string) []string {
+	value, ok := reflect.StructTag(tag).Lookup("delta")
+	if !ok {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// hasTagOption returns true if the tag options contain option
+func hasTagOption(tag []string, option string) bool {
+	for _, t := range tag {
+		if strings.TrimSpace(t) == option {
+			return true
+		}
+	}
+	return false
+}
+
+// tagValue returns the value of a key=value option in the tag options, or an
+// empty string if it is not set
+func tagValue(tag []string, key string) string {
+	for _, t := range tag {
+		if k, v, ok := strings.Cut(strings.TrimSpace(t), "="); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
+
+// mentionsTypeParam returns true if t refers to a type parameter, directly or
+// as the element or type argument of another type
+func mentionsTypeParam(t types.Type) bool {
+	switch t := types.Unalias(t).(type) {
+	case *types.TypeParam:
+		return true
+	case *types.Slice:
+		return mentionsTypeParam(t.Elem())
+	case *types.Array:
+		return mentionsTypeParam(t.Elem())
+	case *types.Map:
+		return mentionsTypeParam(t.Key()) || mentionsTypeParam(t.Elem())
+	case *types.Pointer:
+		return mentionsTypeParam(t.Elem())
+	case *types.Chan:
+		return mentionsTypeParam(t.Elem())
+	case *types.Named:
+		for i := range t.TypeArgs().Len() {
+			if mentionsTypeParam(t.TypeArgs().At(i)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// entityDirective checks if the comment block contains the delta:entity
+// directive and returns its options, e.g. "// delta:entity typeid=3"
+func entityDirective(doc *ast.CommentGroup) (map[string]string, bool) {
+	if doc == nil {
+		return nil, false
+	}
+
+	for _, comment := range doc.List {
+		text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+		fields := strings.Fields(text)
+		if len(fields) == 0 || fields[0] != "delta:entity" {
+			continue
+		}
+		options := make(map[string]string)
+		for _, option := range fields[1:] {
+			key, value, _ := strings.Cut(option, "=")
+			options[key] = value
+		}
+		return options, true
+	}
+	return nil, false
+}
+
+// ExprString converts an AST expression to its string representation
+func ExprString(e ast.Expr) string {
+	var buf strings.Builder
+	err := printer.Fprint(&buf, token.NewFileSet(), e)
+	if err != nil {
+		return "unknown" // fallback for unprintable expressions
+	}
+	return buf.String()
+}
@@ -0,0 +1,36 @@
+package gen
+
+import (
+	"go/token"
+	"go/types"
+	"testing"
+)
+
+func TestMentionsTypeParam(t *testing.T) {
+	// A type of another package named like the type parameter is not generic
+	other := types.NewPackage("example.com/other", "other")
+	named := types.NewNamed(types.NewTypeName(token.NoPos, other, "T", nil), types.Typ[types.Int32], nil)
+	param := types.NewTypeParam(types.NewTypeName(token.NoPos, nil, "T", nil), types.NewInterfaceType(nil, nil))
+	alias := types.NewAlias(types.NewTypeName(token.NoPos, other, "Ts", nil), types.NewSlice(param))
+
+	tests := []struct {
+		name string
+		typ  types.Type
+		want bool
+	}{
+		{"type parameter", param, true},
+		{"slice element", types.NewSlice(param), true},
+		{"map key", types.NewMap(param, types.Typ[types.String]), true},
+		{"alias", alias, true},
+		{"named type of another package", named, false},
+		{"slice of named type", types.NewSlice(named), false},
+		{"primitive", types.Typ[types.Int32], false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mentionsTypeParam(tt.typ); got != tt.want {
+				t.Errorf("mentionsTypeParam(%s) = %v, want %v", tt.typ, got, tt.want)
+			}
+		})
+	}
+}
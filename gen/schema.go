@@ -0,0 +1,177 @@
+package gen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"go/types"
+)
+
+// SchemaVersion is the version of the schema format, incremented when a
+// change would break tools reading it
+const SchemaVersion = 1
+
+// Schema describes the wire format of the deltas of a set of entities, for
+// tools that decode them without parsing Go. Deltagen writes it as JSON with
+// the -schema flag.
+type Schema struct {
+	Version  int            `json:"version"`
+	Entities []EntitySchema `json:"entities"`
+}
+
+// EntitySchema describes the delta of an entity. It is encoded as a uint64
+// little-endian mask with bit Number set for every field present, followed by
+// the present fields in order.
+type EntitySchema struct {
+	Name       string        `json:"name"`
+	Package    string        `json:"package"`          // import path of the Go package
+	TypeID     uint32        `json:"typeID,omitempty"` // registered type ID, for interface fields
+	TypeParams []string      `json:"typeParams,omitempty"`
+	IDField    string        `json:"idField"`
+	IDType     string        `json:"idType"` // Go type, encoded as its underlying type
+	Fields     []FieldSchema `json:"fields"`
+
+	// Hash is the hex SHA-256 of the name, type ID, type parameters and
+	// fields, which changes whenever the wire format of the entity does
+	Hash string `json:"hash"`
+}
+
+// FieldSchema describes a field of an entity delta
+type FieldSchema struct {
+	Name     string   `json:"name"`
+	Number   int      `json:"number"` // bit of the presence mask
+	Type     string   `json:"type"`   // Go type, as written in the entity's package
+	Encoding Encoding `json:"encoding"`
+}
+
+// Encoding describes how a value is written by BinaryWriter. Kind is one of:
+//
+//   - bool, int8, int16, int32, int64, uint8, uint16, uint32, uint64,
+//     float32, float64: fixed size, little-endian, bool as one byte
+//   - string, bytes: varint length followed by the bytes
+//   - slice: varint length followed by each Elem
+//   - map: varint length followed by each Key and Elem
+//   - set: the added keys then the removed keys, each a slice of Key
+//   - collection: entities diffed by ID, keyed by Key with deltas of Entity:
+//     the added and the changed as maps of deltas, the removed as a slice of
+//     keys, then a bool followed by the new order as a slice of keys when set
+//   - interface: varint type ID, 0 for nil, then for other IDs a bool set when
+//     the value was replaced and the delta of the registered entity
+//   - codec: written by the codec registered under Codec, which for
+//     DeltaMarshaler types is their Go type name
+//   - param: a value of the type argument of type parameter Param
+type Encoding struct {
+	Kind   string    `json:"kind"`
+	Key    *Encoding `json:"key,omitempty"`
+	Elem   *Encoding `json:"elem,omitempty"`
+	Entity string    `json:"entity,omitempty"` // import path qualified entity name
+	Codec  string    `json:"codec,omitempty"`
+	Param  string    `json:"param,omitempty"`
+}
+
+// BuildSchema returns the schema of structs
+func BuildSchema(structs []StructInfo) Schema {
+	schema := Schema{Version: SchemaVersion, Entities: []EntitySchema{}}
+	for _, s := range structs {
+		e := EntitySchema{
+			Name:    s.Name,
+			TypeID:  s.TypeID,
+			IDField: s.IDField,
+			IDType:  s.IDType,
+			Fields:  []FieldSchema{},
+		}
+		if s.obj != nil {
+			e.Package = s.obj.Pkg().Path()
+		}
+		for _, p := range s.Params {
+			e.TypeParams = append(e.TypeParams, p.Name)
+		}
+		for i, f := range s.Fields {
+			e.Fields = append(e.Fields, FieldSchema{Name: f.Name, Number: i, Type: f.Type, Encoding: fieldEncoding(wireField(f))})
+		}
+		e.Hash = entityHash(e)
+		schema.Entities = append(schema.Entities, e)
+	}
+	return schema
+}
+
+// entityHash hashes the parts of e that determine its wire format
+func entityHash(e EntitySchema) string {
+	type field struct {
+		Name     string
+		Number   int
+		Encoding Encoding
+	}
+	wire := struct {
+		Name       string
+		TypeID     uint32
+		TypeParams []string
+		Fields     []field
+	}{Name: e.Name, TypeID: e.TypeID, TypeParams: e.TypeParams}
+	for _, f := range e.Fields {
+		wire.Fields = append(wire.Fields, field{f.Name, f.Number, f.Encoding})
+	}
+
+	// Marshaling plain structs cannot fail
+	b, _ := json.Marshal(wire)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// fieldEncoding returns the encoding of the delta of a field
+func fieldEncoding(f FieldInfo) Encoding {
+	switch {
+	case f.Codec != "":
+		return Encoding{Kind: "codec", Codec: f.Codec}
+	case f.Marshaler:
+		return Encoding{Kind: "codec", Codec: f.Type}
+	case f.Interface:
+		return Encoding{Kind: "interface"}
+	case f.Elem != "":
+		key := typeEncoding(getCollectionKeyType(f))
+		return Encoding{Kind: "collection", Key: &key, Entity: elemEntity(f)}
+	case isSetType(f.Type):
+		key := typeEncoding(getMapKeyType(f.Type))
+		return Encoding{Kind: "set", Key: &key}
+	}
+	return typeEncoding(f.Type)
+}
+
+// typeEncoding returns the encoding of a value of a Go type
+func typeEncoding(typeStr string) Encoding {
+	switch typeStr {
+	case "bool", "int8", "int16", "int32", "int64", "uint8", "uint16", "uint32", "uint64", "float32", "float64", "string":
+		return Encoding{Kind: typeStr}
+	case "byte":
+		return Encoding{Kind: "uint8"}
+	case "rune":
+		return Encoding{Kind: "int32"}
+	case "[]byte":
+		return Encoding{Kind: "bytes"}
+	}
+	switch {
+	case isSliceType(typeStr):
+		elem := typeEncoding(getSliceElementType(typeStr))
+		return Encoding{Kind: "slice", Elem: &elem}
+	case isMapType(typeStr):
+		key := typeEncoding(getMapKeyType(typeStr))
+		elem := typeEncoding(getMapValueType(typeStr))
+		return Encoding{Kind: "map", Key: &key, Elem: &elem}
+	}
+	return Encoding{Kind: "param", Param: typeStr}
+}
+
+// elemEntity returns the import path qualified name of the entities held in
+// a collection field
+func elemEntity(f FieldInfo) string {
+	var elem types.Type
+	switch t := f.typ.Underlying().(type) {
+	case *types.Slice:
+		elem = t.Elem()
+	case *types.Map:
+		elem = t.Elem()
+	default:
+		return f.Elem
+	}
+	return types.TypeString(elem, nil)
+}
@@ -0,0 +1,1014 @@
+package gen
+
+import (
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// isSliceType returns true if the type is a slice
+func isSliceType(typeStr string) bool {
+	return strings.HasPrefix(typeStr, "[]")
+}
+
+// isMapType returns true if the type is a map
+func isMapType(typeStr string) bool {
+	return strings.HasPrefix(typeStr, "map[")
+}
+
+// getSerializeMethod returns the appropriate serialize method name for a type
+func getSerializeMethod(typeStr string) (string, error) {
+	switch typeStr {
+	case "bool":
+		return "WriteBool", nil
+	case "int8":
+		return "WriteInt8", nil
+	case "int16":
+		return "WriteInt16", nil
+	case "int32", "rune":
+		return "WriteInt32", nil
+	case "int64":
+		return "WriteInt64", nil
+	case "uint8", "byte":
+		return "WriteUint8", nil
+	case "uint16":
+		return "WriteUint16", nil
+	case "uint32":
+		return "WriteUint32", nil
+	case "uint64":
+		return "WriteUint64", nil
+	case "float32":
+		return "WriteFloat32", nil
+	case "float64":
+		return "WriteFloat64", nil
+	case "string":
+		return "WriteString", nil
+	case "[]byte":
+		return "WriteBytes", nil
+	}
+	return "", fmt.Errorf("unsupported type %s", typeStr)
+}
+
+// getDeserializeMethod returns the appropriate deserialize method name for a type
+func getDeserializeMethod(typeStr string) (string, error) {
+	switch typeStr {
+	case "bool":
+		return "ReadBool", nil
+	case "int8":
+		return "ReadInt8", nil
+	case "int16":
+		return "ReadInt16", nil
+	case "int32", "rune":
+		return "ReadInt32", nil
+	case "int64":
+		return "ReadInt64", nil
+	case "uint8", "byte":
+		return "ReadUint8", nil
+	case "uint16":
+		return "ReadUint16", nil
+	case "uint32":
+		return "ReadUint32", nil
+	case "uint64":
+		return "ReadUint64", nil
+	case "float32":
+		return "ReadFloat32", nil
+	case "float64":
+		return "ReadFloat64", nil
+	case "string":
+		return "ReadString", nil
+	case "[]byte":
+		return "ReadBytes", nil
+	}
+	return "", fmt.Errorf("unsupported type %s", typeStr)
+}
+
+// getSliceElementType extracts the element type from a slice type (e.g., "[]int32" -> "int32")
+func getSliceElementType(sliceType string) string {
+	if strings.HasPrefix(sliceType, "[]") {
+		return sliceType[2:]
+	}
+	return sliceType
+}
+
+// getMapKeyType extracts the key type from a map type (e.g., "map[string]int32" -> "string")
+func getMapKeyType(mapType string) string {
+	if !strings.HasPrefix(mapType, "map[") {
+		return "string"
+	}
+	// Find the closing bracket for the key type
+	start := 4 // after "map["
+	bracket := 1
+	for i := start; i < len(mapType); i++ {
+		switch mapType[i] {
+		case '[':
+			bracket++
+		case ']':
+			bracket--
+			if bracket == 0 {
+				return mapType[start:i]
+			}
+		}
+	}
+	return "string"
+}
+
+// getMapValueType extracts the value type from a map type (e.g., "map[string]int32" -> "int32")
+func getMapValueType(mapType string) string {
+	if !strings.HasPrefix(mapType, "map[") {
+		return "string"
+	}
+	// Find the closing bracket for the key type
+	start := 4 // after "map["
+	bracket := 1
+	for i := start; i < len(mapType); i++ {
+		switch mapType[i] {
+		case '[':
+			bracket++
+		case ']':
+			bracket--
+			if bracket == 0 {
+				return mapType[i+1:]
+			}
+		}
+	}
+	return "string"
+}
+
+// isSetType returns true if the type is a set-shaped map, map[K]struct{} or
+// map[K]bool, which is diffed as added and removed keys
+func isSetType(typeStr string) bool {
+	if !isMapType(typeStr) {
+		return false
+	}
+	value := getMapValueType(typeStr)
+	return value == "struct{}" || value == "bool"
+}
+
+// getSetMember returns the value stored for members of a set-shaped map
+func getSetMember(typeStr string) string {
+	if getMapValueType(typeStr) == "bool" {
+		return "true"
+	}
+	return "struct{}{}"
+}
+
+// isNestedType returns true if the type is a slice or map whose elements are
+// themselves slices or maps, which are handled by the recursive helpers below
+func isNestedType(typeStr string) bool {
+	var elem string
+	switch {
+	case typeStr == "[]byte":
+		return false
+	case isSliceType(typeStr):
+		elem = getSliceElementType(typeStr)
+	case isMapType(typeStr):
+		elem = getMapValueType(typeStr)
+	default:
+		return false
+	}
+	return isSliceType(elem) || isMapType(elem)
+}
+
+// usesCollectionHelpers returns true if the field is a collection encoded
+// through the generic helpers of the delta package: nested collections, and
+// collections of a type parameter or of named primitive types
+func usesCollectionHelpers(f FieldInfo) bool {
+	return isNestedType(f.Type) || ((f.Generic || wireType(f) != f.Type) && isCollectionType(f.Type))
+}
+
+// wireType returns the type of the field with named primitive types replaced
+// by their underlying types, as they are encoded
+func wireType(f FieldInfo) string {
+	if f.wire == "" {
+		return f.Type
+	}
+	return f.wire
+}
+
+// isNamedPrimitive returns true if the field has a named type whose underlying
+// type is a primitive, encoded by converting it
+func isNamedPrimitive(f FieldInfo) bool {
+	return wireType(f) != f.Type && !isCollectionType(f.Type)
+}
+
+// toWire returns v of the field's type converted to its wire type
+func toWire(f FieldInfo, v string) string {
+	if !isNamedPrimitive(f) {
+		return v
+	}
+	return fmt.Sprintf("%s(%s)", wireType(f), v)
+}
+
+// isSet returns true if the field is a set-shaped map, including maps whose
+// values are of a named bool type
+func isSet(f FieldInfo) bool {
+	return isSetType(wireType(f))
+}
+
+// setMember returns the value stored for members of a set field
+func setMember(f FieldInfo) string {
+	return getSetMember(wireType(f))
+}
+
+// keyWriteFunc returns an expression of type func(K) error serializing the
+// keys of an entity collection or set field
+func keyWriteFunc(f FieldInfo) (string, error) {
+	return writeWireFunc(getCollectionKeyType(f), collectionKeyWire(f))
+}
+
+// keyReadFunc returns an expression of type func() (K, error) deserializing
+// the keys of an entity collection or set field
+func keyReadFunc(f FieldInfo) (string, error) {
+	return readWireFunc(getCollectionKeyType(f), collectionKeyWire(f))
+}
+
+// collectionKeyWire returns the wire type of the keys of an entity collection
+// or set field
+func collectionKeyWire(f FieldInfo) string {
+	if isMapType(f.Type) {
+		return getMapKeyType(wireType(f))
+	}
+	if f.elemIDWire != "" {
+		return f.elemIDWire
+	}
+	return f.ElemID
+}
+
+// fieldWriteCall returns an expression serializing v of the collection type of
+// the field with bw
+func fieldWriteCall(f FieldInfo, v string) (string, error) {
+	return writeWireCall(f.Type, wireType(f), v)
+}
+
+// fieldReadCall returns an expression deserializing the collection type of the
+// field with br
+func fieldReadCall(f FieldInfo) (string, error) {
+	return readWireCall(f.Type, wireType(f))
+}
+
+// validatesNested returns true if the Validate method of s checks nested
+// entities against those held by the entity
+func validatesNested(s StructInfo) bool {
+	for _, f := range s.Fields {
+		if f.Elem != "" || f.Interface {
+			return true
+		}
+	}
+	return false
+}
+
+// isCollectionType returns true if the type is a slice or map
+func isCollectionType(typeStr string) bool {
+	return isSliceType(typeStr) || isMapType(typeStr)
+}
+
+// equalCall returns an expression comparing a and b of a collection type
+func equalCall(typeStr, a, b string) string {
+	if !isNestedType(typeStr) {
+		if isSliceType(typeStr) {
+			return fmt.Sprintf("delta.SlicesEqual(%s, %s)", a, b)
+		}
+		return fmt.Sprintf("delta.MapsEqual(%s, %s)", a, b)
+	}
+	if isSliceType(typeStr) {
+		return fmt.Sprintf("delta.SlicesEqualFunc(%s, %s, %s)", a, b, equalFunc(getSliceElementType(typeStr)))
+	}
+	return fmt.Sprintf("delta.MapsEqualFunc(%s, %s, %s)", a, b, equalFunc(getMapValueType(typeStr)))
+}
+
+// equalFunc returns an expression of type func(a, b T) bool for the type
+func equalFunc(typeStr string) string {
+	switch {
+	case isNestedType(typeStr):
+		return fmt.Sprintf("func(a, b %s) bool { return %s }", typeStr, equalCall(typeStr, "a", "b"))
+	case isSliceType(typeStr):
+		return fmt.Sprintf("delta.SlicesEqual[%s]", getSliceElementType(typeStr))
+	case isMapType(typeStr):
+		return fmt.Sprintf("delta.MapsEqual[%s, %s]", getMapKeyType(typeStr), getMapValueType(typeStr))
+	}
+	return fmt.Sprintf("delta.Equal[%s]", typeStr)
+}
+
+// cloneCall returns an expression deep copying v of a collection type
+func cloneCall(typeStr, v string) string {
+	if !isNestedType(typeStr) {
+		if isSliceType(typeStr) {
+			return fmt.Sprintf("delta.CloneSlice(%s)", v)
+		}
+		return fmt.Sprintf("delta.CloneMap(%s)", v)
+	}
+	if isSliceType(typeStr) {
+		return fmt.Sprintf("delta.CloneSliceFunc(%s, %s)", v, cloneFunc(getSliceElementType(typeStr)))
+	}
+	return fmt.Sprintf("delta.CloneMapFunc(%s, %s)", v, cloneFunc(getMapValueType(typeStr)))
+}
+
+// cloneFunc returns an expression of type func(T) T deep copying the type
+func cloneFunc(typeStr string) string {
+	switch {
+	case isNestedType(typeStr):
+		return fmt.Sprintf("func(v %s) %s { return %s }", typeStr, typeStr, cloneCall(typeStr, "v"))
+	case isSliceType(typeStr):
+		return fmt.Sprintf("delta.CloneSlice[%s]", getSliceElementType(typeStr))
+	}
+	return fmt.Sprintf("delta.CloneMap[%s, %s]", getMapKeyType(typeStr), getMapValueType(typeStr))
+}
+
+// writeCall returns an expression serializing v of a collection type with bw
+func writeCall(typeStr, v string) (string, error) {
+	return writeWireCall(typeStr, typeStr, v)
+}
+
+// writeWireCall returns an expression serializing v of a collection type with
+// bw, converting named primitive types to their types in wire
+func writeWireCall(typeStr, wire, v string) (string, error) {
+	if isSliceType(typeStr) {
+		elem, err := writeWireFunc(getSliceElementType(typeStr), getSliceElementType(wire))
+		return fmt.Sprintf("delta.WriteSlice(bw, %s, %s)", v, elem), err
+	}
+	key, err := writeWireFunc(getMapKeyType(typeStr), getMapKeyType(wire))
+	if err != nil {
+		return "", err
+	}
+	value, err := writeWireFunc(getMapValueType(typeStr), getMapValueType(wire))
+	return fmt.Sprintf("delta.WriteMap(bw, %s, %s, %s)", v, key, value), err
+}
+
+// writeFunc returns an expression of type func(T) error serializing the type
+func writeFunc(typeStr string) (string, error) {
+	return writeWireFunc(typeStr, typeStr)
+}
+
+// writeWireFunc returns an expression of type func(T) error serializing the
+// type, converting named primitive types to their types in wire
+func writeWireFunc(typeStr, wire string) (string, error) {
+	if typeStr != "[]byte" && isCollectionType(typeStr) {
+		call, err := writeWireCall(typeStr, wire, "v")
+		return fmt.Sprintf("func(v %s) error { return %s }", typeStr, call), err
+	}
+	if isTypeParam(typeStr) && typeStr == wire {
+		return fmt.Sprintf("func(v %s) error { return delta.WriteValue(bw, v) }", typeStr), nil
+	}
+	method, err := getSerializeMethod(wire)
+	if err == nil && typeStr != wire {
+		return fmt.Sprintf("func(v %s) error { return bw.%s(%s(v)) }", typeStr, method, wire), nil
+	}
+	return "bw." + method, err
+}
+
+// readCall returns an expression deserializing a collection type with br
+func readCall(typeStr string) (string, error) {
+	return readWireCall(typeStr, typeStr)
+}
+
+// readWireCall returns an expression deserializing a collection type with br,
+// converting named primitive types from their types in wire
+func readWireCall(typeStr, wire string) (string, error) {
+	if isSliceType(typeStr) {
+		elem, err := readWireFunc(getSliceElementType(typeStr), getSliceElementType(wire))
+		return fmt.Sprintf("delta.ReadSlice(br, %s)", elem), err
+	}
+	key, err := readWireFunc(getMapKeyType(typeStr), getMapKeyType(wire))
+	if err != nil {
+		return "", err
+	}
+	value, err := readWireFunc(getMapValueType(typeStr), getMapValueType(wire))
+	return fmt.Sprintf("delta.ReadMap(br, %s, %s)", key, value), err
+}
+
+// readFunc returns an expression of type func() (T, error) deserializing the type
+func readFunc(typeStr string) (string, error) {
+	return readWireFunc(typeStr, typeStr)
+}
+
+// readWireFunc returns an expression of type func() (T, error) deserializing
+// the type, converting named primitive types from their types in wire
+func readWireFunc(typeStr, wire string) (string, error) {
+	if typeStr != "[]byte" && isCollectionType(typeStr) {
+		call, err := readWireCall(typeStr, wire)
+		return fmt.Sprintf("func() (%s, error) { return %s }", typeStr, call), err
+	}
+	if isTypeParam(typeStr) && typeStr == wire {
+		return fmt.Sprintf("func() (%s, error) { return delta.ReadValue[%s](br) }", typeStr, typeStr), nil
+	}
+	method, err := getDeserializeMethod(wire)
+	if err == nil && typeStr != wire {
+		return fmt.Sprintf("func() (%s, error) { v, err := br.%s(); return %s(v), err }", typeStr, method, typeStr), nil
+	}
+	return "br." + method, err
+}
+
+// isTypeParam returns true if the type is a type parameter, the only plain
+// identifiers besides primitives that pass validation
+func isTypeParam(typeStr string) bool {
+	if _, err := getSerializeMethod(typeStr); err == nil {
+		return false
+	}
+	return token.IsIdentifier(typeStr)
+}
+
+// getCollectionKeyType returns the key of an entity collection: the element ID
+// for slices and the map key for maps
+func getCollectionKeyType(f FieldInfo) string {
+	if isMapType(f.Type) {
+		return getMapKeyType(f.Type)
+	}
+	return f.ElemID
+}
+
+// hasCodec returns true if the field is compared, cloned and encoded by a
+// delta.FieldCodec rather than by generated code
+func hasCodec(f FieldInfo) bool {
+	return f.Codec != "" || f.Marshaler
+}
+
+// codecExpr returns an expression evaluating to the delta.FieldCodec of the field
+func codecExpr(f FieldInfo) string {
+	if f.Codec != "" {
+		return fmt.Sprintf("delta.MustCodec[%s](%q)", f.Type, f.Codec)
+	}
+	return fmt.Sprintf("delta.MarshalerCodec[%s]()", f.Type)
+}
+
+// isStdImport returns true if the import spec names a standard library
+// package, whose path has no dot in its first element
+func isStdImport(spec string) bool {
+	_, path, _ := strings.Cut(spec, `"`)
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+var templates = template.Must(template.New("file").Funcs(template.FuncMap{
+	"isSliceType":           isSliceType,
+	"isMapType":             isMapType,
+	"getSerializeMethod":    getSerializeMethod,
+	"getDeserializeMethod":  getDeserializeMethod,
+	"getSliceElementType":   getSliceElementType,
+	"getMapKeyType":         getMapKeyType,
+	"getMapValueType":       getMapValueType,
+	"getCollectionKeyType":  getCollectionKeyType,
+	"isSetType":             isSetType,
+	"getSetMember":          getSetMember,
+	"isNestedType":          isNestedType,
+	"usesCollectionHelpers": usesCollectionHelpers,
+	"wireType":              wireType,
+	"isNamedPrimitive":      isNamedPrimitive,
+	"toWire":                toWire,
+	"isSet":                 isSet,
+	"setMember":             setMember,
+	"keyWriteFunc":          keyWriteFunc,
+	"keyReadFunc":           keyReadFunc,
+	"fieldWriteCall":        fieldWriteCall,
+	"fieldReadCall":         fieldReadCall,
+	"validatesNested":       validatesNested,
+	"isTypeParam":           isTypeParam,
+	"writeFunc":             writeFunc,
+	"readFunc":              readFunc,
+	"equalCall":             equalCall,
+	"cloneCall":             cloneCall,
+	"writeCall":             writeCall,
+	"readCall":              readCall,
+	"hasCodec":              hasCodec,
+	"codecExpr":             codecExpr,
+	"isStdImport":           isStdImport,
+}).Parse(`
+{{define "file"}}// Code generated by deltagen. DO NOT EDIT.
+package {{.Name}}
+
+import (
+	"io"
+	{{- range .Imports}}{{if isStdImport .}}
+	{{.}}
+	{{- end}}{{end}}
+
+	"github.com/cbodonnell/delta"
+	{{- range .Imports}}{{if not (isStdImport .)}}
+	{{.}}
+	{{- end}}{{end}}
+	{{- block "imports" .}}{{end}}
+)
+{{- range .Structs}}
+{{template "entity" .}}
+{{- end}}
+{{end}}
+
+{{define "entity"}}
+{{- if not .TypeParams}}var (
+	_ delta.EntityOf[{{.IDType}}] = (*{{.Name}})(nil)
+	_ delta.Diffable[*{{.Name}}, *{{.Name}}Delta] = (*{{.Name}})(nil)
+	_ delta.CheckedEntity = (*{{.Name}})(nil)
+)
+
+{{end}}
+{{- if .TypeID}}func init() {
+	delta.RegisterType({{.TypeID}}, func() delta.Entity { return &{{.Name}}{} }, func() delta.Delta { return &{{.Name}}Delta{} })
+}
+
+{{end -}}
+func (e *{{.Name}}{{.TypeArgs}}) GetID() {{.IDType}} {
+	return e.{{.IDField}}
+}
+
+func (e *{{.Name}}{{.TypeArgs}}) Clone() delta.Entity {
+	cp := *e
+	{{- range .Fields}}
+	{{- template "clone" .}}
+	{{- end}}
+	{{- range .Skipped}}
+	{{- template "clone" .}}
+	{{- end}}
+	return &cp
+}
+
+func (e *{{.Name}}{{.TypeArgs}}) Delta(o delta.Entity) delta.Delta {
+	if o == nil {
+		return nil
+	}
+	other, ok := o.(*{{.Name}}{{.TypeArgs}})
+	if !ok {
+		return nil // or panic
+	}
+	return e.Diff(other)
+}
+
+// Diff returns the changes that turn other into e, or all of e when other is
+// nil
+func (e *{{.Name}}{{.TypeArgs}}) Diff(other *{{.Name}}{{.TypeArgs}}) *{{.Name}}Delta{{.TypeArgs}} {
+	if other == nil {
+		other = &{{.Name}}{{.TypeArgs}}{}
+	}
+	d := &{{.Name}}Delta{{.TypeArgs}}{}
+	{{- range .Fields}}
+	{{- if hasCodec .}}
+	if !{{codecExpr .}}.Equal(e.{{.Name}}, other.{{.Name}}) {
+		v := {{codecExpr .}}.Clone(e.{{.Name}})
+		d.{{.Name}} = &v
+	}
+	{{- else if and .Elem (isSliceType .Type)}}
+	d.{{.Name}} = delta.DiffSlice[{{.Elem}}, *{{.Elem}}, {{.ElemID}}, *{{.Elem}}Delta](e.{{.Name}}, other.{{.Name}})
+	{{- else if .Elem}}
+	d.{{.Name}} = delta.DiffMap[{{getMapKeyType .Type}}, {{.Elem}}, *{{.Elem}}, {{.ElemID}}, *{{.Elem}}Delta](e.{{.Name}}, other.{{.Name}})
+	{{- else if .Interface}}
+	d.{{.Name}} = delta.DiffInterface(e.{{.Name}}, other.{{.Name}})
+	{{- else if isSet .}}
+	d.{{.Name}} = delta.DiffSet(e.{{.Name}}, other.{{.Name}}, {{setMember .}})
+	{{- else if usesCollectionHelpers .}}
+	if !{{equalCall .Type (printf "e.%s" .Name) (printf "other.%s" .Name)}} {
+		v := {{cloneCall .Type (printf "e.%s" .Name)}}
+		d.{{.Name}} = &v
+	}
+	{{- else if isSliceType .Type}}
+	if !delta.SlicesEqual(e.{{.Name}}, other.{{.Name}}) {
+		if e.{{.Name}} != nil {
+			v := make({{.Type}}, len(e.{{.Name}}))
+			copy(v, e.{{.Name}})
+			d.{{.Name}} = &v
+		} else {
+			d.{{.Name}} = &{{.Type}}{}
+		}
+	}
+	{{- else if isMapType .Type}}
+	if !delta.MapsEqual(e.{{.Name}}, other.{{.Name}}) {
+		if e.{{.Name}} != nil {
+			v := make({{.Type}})
+			for k, val := range e.{{.Name}} {
+				v[k] = val
+			}
+			d.{{.Name}} = &v
+		} else {
+			v := make({{.Type}})
+			d.{{.Name}} = &v
+		}
+	}
+	{{- else}}
+	if e.{{.Name}} != other.{{.Name}} {
+		v := e.{{.Name}}
+		d.{{.Name}} = &v
+	}
+	{{- end}}
+	{{- end}}
+	return d
+}
+
+func (e *{{.Name}}{{.TypeArgs}}) ApplyDelta(d delta.Delta) {
+	if d == nil {
+		return
+	}
+	dt, ok := d.(*{{.Name}}Delta{{.TypeArgs}})
+	if !ok {
+		return // or panic
+	}
+	dt.ApplyTo(e)
+}
+
+// ApplyDeltaChecked is like ApplyDelta, but reports a delta of another type or
+// one that fails validation instead of ignoring it
+func (e *{{.Name}}{{.TypeArgs}}) ApplyDeltaChecked(d delta.Delta) error {
+	if d == nil {
+		return nil
+	}
+	dt, ok := d.(*{{.Name}}Delta{{.TypeArgs}})
+	if !ok {
+		return delta.TypeMismatch("{{.Name}}", d)
+	}
+	return dt.ApplyToChecked(e)
+}
+
+// Apply applies d to e if it passes validation
+func (e *{{.Name}}{{.TypeArgs}}) Apply(d *{{.Name}}Delta{{.TypeArgs}}) error {
+	if d == nil {
+		return nil
+	}
+	return d.ApplyToChecked(e)
+}
+
+{{if not .TypeParams}}var _ delta.ValidatingDelta = (*{{.Name}}Delta)(nil)
+
+{{end -}}
+type {{.Name}}Delta{{.TypeParams}} struct {
+	{{- range .Fields}}
+	{{- if .Elem}}
+	{{.Name}} *delta.CollectionDelta[{{getCollectionKeyType .}}, *{{.Elem}}Delta]
+	{{- else if .Interface}}
+	{{.Name}} *delta.InterfaceDelta
+	{{- else if isSet .}}
+	{{.Name}} *delta.SetDelta[{{getMapKeyType .Type}}]
+	{{- else}}
+	{{.Name}} *{{.Type}}
+	{{- end}}
+	{{- end}}
+}
+
+// IsEmpty returns true if the delta carries no changes
+func (d *{{.Name}}Delta{{.TypeArgs}}) IsEmpty() bool {
+	return {{range $i, $field := .Fields}}{{if $i}} && {{end}}d.{{$field.Name}} == nil{{end}}
+}
+
+// Validate returns a *delta.ApplyError if d cannot be applied to e, because e
+// has another type or a field would leave the bounds of its tags, without
+// changing e. Deltas do not carry the ID of the entity they were computed for,
+// so a full state applied to another entity is only reported by delta.Route.
+func (d *{{.Name}}Delta{{.TypeArgs}}) Validate(e delta.Entity) error {
+	{{if validatesNested .}}et{{else}}_{{end}}, ok := e.(*{{.Name}}{{.TypeArgs}})
+	if !ok {
+		return delta.TypeMismatch("{{.Name}}", e)
+	}
+	{{- range .Fields}}
+	{{- if and .Elem (isSliceType .Type)}}
+	if err := delta.ValidateSlice[{{.Elem}}, *{{.Elem}}, {{.ElemID}}, *{{.Elem}}Delta]("{{$.Name}}", "{{.Name}}", et.{{.Name}}, d.{{.Name}}); err != nil {
+		return err
+	}
+	{{- else if .Elem}}
+	if err := delta.ValidateMap[{{getMapKeyType .Type}}, {{.Elem}}, *{{.Elem}}, {{.ElemID}}]("{{$.Name}}", "{{.Name}}", et.{{.Name}}, d.{{.Name}}); err != nil {
+		return err
+	}
+	{{- else if .Interface}}
+	if err := delta.ValidateInterface("{{$.Name}}", "{{.Name}}", et.{{.Name}}, d.{{.Name}}); err != nil {
+		return err
+	}
+	{{- else if or .Min .Max .MaxLen}}
+	if d.{{.Name}} != nil {
+		{{- if .Min}}
+		if err := delta.CheckMin("{{$.Name}}", "{{.Name}}", *d.{{.Name}}, {{.Min}}); err != nil {
+			return err
+		}
+		{{- end}}
+		{{- if .Max}}
+		if err := delta.CheckMax("{{$.Name}}", "{{.Name}}", *d.{{.Name}}, {{.Max}}); err != nil {
+			return err
+		}
+		{{- end}}
+		{{- if .MaxLen}}
+		if err := delta.CheckMaxLen("{{$.Name}}", "{{.Name}}", len(*d.{{.Name}}), {{.MaxLen}}); err != nil {
+			return err
+		}
+		{{- end}}
+	}
+	{{- end}}
+	{{- end}}
+	return nil
+}
+
+// ApplyToChecked applies d to e only if it passes Validate, so either all of d
+// is applied or none of it
+func (d *{{.Name}}Delta{{.TypeArgs}}) ApplyToChecked(e delta.Entity) error {
+	if err := d.Validate(e); err != nil {
+		return err
+	}
+	d.ApplyTo(e)
+	return nil
+}
+
+func (d *{{.Name}}Delta{{.TypeArgs}}) ApplyTo(e delta.Entity) {
+	et, ok := e.(*{{.Name}}{{.TypeArgs}})
+	if !ok {
+		return // or panic
+	}
+	{{- range .Fields}}
+	if d.{{.Name}} != nil {
+		{{- if hasCodec .}}
+		et.{{.Name}} = {{codecExpr .}}.Clone(*d.{{.Name}})
+		{{- else if and .Elem (isSliceType .Type)}}
+		delta.ApplySlice(&et.{{.Name}}, d.{{.Name}})
+		{{- else if .Elem}}
+		delta.ApplyMap[{{getMapKeyType .Type}}, {{.Elem}}, *{{.Elem}}, {{.ElemID}}](&et.{{.Name}}, d.{{.Name}})
+		{{- else if .Interface}}
+		delta.ApplyInterface(&et.{{.Name}}, d.{{.Name}})
+		{{- else if isSet .}}
+		delta.ApplySet(&et.{{.Name}}, d.{{.Name}}, {{setMember .}})
+		{{- else if usesCollectionHelpers .}}
+		et.{{.Name}} = {{cloneCall .Type (printf "*d.%s" .Name)}}
+		{{- else if isSliceType .Type}}
+		if *d.{{.Name}} != nil {
+			et.{{.Name}} = make({{.Type}}, len(*d.{{.Name}}))
+			copy(et.{{.Name}}, *d.{{.Name}})
+		} else {
+			et.{{.Name}} = nil
+		}
+		{{- else if isMapType .Type}}
+		if *d.{{.Name}} != nil {
+			et.{{.Name}} = make({{.Type}})
+			for k, v := range *d.{{.Name}} {
+				et.{{.Name}}[k] = v
+			}
+		} else {
+			et.{{.Name}} = nil
+		}
+		{{- else}}
+		et.{{.Name}} = *d.{{.Name}}
+		{{- end}}
+	}
+	{{- end}}
+}
+
+func (d *{{.Name}}Delta{{.TypeArgs}}) Serialize(w io.Writer) error {
+	bw := delta.NewBinaryWriter(w)
+	
+	// Write field presence bitmask
+	var fieldMask uint64
+	{{- range $i, $field := .Fields}}
+	if d.{{$field.Name}} != nil {
+		fieldMask |= 1 << {{$i}}
+	}
+	{{- end}}
+	if err := bw.WriteUint64(fieldMask); err != nil {
+		return err
+	}
+
+	// Write field values for present fields
+	{{- range $i, $field := .Fields}}
+	if d.{{$field.Name}} != nil {
+		{{- if hasCodec $field}}
+		// Serialize with codec
+		if err := {{codecExpr $field}}.Encode(bw, *d.{{$field.Name}}); err != nil {
+			return err
+		}
+		{{- else if $field.Elem}}
+		// Serialize entity collection
+		if err := delta.WriteCollection(bw, d.{{$field.Name}}, {{keyWriteFunc $field}}); err != nil {
+			return err
+		}
+		{{- else if $field.Interface}}
+		// Serialize interface
+		if err := delta.WriteInterface(bw, d.{{$field.Name}}); err != nil {
+			return err
+		}
+		{{- else if isSet $field}}
+		// Serialize set
+		if err := delta.WriteSet(bw, d.{{$field.Name}}, {{keyWriteFunc $field}}); err != nil {
+			return err
+		}
+		{{- else if usesCollectionHelpers $field}}
+		// Serialize {{if isNestedType $field.Type}}nested{{else}}generic{{end}} collection
+		if err := {{fieldWriteCall $field (printf "*d.%s" $field.Name)}}; err != nil {
+			return err
+		}
+		{{- else if isSliceType $field.Type}}
+		// Serialize slice
+		if err := bw.WriteVarUint32(uint32(len(*d.{{$field.Name}}))); err != nil {
+			return err
+		}
+		for _, item := range *d.{{$field.Name}} {
+			{{- $elementType := getSliceElementType $field.Type}}
+			{{- $method := getSerializeMethod $elementType}}
+			if err := bw.{{$method}}(item); err != nil {
+				return err
+			}
+		}
+		{{- else if isMapType $field.Type}}
+		// Serialize map
+		if err := bw.WriteVarUint32(uint32(len(*d.{{$field.Name}}))); err != nil {
+			return err
+		}
+		for _, k := range delta.SortedKeys(*d.{{$field.Name}}) {
+			v := (*d.{{$field.Name}})[k]
+			{{- $keyType := getMapKeyType $field.Type}}
+			{{- $valueType := getMapValueType $field.Type}}
+			{{- $keyMethod := getSerializeMethod $keyType}}
+			{{- $valueMethod := getSerializeMethod $valueType}}
+			if err := bw.{{$keyMethod}}(k); err != nil {
+				return err
+			}
+			if err := bw.{{$valueMethod}}(v); err != nil {
+				return err
+			}
+		}
+		{{- else if and (isTypeParam $field.Type) (not (isNamedPrimitive $field))}}
+		// Serialize type parameter
+		if err := delta.WriteValue(bw, *d.{{$field.Name}}); err != nil {
+			return err
+		}
+		{{- else}}
+		// Serialize primitive
+		{{- $method := getSerializeMethod (wireType $field)}}
+		if err := bw.{{$method}}({{toWire $field (printf "*d.%s" $field.Name)}}); err != nil {
+			return err
+		}
+		{{- end}}
+	}
+	{{- end}}
+	
+	return nil
+}
+
+func (d *{{.Name}}Delta{{.TypeArgs}}) Deserialize(r io.Reader) error {
+	br := delta.NewBinaryReader(r)
+	
+	// Read field presence bitmask
+	fieldMask, err := br.ReadFieldMask({{len .Fields}})
+	if err != nil {
+		return delta.WrapDecodeError("{{.Name}}", "", br.Offset(), err)
+	}
+
+	// Read field values for present fields
+	{{- range $i, $field := .Fields}}
+	if fieldMask & (1 << {{$i}}) != 0 {
+		offset := br.Offset()
+		{{- if hasCodec $field}}
+		// Deserialize with codec
+		val, err := {{codecExpr $field}}.Decode(br)
+		if err != nil {
+			return delta.WrapDecodeError("{{$.Name}}", "{{$field.Name}}", offset, err)
+		}
+		d.{{$field.Name}} = &val
+		{{- else if $field.Elem}}
+		// Deserialize entity collection
+		cd, err := delta.ReadCollection(br, {{keyReadFunc $field}}, func() *{{$field.Elem}}Delta { return &{{$field.Elem}}Delta{} })
+		if err != nil {
+			return delta.WrapDecodeError("{{$.Name}}", "{{$field.Name}}", offset, err)
+		}
+		d.{{$field.Name}} = cd
+		{{- else if $field.Interface}}
+		// Deserialize interface
+		id, err := delta.ReadInterface(br)
+		if err != nil {
+			return delta.WrapDecodeError("{{$.Name}}", "{{$field.Name}}", offset, err)
+		}
+		d.{{$field.Name}} = id
+		{{- else if isSet $field}}
+		// Deserialize set
+		sd, err := delta.ReadSet(br, {{keyReadFunc $field}})
+		if err != nil {
+			return delta.WrapDecodeError("{{$.Name}}", "{{$field.Name}}", offset, err)
+		}
+		d.{{$field.Name}} = sd
+		{{- else if usesCollectionHelpers $field}}
+		// Deserialize {{if isNestedType $field.Type}}nested{{else}}generic{{end}} collection
+		val, err := {{fieldReadCall $field}}
+		if err != nil {
+			return delta.WrapDecodeError("{{$.Name}}", "{{$field.Name}}", offset, err)
+		}
+		d.{{$field.Name}} = &val
+		{{- else if isSliceType $field.Type}}
+		// Deserialize slice
+		length, err := br.ReadVarUint32()
+		if err != nil {
+			return delta.WrapDecodeError("{{$.Name}}", "{{$field.Name}}", offset, err)
+		}
+		slice := make({{$field.Type}}, length)
+		for i := range slice {
+			{{- $elementType := getSliceElementType $field.Type}}
+			{{- $method := getDeserializeMethod $elementType}}
+			item, err := br.{{$method}}()
+			if err != nil {
+				return delta.WrapDecodeError("{{$.Name}}", "{{$field.Name}}", offset, err)
+			}
+			slice[i] = item
+		}
+		d.{{$field.Name}} = &slice
+		{{- else if isMapType $field.Type}}
+		// Deserialize map
+		length, err := br.ReadVarUint32()
+		if err != nil {
+			return delta.WrapDecodeError("{{$.Name}}", "{{$field.Name}}", offset, err)
+		}
+		m := make({{$field.Type}})
+		for i := uint32(0); i < length; i++ {
+			{{- $keyType := getMapKeyType $field.Type}}
+			{{- $valueType := getMapValueType $field.Type}}
+			{{- $keyMethod := getDeserializeMethod $keyType}}
+			{{- $valueMethod := getDeserializeMethod $valueType}}
+			k, err := br.{{$keyMethod}}()
+			if err != nil {
+				return delta.WrapDecodeError("{{$.Name}}", "{{$field.Name}}", offset, err)
+			}
+			v, err := br.{{$valueMethod}}()
+			if err != nil {
+				return delta.WrapDecodeError("{{$.Name}}", "{{$field.Name}}", offset, err)
+			}
+			m[k] = v
+		}
+		d.{{$field.Name}} = &m
+		{{- else if and (isTypeParam $field.Type) (not (isNamedPrimitive $field))}}
+		// Deserialize type parameter
+		val, err := delta.ReadValue[{{$field.Type}}](br)
+		if err != nil {
+			return delta.WrapDecodeError("{{$.Name}}", "{{$field.Name}}", offset, err)
+		}
+		d.{{$field.Name}} = &val
+		{{- else}}
+		// Deserialize primitive
+		{{- $method := getDeserializeMethod (wireType $field)}}
+		val, err := br.{{$method}}()
+		if err != nil {
+			return delta.WrapDecodeError("{{$.Name}}", "{{$field.Name}}", offset, err)
+		}
+		{{- if isNamedPrimitive $field}}
+		v := {{$field.Type}}(val)
+		d.{{$field.Name}} = &v
+		{{- else}}
+		d.{{$field.Name}} = &val
+		{{- end}}
+		{{- end}}
+	}
+	{{- end}}
+	
+	return nil
+}
+{{- block "extra" .}}{{end}}
+{{end}}
+
+{{define "clone"}}
+	{{- if hasCodec .}}
+	cp.{{.Name}} = {{codecExpr .}}.Clone(e.{{.Name}})
+	{{- else if .Interface}}
+	cp.{{.Name}} = delta.CloneInterface(e.{{.Name}})
+	{{- else if and .Elem (isSliceType .Type)}}
+	if e.{{.Name}} != nil {
+		cp.{{.Name}} = make({{.Type}}, len(e.{{.Name}}))
+		for i := range e.{{.Name}} {
+			cp.{{.Name}}[i] = *e.{{.Name}}[i].Clone().(*{{.Elem}})
+		}
+	}
+	{{- else if .Elem}}
+	if e.{{.Name}} != nil {
+		cp.{{.Name}} = make({{.Type}})
+		for k, v := range e.{{.Name}} {
+			cp.{{.Name}}[k] = *v.Clone().(*{{.Elem}})
+		}
+	}
+	{{- else if usesCollectionHelpers .}}
+	cp.{{.Name}} = {{cloneCall .Type (printf "e.%s" .Name)}}
+	{{- else if isSliceType .Type}}
+	if e.{{.Name}} != nil {
+		cp.{{.Name}} = make({{.Type}}, len(e.{{.Name}}))
+		copy(cp.{{.Name}}, e.{{.Name}})
+	}
+	{{- else if isMapType .Type}}
+	if e.{{.Name}} != nil {
+		cp.{{.Name}} = make({{.Type}})
+		for k, v := range e.{{.Name}} {
+			cp.{{.Name}}[k] = v
+		}
+	}
+	{{- end}}
+{{- end}}
+`))
+
+// loadTemplates returns a copy of the built-in templates base extended with
+// the templates defined in the files matching patterns, which can use the same
+// functions. A file defining a template with the name of a built-in one, such
+// as "entity", replaces it. The empty "imports" and "extra" templates of the Go
+// templates are hooks for adding imports to each file and declarations after
+// each entity; the TypeScript templates have an "extra" hook in each module.
+func loadTemplates(base *template.Template, patterns []string) (*template.Template, error) {
+	tmpl, err := base.Clone()
+	if err != nil {
+		return nil, err
+	}
+	for _, pattern := range patterns {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no template files match %s", pattern)
+		}
+		if _, err := tmpl.ParseFiles(files...); err != nil {
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
@@ -0,0 +1,8 @@
+// Package crew declares an entity in a second package for the generator tests
+package crew
+
+// delta:entity
+type Sailor struct {
+	ID   int64
+	Rank int32
+}
@@ -0,0 +1,15 @@
+// Package ships declares entities for the generator tests
+package ships
+
+// delta:entity
+type Ship struct {
+	ID    int64
+	Name  string
+	Speed float32
+}
+
+// delta:entity
+type Dock struct {
+	ID    int64
+	Ships []Ship
+}
@@ -0,0 +1,80 @@
+// Package invalid declares entities with fields that deltagen cannot
+// generate, to check that all of them are reported at once
+package invalid
+
+import (
+	"io"
+	"time"
+
+	"github.com/cbodonnell/delta/gen/testdata/invalid/other"
+)
+
+// delta:entity typeid=1
+type Ship struct {
+	ID     int64
+	Speed  int
+	Cargo  [4]int32
+	Target *Ship
+	Crew   map[string]*Ship
+	Orders chan string
+	Notes  any
+	Hull   uint8  `delta:"max=300"`
+	Name   string `delta:"maxlen=0"`
+	Fuel   float32
+}
+
+// delta:entity typeid=1
+type Dock struct {
+	ID    int64
+	Ships map[float64]Ship
+	Spec  struct{ Size int32 }
+	At    Point
+}
+
+// Point declares the methods of delta.DeltaMarshaler with other signatures, so
+// it cannot be encoded with them
+type Point struct{ X, Y float32 }
+
+func (p Point) Equal(other *Point) bool   { return other != nil && p == *other }
+func (p Point) Clone() Point              { return p }
+func (p Point) Encode(w io.Writer) error  { return nil }
+func (p *Point) Decode(r io.Reader) error { return nil }
+
+// Engine is held by the Drive field of Hangar
+type Engine interface{ Thrust() float32 }
+
+// delta:entity typeid=2
+type Jet struct {
+	ID    int64
+	Power float32
+}
+
+func (j *Jet) Thrust() float32 { return j.Power }
+
+// delta:entity
+type Sail struct {
+	ID   int64
+	Area float32
+}
+
+func (s *Sail) Thrust() float32 { return s.Area }
+
+// delta:entity
+type Hangar struct {
+	ID    int64
+	Drive Engine
+	time.Time
+}
+
+// delta:entity
+type Box[T other.Primitive] struct {
+	ID int64
+	V  T
+}
+
+// delta:entity
+type Level[T ~int32, U int32 | int64] struct {
+	ID     int64
+	Floor  T
+	Height U
+}
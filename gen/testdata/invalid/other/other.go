@@ -0,0 +1,5 @@
+// Package other declares a constraint named like delta.Primitive that admits
+// any type
+package other
+
+type Primitive interface{ any }
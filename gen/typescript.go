@@ -0,0 +1,362 @@
+package gen
+
+import (
+	_ "embed"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// tsRuntime is the TypeScript module decoding the primitive encodings of
+// BinaryWriter and the collection, set and interface deltas, written next to
+// the generated modules as delta.ts
+//
+//go:embed typescript/delta.ts
+var tsRuntime []byte
+
+// tsName converts a Go field name to a lowerCamelCase property name, e.g. ID
+// to id and HPMax to hpMax
+func tsName(name string) string {
+	runes := []rune(name)
+	i := 0
+	for i < len(runes) && unicode.IsUpper(runes[i]) {
+		i++
+	}
+	if i > 1 && i < len(runes) {
+		// Keep the last capital of an initialism for the next word: HPMax
+		i--
+	}
+	return strings.ToLower(string(runes[:i])) + string(runes[i:])
+}
+
+// tsElem returns the TypeScript name of an entity type, without the import
+// name that qualifies entities of other packages in Go
+func tsElem(elem string) string {
+	if i := strings.LastIndex(elem, "."); i >= 0 {
+		return elem[i+1:]
+	}
+	return elem
+}
+
+// tsTypeOf returns the TypeScript type decoded for a Go type
+func tsTypeOf(typeStr string) string {
+	switch typeStr {
+	case "bool":
+		return "boolean"
+	case "int8", "int16", "int32", "rune", "uint8", "byte", "uint16", "uint32", "float32", "float64":
+		return "number"
+	case "int64", "uint64":
+		return "bigint"
+	case "string":
+		return "string"
+	case "[]byte":
+		return "Uint8Array"
+	}
+	switch {
+	case isSliceType(typeStr):
+		return tsTypeOf(getSliceElementType(typeStr)) + "[]"
+	case isMapType(typeStr):
+		return fmt.Sprintf("Map<%s, %s>", tsTypeOf(getMapKeyType(typeStr)), tsTypeOf(getMapValueType(typeStr)))
+	}
+	// A type parameter
+	return typeStr
+}
+
+// tsType returns the TypeScript type of a field in the entity interface
+func tsType(f FieldInfo) string {
+	switch {
+	case hasCodec(f), f.Interface:
+		return "unknown"
+	case f.Elem != "" && isSliceType(f.Type):
+		return tsElem(f.Elem) + "[]"
+	case f.Elem != "":
+		return fmt.Sprintf("Map<%s, %s>", tsTypeOf(getMapKeyType(f.Type)), tsElem(f.Elem))
+	case isSetType(f.Type):
+		return fmt.Sprintf("Set<%s>", tsTypeOf(getMapKeyType(f.Type)))
+	}
+	return tsTypeOf(f.Type)
+}
+
+// tsDeltaType returns the TypeScript type of a field in the delta interface
+func tsDeltaType(f FieldInfo) string {
+	switch {
+	case hasCodec(f):
+		return "unknown"
+	case f.Interface:
+		return "delta.InterfaceDelta"
+	case f.Elem != "":
+		return fmt.Sprintf("delta.CollectionDelta<%s, %sDelta>", tsTypeOf(getCollectionKeyType(f)), tsElem(f.Elem))
+	case isSetType(f.Type):
+		return fmt.Sprintf("delta.SetDelta<%s>", tsTypeOf(getMapKeyType(f.Type)))
+	}
+	return tsTypeOf(f.Type)
+}
+
+// tsZero returns the zero value of a field, matching the Go zero value except
+// that nil collections are empty
+func tsZero(f FieldInfo) string {
+	switch {
+	case hasCodec(f), f.Interface:
+		return "null"
+	case f.Elem == "" && isSetType(f.Type):
+		return "new Set()"
+	}
+	switch tsTypeOf(f.Type) {
+	case "boolean":
+		return "false"
+	case "number":
+		return "0"
+	case "bigint":
+		return "0n"
+	case "string":
+		return `""`
+	case "Uint8Array":
+		return "new Uint8Array(0)"
+	}
+	switch {
+	case isSliceType(f.Type):
+		return "[]"
+	case isMapType(f.Type):
+		return "new Map()"
+	}
+	return "zero" + f.Type
+}
+
+// tsRead returns an expression reading the delta of a field from r
+func tsRead(f FieldInfo, r string) string {
+	switch {
+	case f.Codec != "":
+		return fmt.Sprintf("delta.readCodec(%q, %s)", f.Codec, r)
+	case f.Marshaler:
+		return fmt.Sprintf("delta.readCodec(%q, %s)", f.Type, r)
+	case f.Interface:
+		return fmt.Sprintf("delta.readInterface(%s)", r)
+	case f.Elem != "":
+		return fmt.Sprintf("delta.readCollection(%s, %s, deserialize%sDelta)", r, tsReader(getCollectionKeyType(f)), tsElem(f.Elem))
+	case isSetType(f.Type):
+		return fmt.Sprintf("delta.readSet(%s, %s)", r, tsReader(getMapKeyType(f.Type)))
+	}
+	return tsReadType(f.Type, r)
+}
+
+// tsReadType returns an expression reading a value of a Go type from r
+func tsReadType(typeStr, r string) string {
+	if method, err := getDeserializeMethod(typeStr); err == nil {
+		return fmt.Sprintf("%s.%s()", r, tsName(method))
+	}
+	switch {
+	case isSliceType(typeStr):
+		return fmt.Sprintf("delta.readSlice(%s, %s)", r, tsReader(getSliceElementType(typeStr)))
+	case isMapType(typeStr):
+		return fmt.Sprintf("delta.readMap(%s, %s, %s)", r, tsReader(getMapKeyType(typeStr)), tsReader(getMapValueType(typeStr)))
+	}
+	return fmt.Sprintf("read%s(%s)", typeStr, r)
+}
+
+// tsReader returns a delta.Reader function for a Go type
+func tsReader(typeStr string) string {
+	if isTypeParam(typeStr) {
+		return "read" + typeStr
+	}
+	return "(r) => " + tsReadType(typeStr, "r")
+}
+
+// tsApply returns the statement applying the delta of a field to e
+func tsApply(f FieldInfo) string {
+	name := tsName(f.Name)
+	switch {
+	case hasCodec(f):
+		return fmt.Sprintf("e.%s = d.%s;", name, name)
+	case f.Interface:
+		return fmt.Sprintf("e.%s = delta.applyInterface(e.%s, d.%s);", name, name, name)
+	case f.Elem != "" && isSliceType(f.Type):
+		elem := tsElem(f.Elem)
+		return fmt.Sprintf("e.%s = delta.applySlice(e.%s, d.%s, get%sID, new%s, apply%sDelta);", name, name, name, elem, elem, elem)
+	case f.Elem != "":
+		elem := tsElem(f.Elem)
+		return fmt.Sprintf("delta.applyMap(e.%s, d.%s, new%s, apply%sDelta);", name, name, elem, elem)
+	case isSetType(f.Type):
+		return fmt.Sprintf("delta.applySet(e.%s, d.%s);", name, name)
+	}
+	return fmt.Sprintf("e.%s = d.%s;", name, name)
+}
+
+// tsParams returns the type parameter list of a generic entity, e.g. "<T>"
+func tsParams(s StructInfo) string {
+	if len(s.Params) == 0 {
+		return ""
+	}
+	var names []string
+	for _, p := range s.Params {
+		names = append(names, p.Name)
+	}
+	return "<" + strings.Join(names, ", ") + ">"
+}
+
+// tsZeroParams returns the parameters of new<Name> taking the zero value of
+// each type parameter of a generic entity, e.g. "zeroT: T"
+func tsZeroParams(s StructInfo) string {
+	var params []string
+	for _, p := range s.Params {
+		params = append(params, fmt.Sprintf("zero%s: %s", p.Name, p.Name))
+	}
+	return strings.Join(params, ", ")
+}
+
+// tsReaderParams returns the extra parameters of deserialize<Name>Delta taking
+// a reader for each type parameter of a generic entity, e.g.
+// ", readT: delta.Reader<T>"
+func tsReaderParams(s StructInfo) string {
+	var b strings.Builder
+	for _, p := range s.Params {
+		fmt.Fprintf(&b, ", read%s: delta.Reader<%s>", p.Name, p.Name)
+	}
+	return b.String()
+}
+
+// tsImport is an import of the generated module of another entity
+type tsImport struct {
+	Module string
+	Names  string
+}
+
+// tsImports returns the imports of the modules of entities held in
+// collections by s
+func tsImports(s StructInfo) []tsImport {
+	names := make(map[string][]string)
+	for _, f := range s.Fields {
+		elem := tsElem(f.Elem)
+		if f.Elem == "" || elem == s.Name {
+			continue
+		}
+		names[elem] = append(names[elem], elem, elem+"Delta", "new"+elem, "deserialize"+elem+"Delta", "apply"+elem+"Delta")
+		if isSliceType(f.Type) {
+			names[elem] = append(names[elem], "get"+elem+"ID")
+		}
+	}
+
+	var imports []tsImport
+	for module, list := range names {
+		slices.Sort(list)
+		imports = append(imports, tsImport{Module: module, Names: strings.Join(slices.Compact(list), ", ")})
+	}
+	slices.SortFunc(imports, func(a, b tsImport) int { return strings.Compare(a.Module, b.Module) })
+	return imports
+}
+
+// tsFiles renders the TypeScript modules of structs: one module per entity,
+// the runtime and an index re-exporting them, per output directory
+func tsFiles(output string, structs []StructInfo, tmpl *template.Template) ([]File, error) {
+	if strings.HasSuffix(output, ".go") || strings.HasSuffix(output, ".ts") {
+		return nil, fmt.Errorf("output %s must be a directory for TypeScript", output)
+	}
+
+	dirs, byDir, err := groupDirs(output, structs, ".ts")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []File
+	for _, dir := range dirs {
+		files = append(files, File{Path: filepath.Join(dir, "delta.ts"), Content: tsRuntime})
+		for _, s := range byDir[dir] {
+			var buf strings.Builder
+			if err := tmpl.ExecuteTemplate(&buf, "module", s); err != nil {
+				return nil, fmt.Errorf("struct %s: executing template: %w", s.Name, err)
+			}
+			files = append(files, File{Path: filepath.Join(dir, s.Name+".ts"), Content: []byte(buf.String())})
+		}
+
+		var buf strings.Builder
+		if err := tmpl.ExecuteTemplate(&buf, "index", byDir[dir]); err != nil {
+			return nil, fmt.Errorf("executing template: %w", err)
+		}
+		files = append(files, File{Path: filepath.Join(dir, "index.ts"), Content: []byte(buf.String())})
+	}
+	return files, nil
+}
+
+var tsTemplates = template.Must(template.New("module").Funcs(template.FuncMap{
+	"tsName":         tsName,
+	"tsType":         tsType,
+	"tsTypeOf":       tsTypeOf,
+	"tsDeltaType":    tsDeltaType,
+	"tsZero":         tsZero,
+	"tsRead":         tsRead,
+	"tsApply":        tsApply,
+	"tsParams":       tsParams,
+	"tsZeroParams":   tsZeroParams,
+	"tsReaderParams": tsReaderParams,
+	"tsImports":      tsImports,
+}).Parse(`
+{{- define "module"}}// Code generated by deltagen. DO NOT EDIT.
+
+import * as delta from "./delta";
+{{- range tsImports .}}
+import { {{.Names}} } from "./{{.Module}}";
+{{- end}}
+
+export interface {{.Name}}{{tsParams .}} {
+{{- range .Fields}}
+  {{tsName .Name}}: {{tsType .}};
+{{- end}}
+}
+
+export interface {{.Name}}Delta{{tsParams .}} {
+{{- range .Fields}}
+  {{tsName .Name}}?: {{tsDeltaType .}};
+{{- end}}
+}
+
+export function new{{.Name}}{{tsParams .}}({{tsZeroParams .}}): {{.Name}}{{tsParams .}} {
+  return {
+  {{- range .Fields}}
+    {{tsName .Name}}: {{tsZero .}},
+  {{- end}}
+  };
+}
+
+export function get{{.Name}}ID{{tsParams .}}(e: {{.Name}}{{tsParams .}}): {{tsTypeOf .IDType}} {
+  return e.{{tsName .IDField}};
+}
+
+export function deserialize{{.Name}}Delta{{tsParams .}}(r: delta.BinaryReader{{tsReaderParams .}}): {{.Name}}Delta{{tsParams .}} {
+  const d: {{.Name}}Delta{{tsParams .}} = {};
+  const mask = r.readUint64();
+  {{- range $i, $field := .Fields}}
+  if (delta.hasField(mask, {{$i}})) {
+    d.{{tsName $field.Name}} = {{tsRead $field "r"}};
+  }
+  {{- end}}
+  return d;
+}
+
+export function apply{{.Name}}Delta{{tsParams .}}(e: {{.Name}}{{tsParams .}}, d: {{.Name}}Delta{{tsParams .}}): void {
+  {{- range .Fields}}
+  if (d.{{tsName .Name}} !== undefined) {
+    {{tsApply .}}
+  }
+  {{- end}}
+}
+{{- if .TypeID}}
+
+delta.registerType({{.TypeID}}, {
+  create: new{{.Name}},
+  read: deserialize{{.Name}}Delta,
+  apply: (e, d) => apply{{.Name}}Delta(e as {{.Name}}, d as {{.Name}}Delta),
+});
+{{- end}}
+{{- block "extra" .}}{{end}}
+{{end}}
+
+{{- define "index"}}// Code generated by deltagen. DO NOT EDIT.
+
+export * from "./delta";
+{{- range .}}
+export * from "./{{.Name}}";
+{{- end}}
+{{end}}
+`))
@@ -0,0 +1,356 @@
+package gen
+
+import (
+	"fmt"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// maxFields is the number of fields that fit in the presence bitmask
+const maxFields = 64
+
+// Diagnostic reports a problem with a field at a source position
+type Diagnostic struct {
+	Pos     token.Position
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s", d.Pos, d.Message)
+}
+
+// Diagnostics is the error returned when one or more structs cannot be generated
+type Diagnostics []Diagnostic
+
+func (ds Diagnostics) Error() string {
+	lines := make([]string, len(ds))
+	for i, d := range ds {
+		lines[i] = d.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Validate checks that every field of every struct has a supported type and
+// reports all problems at once, rather than leaving them to surface as compile
+// errors in the generated code
+func Validate(structs []StructInfo) error {
+	var diags Diagnostics
+	typeIDs := make(map[uint32]string)
+	for _, s := range structs {
+		diags = append(diags, s.problems...)
+		if s.TypeID != 0 {
+			if other, ok := typeIDs[s.TypeID]; ok {
+				diags = append(diags, Diagnostic{
+					Pos:     s.Pos,
+					Message: fmt.Sprintf("struct %s has typeid=%d, which is already used by %s", s.Name, s.TypeID, other),
+				})
+			}
+			typeIDs[s.TypeID] = s.Name
+			if s.TypeParams != "" {
+				diags = append(diags, Diagnostic{
+					Pos:     s.Pos,
+					Message: fmt.Sprintf("generic struct %s cannot be registered with a typeid", s.Name),
+				})
+			}
+		}
+
+		for _, p := range s.Params {
+			if !isPrimitiveConstraint(p.constraint) {
+				diags = append(diags, Diagnostic{
+					Pos:     p.Pos,
+					Message: fmt.Sprintf("type parameter %s has unsupported constraint %s: use delta.Primitive or a union of supported primitive types", p.Name, p.Constraint),
+				})
+			}
+		}
+
+		if len(s.Fields) > maxFields {
+			diags = append(diags, Diagnostic{
+				Pos:     s.Fields[maxFields].Pos,
+				Message: fmt.Sprintf("struct %s has %d fields, at most %d are supported (tag fields with delta:\"-\" to exclude them)", s.Name, len(s.Fields), maxFields),
+			})
+		}
+
+		var pkg *types.Package
+		if s.obj != nil {
+			pkg = s.obj.Pkg()
+		}
+		for _, f := range s.Fields {
+			if reason := unsupportedBounds(f); reason != "" {
+				diags = append(diags, Diagnostic{
+					Pos:     f.Pos,
+					Message: fmt.Sprintf("field %s has invalid bounds: %s", f.Name, reason),
+				})
+			}
+			if len(f.unregistered) > 0 {
+				diags = append(diags, Diagnostic{
+					Pos:     f.Pos,
+					Message: fmt.Sprintf("field %s has type %s, which is implemented by %s without a typeid: add typeid=N to their delta:entity comment so they can be encoded", f.Name, f.Type, strings.Join(f.unregistered, ", ")),
+				})
+			}
+			if f.Interface || hasCodec(f) {
+				continue
+			}
+			if f.Elem != "" {
+				if key := collectionKeyWire(f); !isIDType(key) {
+					diags = append(diags, Diagnostic{
+						Pos:     f.Pos,
+						Message: fmt.Sprintf("field %s has unsupported key type %s: entity maps must be keyed by a string or integer type", f.Name, getCollectionKeyType(f)),
+					})
+				}
+				continue
+			}
+			if reason := unsupportedType(f.typ, pkg); reason != "" {
+				diags = append(diags, Diagnostic{
+					Pos:     f.Pos,
+					Message: fmt.Sprintf("field %s has unsupported type %s: %s", f.Name, f.Type, reason),
+				})
+			}
+		}
+	}
+
+	if len(diags) > 0 {
+		return diags
+	}
+	return nil
+}
+
+// unsupportedBounds returns why the min, max and maxlen tags of a field cannot
+// be checked, or an empty string if they can or are not set. Bounds must be
+// constants representable by the field's type.
+func unsupportedBounds(f FieldInfo) string {
+	if f.Min == "" && f.Max == "" && f.MaxLen == "" {
+		return ""
+	}
+	if f.Elem != "" || f.Interface || hasCodec(f) || f.typ == nil {
+		return "min, max and maxlen are only supported on primitive, slice and map fields"
+	}
+
+	if f.Min != "" || f.Max != "" {
+		basic, ok := f.typ.Underlying().(*types.Basic)
+		if !ok || basic.Info()&(types.IsInteger|types.IsFloat) == 0 {
+			return fmt.Sprintf("min and max are only supported on integer and floating-point fields, not %s", f.Type)
+		}
+		for _, bound := range []string{f.Min, f.Max} {
+			if bound == "" {
+				continue
+			}
+			if _, err := evalConstant(fmt.Sprintf("%s(%s)", basic.Name(), bound)); err != nil {
+				return fmt.Sprintf("%s is not a constant representable as %s", bound, basic.Name())
+			}
+		}
+		if f.Min != "" && f.Max != "" {
+			if tv, _ := evalConstant(fmt.Sprintf("%s(%s) <= %s(%s)", basic.Name(), f.Min, basic.Name(), f.Max)); !constant.BoolVal(tv.Value) {
+				return fmt.Sprintf("min %s is greater than max %s", f.Min, f.Max)
+			}
+		}
+	}
+
+	if f.MaxLen != "" {
+		switch t := f.typ.Underlying().(type) {
+		case *types.Basic:
+			if t.Info()&types.IsString == 0 {
+				return fmt.Sprintf("maxlen is only supported on string, slice and map fields, not %s", f.Type)
+			}
+		case *types.Slice:
+		case *types.Map:
+			if isSet(f) {
+				return "maxlen is not supported on sets"
+			}
+		default:
+			return fmt.Sprintf("maxlen is only supported on string, slice and map fields, not %s", f.Type)
+		}
+		if n, err := strconv.ParseUint(f.MaxLen, 10, 31); err != nil || n == 0 {
+			return fmt.Sprintf("maxlen must be a positive integer, got %q", f.MaxLen)
+		}
+	}
+	return ""
+}
+
+// evalConstant evaluates a constant expression of predeclared types
+func evalConstant(expr string) (types.TypeAndValue, error) {
+	tv, err := types.Eval(token.NewFileSet(), nil, token.NoPos, expr)
+	if err == nil && tv.Value == nil {
+		err = fmt.Errorf("%s is not constant", expr)
+	}
+	return tv, err
+}
+
+// unsupportedType returns why the type of a field declared in package pkg
+// can't be generated, with a suggestion, or an empty string if it is
+// supported. Named primitive types are checked as their underlying types.
+// Problems with the elements of slices and maps name the innermost offending
+// type.
+func unsupportedType(t types.Type, pkg *types.Package) string {
+	if t == nil {
+		return ""
+	}
+	t = types.Unalias(t)
+	if m, ok := t.(*types.Map); ok && isSetMap(m) {
+		// Sets only encode their keys
+		t = types.Unalias(m.Key())
+	}
+	elem, reason := unsupportedElem(t, pkg)
+	if reason != "" && elem != t {
+		return fmt.Sprintf("element type %s: %s", types.TypeString(elem, types.RelativeTo(pkg)), reason)
+	}
+	return reason
+}
+
+// isSetMap returns true if m is a set-shaped map, map[K]struct{} or map[K]bool
+func isSetMap(m *types.Map) bool {
+	switch v := types.Unalias(m.Elem()).(type) {
+	case *types.Struct:
+		return v.NumFields() == 0
+	case *types.Named:
+		basic, ok := v.Underlying().(*types.Basic)
+		return ok && basic.Kind() == types.Bool
+	case *types.Basic:
+		return v.Kind() == types.Bool
+	}
+	return false
+}
+
+// unsupportedElem checks a type, recursing into the elements of slices and
+// maps, and returns the offending type and the reason
+func unsupportedElem(t types.Type, pkg *types.Package) (types.Type, string) {
+	switch u := t.(type) {
+	case *types.Array:
+		return t, "fixed-size arrays are not supported, use a slice"
+
+	case *types.Slice:
+		if basic, ok := types.Unalias(u.Elem()).(*types.Basic); ok && basic.Kind() == types.Uint8 {
+			return t, ""
+		}
+		return unsupportedElem(types.Unalias(u.Elem()), pkg)
+
+	case *types.Map:
+		key := types.Unalias(u.Key())
+		if unsupportedPrimitive(key, pkg) != "" {
+			return key, "map keys must be a primitive type"
+		}
+		return unsupportedElem(types.Unalias(u.Elem()), pkg)
+	}
+	return t, unsupportedPrimitive(t, pkg)
+}
+
+// unsupportedPrimitive returns why a non-collection type can't be generated,
+// or an empty string if it is supported
+func unsupportedPrimitive(t types.Type, pkg *types.Package) string {
+	switch u := t.(type) {
+	case *types.TypeParam:
+		return ""
+
+	case *types.Basic:
+		return unsupportedBasic(u)
+
+	case *types.Named:
+		if basic, ok := u.Underlying().(*types.Basic); ok {
+			return unsupportedBasic(basic)
+		}
+		if iface, ok := u.Underlying().(*types.Interface); ok && iface.Empty() {
+			return "empty interface fields are not supported, declare an interface implemented by registered delta:entity types or tag the field delta:\"-\" to exclude it"
+		}
+		if u.Obj().Pkg() != pkg {
+			return "named types from other packages must have a primitive underlying type, implement delta.DeltaMarshaler, register a codec and tag the field delta:\"codec=name\", or tag the field delta:\"-\" to exclude it"
+		}
+		return "named types must have a primitive underlying type, use a slice or map of delta:entity structs, implement delta.DeltaMarshaler, or tag the field delta:\"-\" to exclude it"
+
+	case *types.Pointer:
+		if named, ok := types.Unalias(u.Elem()).(*types.Named); ok {
+			return fmt.Sprintf("pointers are not supported; if %s is a delta:entity, store it by value to diff it by ID", types.TypeString(named, types.RelativeTo(pkg)))
+		}
+		return "pointer fields are not supported, use a value type or tag the field delta:\"-\" to exclude it"
+
+	case *types.Interface:
+		if u.Empty() {
+			return "empty interface fields are not supported, declare an interface implemented by registered delta:entity types or tag the field delta:\"-\" to exclude it"
+		}
+		return "anonymous interface fields are not supported, declare an interface implemented by registered delta:entity types or tag the field delta:\"-\" to exclude it"
+
+	case *types.Struct:
+		return "anonymous structs are not supported, declare a delta:entity or tag the field delta:\"-\" to exclude it"
+
+	case *types.Chan, *types.Signature:
+		return "channels and functions cannot be transmitted, tag the field delta:\"-\" to exclude it"
+	}
+	return "tag the field delta:\"-\" to exclude it"
+}
+
+// unsupportedBasic returns why a basic type can't be generated, or an empty
+// string if WriteValue encodes it
+func unsupportedBasic(t *types.Basic) string {
+	switch {
+	case isPrimitiveKind(t.Kind()):
+		return ""
+	case t.Kind() == types.Int || t.Kind() == types.Uint:
+		return fmt.Sprintf("use a sized integer type such as %s32 or %s64", t.Name(), t.Name())
+	}
+	return "use a supported primitive type"
+}
+
+// isPrimitiveConstraint returns true if a type parameter constraint only
+// admits types that WriteValue can encode: delta.Primitive, or an interface
+// whose type set is restricted to supported primitive types by a union or an
+// embedded constraint. The type set is the intersection of the embedded
+// elements, so one restricting element suffices.
+func isPrimitiveConstraint(constraint types.Type) bool {
+	if isDeltaPrimitive(constraint) {
+		return true
+	}
+	iface, ok := constraint.Underlying().(*types.Interface)
+	if !ok {
+		return false
+	}
+	for i := 0; i < iface.NumEmbeddeds(); i++ {
+		if restrictsToPrimitives(iface.EmbeddedType(i)) {
+			return true
+		}
+	}
+	return false
+}
+
+// restrictsToPrimitives returns true if every type in the type set of an
+// embedded interface element is a supported primitive type. Tilde terms admit
+// named types, which WriteValue does not encode.
+func restrictsToPrimitives(t types.Type) bool {
+	switch t := t.(type) {
+	case *types.Union:
+		for i := 0; i < t.Len(); i++ {
+			term := t.Term(i)
+			if term.Tilde() || !restrictsToPrimitives(term.Type()) {
+				return false
+			}
+		}
+		return true
+	case *types.Basic:
+		return isPrimitiveKind(t.Kind())
+	}
+	if isDeltaPrimitive(t) {
+		return true
+	}
+	if _, ok := t.Underlying().(*types.Interface); ok {
+		return isPrimitiveConstraint(t)
+	}
+	return false
+}
+
+// isDeltaPrimitive returns true if t is delta.Primitive
+func isDeltaPrimitive(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Name() == "Primitive" && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == deltaPath
+}
+
+// isPrimitiveKind returns true if values of the basic kind are encoded by
+// BinaryWriter
+func isPrimitiveKind(kind types.BasicKind) bool {
+	switch kind {
+	case types.Bool, types.Int8, types.Int16, types.Int32, types.Int64,
+		types.Uint8, types.Uint16, types.Uint32, types.Uint64,
+		types.Float32, types.Float64, types.String:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate_ReportsAllProblems(t *testing.T) {
+	structs, err := Parse("testdata/invalid")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	var diags Diagnostics
+	if err := Validate(structs); !errors.As(err, &diags) {
+		t.Fatalf("Validate() error = %v, want Diagnostics", err)
+	}
+	want := []string{
+		`invalid.go:15:2: field Speed has unsupported type int: use a sized integer type such as int32 or int64`,
+		`invalid.go:16:2: field Cargo has unsupported type [4]int32: fixed-size arrays are not supported, use a slice`,
+		`invalid.go:17:2: field Target has unsupported type *Ship: pointers are not supported; if Ship is a delta:entity, store it by value to diff it by ID`,
+		`invalid.go:18:2: field Crew has unsupported type map[string]*Ship: element type *Ship: pointers are not supported; if Ship is a delta:entity, store it by value to diff it by ID`,
+		`invalid.go:19:2: field Orders has unsupported type chan string: channels and functions cannot be transmitted, tag the field delta:"-" to exclude it`,
+		`invalid.go:20:2: field Notes has unsupported type any: empty interface fields are not supported, declare an interface implemented by registered delta:entity types or tag the field delta:"-" to exclude it`,
+		`invalid.go:21:2: field Hull has invalid bounds: 300 is not a constant representable as uint8`,
+		`invalid.go:22:2: field Name has invalid bounds: maxlen must be a positive integer, got "0"`,
+		`invalid.go:27:6: struct Dock has typeid=1, which is already used by Ship`,
+		`invalid.go:29:2: field Ships has unsupported key type float64: entity maps must be keyed by a string or integer type`,
+		`invalid.go:30:2: field Spec has unsupported type struct{Size int32}: anonymous structs are not supported, declare a delta:entity or tag the field delta:"-" to exclude it`,
+		`invalid.go:31:2: field At has unsupported type Point: named types must have a primitive underlying type, use a slice or map of delta:entity structs, implement delta.DeltaMarshaler, or tag the field delta:"-" to exclude it`,
+		`invalid.go:66:7: embedded struct time.Time has no fields accessible from package invalid: declare it as a named field of a supported type, or tag it delta:"-" to exclude it`,
+		`invalid.go:65:2: field Drive has type Engine, which is implemented by Sail without a typeid: add typeid=N to their delta:entity comment so they can be encoded`,
+		`invalid.go:70:10: type parameter T has unsupported constraint other.Primitive: use delta.Primitive or a union of supported primitive types`,
+		`invalid.go:76:12: type parameter T has unsupported constraint ~int32: use delta.Primitive or a union of supported primitive types`,
+	}
+	if len(diags) != len(want) {
+		t.Errorf("Validate() returned %d diagnostics, want %d:\n%v", len(diags), len(want), diags)
+	}
+	for i := range min(len(diags), len(want)) {
+		d := diags[i]
+		got := fmt.Sprintf("%s:%d:%d: %s", filepath.Base(d.Pos.Filename), d.Pos.Line, d.Pos.Column, d.Message)
+		if got != want[i] {
+			t.Errorf("diagnostic %d = %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+func TestValidate_Generate(t *testing.T) {
+	// Generate validates before rendering and returns the diagnostics as is
+	_, err := Generate(Config{Input: "testdata/invalid"})
+	var diags Diagnostics
+	if !errors.As(err, &diags) || len(diags) == 0 {
+		t.Fatalf("Generate() error = %v, want Diagnostics", err)
+	}
+	if got, want := diags[0].String(), "invalid.go:15:2: "+diags[0].Message; !strings.HasSuffix(got, want) {
+		t.Errorf("Diagnostic.String() = %s, want it to end in %s", got, want)
+	}
+	if got := strings.Count(err.Error(), "\n") + 1; got != len(diags) {
+		t.Errorf("Diagnostics.Error() has %d lines, want one per diagnostic", got)
+	}
+}
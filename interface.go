@@ -0,0 +1,134 @@
+package delta
+
+import "fmt"
+
+// InterfaceDelta describes a change to an interface-typed field holding
+// registered entities. When the concrete type changes, the new value is sent
+// in full as a delta from its zero value; otherwise only the nested delta is
+// sent. A TypeID of 0 sets the field to nil.
+type InterfaceDelta struct {
+	TypeID uint32
+	Full   bool
+	Delta  Delta
+}
+
+// DiffInterface returns the change that turns the value to into from, or nil
+// if there is none. A from value that could not be decoded, because it is not
+// an Entity or its type has not been registered with RegisterType, panics
+// rather than being sent as nil. Deltagen reports annotated entities
+// implementing the interface without a typeid, so only values of other types
+// can cause the panic.
+func DiffInterface[T any](from, to T) *InterfaceDelta {
+	fromEntity, ok := any(from).(Entity)
+	if !ok && any(from) != nil {
+		panic(fmt.Sprintf("delta: type %T is not an entity", from))
+	}
+	toEntity, _ := any(to).(Entity)
+	if fromEntity == nil {
+		if toEntity == nil {
+			return nil
+		}
+		return &InterfaceDelta{}
+	}
+
+	id, ok := TypeIDOf(fromEntity)
+	if !ok {
+		panic(fmt.Sprintf("delta: type %T is not registered", fromEntity))
+	}
+
+	if toEntity != nil {
+		if toID, _ := TypeIDOf(toEntity); toID == id {
+			d := fromEntity.Delta(toEntity)
+			if e, ok := d.(ElementDelta); ok && e.IsEmpty() {
+				return nil
+			}
+			return &InterfaceDelta{TypeID: id, Delta: d}
+		}
+	}
+
+	zero, err := NewEntity(id)
+	if err != nil {
+		panic(err)
+	}
+	return &InterfaceDelta{TypeID: id, Full: true, Delta: fromEntity.Delta(zero)}
+}
+
+// ApplyInterface applies id to the interface field, replacing its value when
+// the concrete type changed and applying the nested delta in place otherwise
+func ApplyInterface[T any](field *T, id *InterfaceDelta) {
+	if id == nil {
+		return
+	}
+
+	if id.TypeID == 0 {
+		var zero T
+		*field = zero
+		return
+	}
+
+	if !id.Full {
+		if e, ok := any(*field).(Entity); ok {
+			id.Delta.ApplyTo(e)
+		}
+		return
+	}
+
+	e, err := NewEntity(id.TypeID)
+	if err != nil {
+		return
+	}
+	id.Delta.ApplyTo(e)
+	if v, ok := e.(T); ok {
+		*field = v
+	}
+}
+
+// CloneInterface returns a deep copy of the entity held by an interface field
+func CloneInterface[T any](v T) T {
+	e, ok := any(v).(Entity)
+	if !ok {
+		return v
+	}
+	cp, _ := e.Clone().(T)
+	return cp
+}
+
+// WriteInterface writes the type ID as a varint, followed for non-nil values
+// by the full flag and the nested delta
+func WriteInterface(bw *BinaryWriter, id *InterfaceDelta) error {
+	if err := bw.WriteVarUint32(id.TypeID); err != nil {
+		return err
+	}
+	if id.TypeID == 0 {
+		return nil
+	}
+	if err := bw.WriteBool(id.Full); err != nil {
+		return err
+	}
+	return id.Delta.Serialize(bw)
+}
+
+// ReadInterface reads an interface delta written by WriteInterface,
+// constructing the nested delta from the registered type
+func ReadInterface(br *BinaryReader) (*InterfaceDelta, error) {
+	typeID, err := br.ReadVarUint32()
+	if err != nil {
+		return nil, err
+	}
+	if typeID == 0 {
+		return &InterfaceDelta{}, nil
+	}
+
+	full, err := br.ReadBool()
+	if err != nil {
+		return nil, err
+	}
+	d, err := NewDelta(typeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.Deserialize(br); err != nil {
+		return nil, err
+	}
+	return &InterfaceDelta{TypeID: typeID, Full: full, Delta: d}, nil
+}
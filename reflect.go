@@ -0,0 +1,1476 @@
+package delta
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// Diff returns the changes that turn old into new, two structs or pointers to
+// structs of the same type, without generated code. Fields are found by
+// reflection following the same rules as deltagen, and the delta serializes to
+// exactly the bytes of the generated delta for the type, so generated and
+// reflective peers interoperate. Entity collections hold structs with an
+// identity field, and nested entities are diffed by reflection as well.
+func Diff(old, new any) (Delta, error) {
+	from, err := structValue(new)
+	if err != nil {
+		return nil, err
+	}
+	to, err := structValue(old)
+	if err != nil {
+		return nil, err
+	}
+	if from.Type() != to.Type() {
+		return nil, fmt.Errorf("delta: cannot diff %s against %s", from.Type(), to.Type())
+	}
+
+	s, err := reflectStructOf(from.Type())
+	if err != nil {
+		return nil, err
+	}
+	return s.diff(from, to), nil
+}
+
+// Apply applies d to target, a pointer to a struct. Deltas returned by Diff or
+// NewReflectDelta must be for the struct type of target. Generated deltas must
+// validate against target, which must then be a generated entity of the same
+// type, and are applied through the wire format they share with reflective
+// ones. Like ApplyDeltaChecked, Apply returns an *ApplyError without changing
+// target when a field would leave the bounds of its min, max or maxlen tags.
+func Apply(target any, d Delta) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("delta: cannot apply to %T, want a non-nil pointer to a struct", target)
+	}
+	if d == nil {
+		return nil
+	}
+
+	rd, ok := d.(*ReflectDelta)
+	if !ok {
+		vd, ok := d.(ValidatingDelta)
+		e, isEntity := target.(Entity)
+		if !ok || !isEntity {
+			return fmt.Errorf("delta: cannot apply %T to %T", d, target)
+		}
+		if err := vd.Validate(e); err != nil {
+			return err
+		}
+		var err error
+		if rd, err = NewReflectDelta(target); err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := d.Serialize(&buf); err != nil {
+			return err
+		}
+		if err := rd.Deserialize(&buf); err != nil {
+			return fmt.Errorf("delta: %T does not match %s: %w", d, rd.s.typ, err)
+		}
+	}
+	if rd.s.typ != v.Elem().Type() {
+		return fmt.Errorf("delta: cannot apply a delta of %s to %s", rd.s.typ, v.Elem().Type())
+	}
+	if err := rd.s.validate(rd, v.Elem()); err != nil {
+		return err
+	}
+	rd.s.apply(rd, v.Elem())
+	return nil
+}
+
+// ReflectDelta is a delta computed by reflection. It implements ElementDelta,
+// so it can be used wherever generated deltas are.
+type ReflectDelta struct {
+	s *reflectStruct
+
+	// values holds the change to each field, or nil if the field is unchanged
+	values []any
+}
+
+// NewReflectDelta returns an empty delta for the struct type of v, a struct or
+// a pointer to one, ready to be filled by Deserialize
+func NewReflectDelta(v any) (*ReflectDelta, error) {
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("delta: %T is not a struct or pointer to a struct", v)
+	}
+	s, err := reflectStructOf(t)
+	if err != nil {
+		return nil, err
+	}
+	return s.newDelta(), nil
+}
+
+// IsEmpty returns true if the delta carries no changes
+func (d *ReflectDelta) IsEmpty() bool {
+	for _, v := range d.values {
+		if v != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyTo applies the delta to e, which must be a pointer to the struct type
+// of the delta. Like generated deltas, it does nothing for other types, and
+// it applies changes outside the bounds of the min, max and maxlen tags.
+func (d *ReflectDelta) ApplyTo(e Entity) {
+	v := reflect.ValueOf(e)
+	if v.Kind() == reflect.Pointer && !v.IsNil() && v.Elem().Type() == d.s.typ {
+		d.s.apply(d, v.Elem())
+	}
+}
+
+// Validate returns an *ApplyError if d cannot be applied to e, because e is
+// not a pointer to the struct type of the delta or a field would leave the
+// bounds of its tags, without changing e
+func (d *ReflectDelta) Validate(e Entity) error {
+	v := reflect.ValueOf(e)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Type() != d.s.typ {
+		return TypeMismatch(d.s.typ.Name(), e)
+	}
+	return d.s.validate(d, v.Elem())
+}
+
+// Serialize writes the delta in the format of the generated delta
+func (d *ReflectDelta) Serialize(w io.Writer) error {
+	bw := NewBinaryWriter(w)
+
+	var fieldMask uint64
+	for i, v := range d.values {
+		if v != nil {
+			fieldMask |= 1 << i
+		}
+	}
+	if err := bw.WriteUint64(fieldMask); err != nil {
+		return err
+	}
+
+	for i, v := range d.values {
+		if v == nil {
+			continue
+		}
+		if err := d.s.fields[i].write(bw, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Deserialize reads a delta written by Serialize or by the generated delta
+func (d *ReflectDelta) Deserialize(r io.Reader) error {
+	br := NewBinaryReader(r)
+
+	fieldMask, err := br.ReadFieldMask(len(d.s.fields))
+	if err != nil {
+		return WrapDecodeError(d.s.typ.Name(), "", br.Offset(), err)
+	}
+
+	for i, f := range d.s.fields {
+		if fieldMask&(1<<i) == 0 {
+			continue
+		}
+		offset := br.Offset()
+		if d.values[i], err = f.read(br); err != nil {
+			return WrapDecodeError(d.s.typ.Name(), f.name, offset, err)
+		}
+	}
+	return nil
+}
+
+// fieldKind is how a field is diffed and encoded
+type fieldKind int
+
+const (
+	kindValue       fieldKind = iota // primitives, []byte and slices and maps of them
+	kindCodec                        // a registered codec or DeltaMarshaler
+	kindInterface                    // an interface holding registered entities
+	kindSet                          // map[K]struct{} or map[K]bool
+	kindEntitySlice                  // a slice of entities diffed by ID
+	kindEntityMap                    // a map of entities diffed by key
+)
+
+// reflectStruct describes the fields of a struct type as deltagen would
+// generate them
+type reflectStruct struct {
+	typ    reflect.Type
+	fields []*reflectField
+	id     []int // index of the identity field
+}
+
+// reflectField is a field of a reflectStruct
+type reflectField struct {
+	name   string
+	index  []int
+	typ    reflect.Type
+	kind   fieldKind
+	codec  *reflectCodec
+	elem   *reflectStruct // entity of kindEntitySlice and kindEntityMap fields
+	member reflect.Value  // value of the members of kindSet fields
+	bounds *reflectBounds // limits of the min, max and maxlen tags, or nil
+}
+
+// reflectBounds are the limits set by the min, max and maxlen tags of a
+// kindValue field
+type reflectBounds struct {
+	min, max reflect.Value // int64, uint64 or float64, invalid when not set
+	maxLen   int           // 0 when not set
+}
+
+// reflectCodec compares, clones and encodes the values of a codec field
+type reflectCodec struct {
+	equal  func(a, b reflect.Value) bool
+	clone  func(v reflect.Value) reflect.Value
+	encode func(bw *BinaryWriter, v reflect.Value) error
+	decode func(br *BinaryReader) (reflect.Value, error)
+}
+
+// setChange is the change to a kindSet field
+type setChange struct {
+	added, removed []reflect.Value
+}
+
+// collectionChange is the change to a kindEntitySlice or kindEntityMap field
+type collectionChange struct {
+	added, changed []collectionEntry
+	removed        []reflect.Value
+	order          []reflect.Value
+	hasOrder       bool
+}
+
+// collectionEntry is the delta of an entity added to or changed in a
+// collection
+type collectionEntry struct {
+	key reflect.Value
+	d   *ReflectDelta
+}
+
+var reflectStructs sync.Map // reflect.Type to *reflectStruct
+
+// reflectStructOf returns the description of struct type t, building and
+// caching it on first use
+func reflectStructOf(t reflect.Type) (*reflectStruct, error) {
+	if s, ok := reflectStructs.Load(t); ok {
+		return s.(*reflectStruct), nil
+	}
+
+	// Entities may hold collections of themselves, so structs being built are
+	// shared until all of them are complete
+	building := make(map[reflect.Type]*reflectStruct)
+	if _, err := buildReflectStruct(t, building); err != nil {
+		return nil, err
+	}
+	for t, s := range building {
+		reflectStructs.LoadOrStore(t, s)
+	}
+	actual, _ := reflectStructs.Load(t)
+	return actual.(*reflectStruct), nil
+}
+
+// embeddedReflectField is a field found in a struct or the structs it embeds
+type embeddedReflectField struct {
+	field reflect.StructField
+	index []int
+	tag   []string
+	depth int
+	skip  bool
+}
+
+func buildReflectStruct(t reflect.Type, building map[reflect.Type]*reflectStruct) (*reflectStruct, error) {
+	if s, ok := building[t]; ok {
+		return s, nil
+	}
+	if s, ok := reflectStructs.Load(t); ok {
+		return s.(*reflectStruct), nil
+	}
+	s := &reflectStruct{typ: t}
+	building[t] = s
+
+	fields, err := reflectStructFields(t, t.PkgPath(), nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("delta: struct %s: %w", t, err)
+	}
+	fields, err = promoteReflectFields(fields)
+	if err != nil {
+		return nil, fmt.Errorf("delta: struct %s: %w", t, err)
+	}
+
+	var included []embeddedReflectField
+	for _, f := range fields {
+		if f.skip {
+			continue
+		}
+		included = append(included, f)
+		rf, err := newReflectField(f, building)
+		if err == nil {
+			rf.bounds, err = reflectBoundsOf(f.tag, rf)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("delta: struct %s: field %s: %w", t, f.field.Name, err)
+		}
+		s.fields = append(s.fields, rf)
+	}
+	if len(s.fields) > 64 {
+		return nil, fmt.Errorf("delta: struct %s has %d fields, more than the 64 a delta can hold", t, len(s.fields))
+	}
+
+	id, err := reflectIdentityField(included)
+	if err != nil {
+		return nil, fmt.Errorf("delta: struct %s: %w", t, err)
+	}
+	s.id = id
+	return s, nil
+}
+
+// reflectStructFields returns the fields of t, flattening embedded structs
+func reflectStructFields(t reflect.Type, pkgPath string, index []int, depth int) ([]embeddedReflectField, error) {
+	var fields []embeddedReflectField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := reflectTagOptions(f.Tag)
+		skip := reflectHasTagOption(tag, "-")
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		if f.Anonymous {
+			if skip {
+				continue
+			}
+			if f.Type.Kind() == reflect.Pointer {
+				return nil, fmt.Errorf("embedded pointer %s is not supported", f.Type)
+			}
+			// Embedded types that are not structs are fields named after
+			// their type
+			if f.Type.Kind() == reflect.Struct {
+				promoted, err := reflectStructFields(f.Type, pkgPath, fieldIndex, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				if len(promoted) == 0 && f.Type.NumField() > 0 {
+					return nil, fmt.Errorf("embedded struct %s has no fields accessible from package %s", f.Type, pkgPath)
+				}
+				fields = append(fields, promoted...)
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			if f.PkgPath != pkgPath {
+				if reflectHasTagOption(tag, "include") {
+					return nil, fmt.Errorf("unexported field %s of package %s cannot be included", f.Name, f.PkgPath)
+				}
+				continue
+			}
+			if !reflectHasTagOption(tag, "include") {
+				skip = true
+			}
+		}
+
+		fields = append(fields, embeddedReflectField{field: f, index: fieldIndex, tag: tag, depth: depth, skip: skip})
+	}
+	return fields, nil
+}
+
+// promoteReflectFields applies Go's field promotion rules, like deltagen
+func promoteReflectFields(fields []embeddedReflectField) ([]embeddedReflectField, error) {
+	shallowest := make(map[string]int)
+	count := make(map[string]int)
+	for _, f := range fields {
+		d, ok := shallowest[f.field.Name]
+		switch {
+		case !ok || f.depth < d:
+			shallowest[f.field.Name] = f.depth
+			count[f.field.Name] = 1
+		case f.depth == d:
+			count[f.field.Name]++
+		}
+	}
+
+	var promoted []embeddedReflectField
+	for _, f := range fields {
+		if f.depth != shallowest[f.field.Name] {
+			continue
+		}
+		if count[f.field.Name] > 1 {
+			return nil, fmt.Errorf("ambiguous field %s promoted from multiple embedded structs", f.field.Name)
+		}
+		promoted = append(promoted, f)
+	}
+	return promoted, nil
+}
+
+// reflectIdentityField returns the index of the field tagged delta:"id", or of
+// the field named ID when no field is tagged
+func reflectIdentityField(fields []embeddedReflectField) ([]int, error) {
+	var id *embeddedReflectField
+	for i, f := range fields {
+		if !reflectHasTagOption(f.tag, "id") {
+			continue
+		}
+		if id != nil {
+			return nil, fmt.Errorf("fields %s and %s are both tagged delta:\"id\"", id.field.Name, f.field.Name)
+		}
+		id = &fields[i]
+	}
+	if id == nil {
+		for i, f := range fields {
+			if f.field.Name == "ID" {
+				id = &fields[i]
+				break
+			}
+		}
+	}
+	if id == nil {
+		return nil, errors.New("does not have an ID field or a field tagged delta:\"id\"")
+	}
+	switch id.field.Type.Kind() {
+	case reflect.String, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return id.index, nil
+	}
+	return nil, fmt.Errorf("identity field %s has type %s, want a string or integer type", id.field.Name, id.field.Type)
+}
+
+// reflectTagOptions returns the comma-separated options of the delta key of a
+// struct tag
+func reflectTagOptions(tag reflect.StructTag) []string {
+	value, ok := tag.Lookup("delta")
+	if !ok {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// reflectHasTagOption returns true if the tag options contain option
+func reflectHasTagOption(tag []string, option string) bool {
+	for _, t := range tag {
+		if strings.TrimSpace(t) == option {
+			return true
+		}
+	}
+	return false
+}
+
+// reflectTagValue returns the value of a key=value option in the tag options
+func reflectTagValue(tag []string, key string) string {
+	for _, t := range tag {
+		if k, v, ok := strings.Cut(strings.TrimSpace(t), "="); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
+
+// newReflectField classifies a field the way deltagen does: codecs first, then
+// DeltaMarshaler types, interfaces, sets, entity collections and values
+func newReflectField(f embeddedReflectField, building map[reflect.Type]*reflectStruct) (*reflectField, error) {
+	t := f.field.Type
+	rf := &reflectField{name: f.field.Name, index: f.index, typ: t}
+
+	if name := reflectTagValue(f.tag, "codec"); name != "" {
+		c, err := registeredReflectCodec(name, t)
+		if err != nil {
+			return nil, err
+		}
+		rf.kind, rf.codec = kindCodec, c
+		return rf, nil
+	}
+	if c, ok := marshalerReflectCodec(t); ok {
+		rf.kind, rf.codec = kindCodec, c
+		return rf, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		if t.NumMethod() == 0 || t.PkgPath() == "" {
+			return nil, fmt.Errorf("interface type %s is not supported, use a named interface implemented by registered entities", t)
+		}
+		rf.kind = kindInterface
+		return rf, nil
+	case reflect.Map:
+		switch {
+		case t.Elem().Kind() == reflect.Bool:
+			rf.kind, rf.member = kindSet, reflect.ValueOf(true).Convert(t.Elem())
+			return rf, validateReflectValue(t.Key())
+		case t.Elem().Kind() == reflect.Struct && t.Elem().NumField() == 0:
+			rf.kind, rf.member = kindSet, reflect.New(t.Elem()).Elem()
+			return rf, validateReflectValue(t.Key())
+		case t.Elem().Kind() == reflect.Struct:
+			elem, err := buildReflectStruct(t.Elem(), building)
+			if err != nil {
+				return nil, err
+			}
+			rf.kind, rf.elem = kindEntityMap, elem
+			return rf, validateReflectValue(t.Key())
+		}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Struct {
+			elem, err := buildReflectStruct(t.Elem(), building)
+			if err != nil {
+				return nil, err
+			}
+			rf.kind, rf.elem = kindEntitySlice, elem
+			return rf, nil
+		}
+	}
+	rf.kind = kindValue
+	return rf, validateReflectValue(t)
+}
+
+// reflectBoundsOf parses the min, max and maxlen tags of rf like deltagen
+// checks them, returning nil if none is set
+func reflectBoundsOf(tag []string, rf *reflectField) (*reflectBounds, error) {
+	min, max, maxLen := reflectTagValue(tag, "min"), reflectTagValue(tag, "max"), reflectTagValue(tag, "maxlen")
+	if min == "" && max == "" && maxLen == "" {
+		return nil, nil
+	}
+	if rf.kind != kindValue {
+		return nil, errors.New("min, max and maxlen are only supported on primitive, slice and map fields")
+	}
+
+	b := &reflectBounds{}
+	var err error
+	if b.min, err = parseReflectBound(min, rf.typ); err != nil {
+		return nil, err
+	}
+	if b.max, err = parseReflectBound(max, rf.typ); err != nil {
+		return nil, err
+	}
+	if b.min.IsValid() && b.max.IsValid() && compareValues(b.min, b.max) > 0 {
+		return nil, fmt.Errorf("min %s is greater than max %s", min, max)
+	}
+
+	if maxLen != "" {
+		switch rf.typ.Kind() {
+		case reflect.String, reflect.Slice, reflect.Map:
+		default:
+			return nil, fmt.Errorf("maxlen is only supported on string, slice and map fields, not %s", rf.typ)
+		}
+		n, err := strconv.ParseUint(maxLen, 10, 31)
+		if err != nil || n == 0 {
+			return nil, fmt.Errorf("maxlen must be a positive integer, got %q", maxLen)
+		}
+		b.maxLen = int(n)
+	}
+	return b, nil
+}
+
+// parseReflectBound parses the min or max bound s of a field of type t as an
+// int64, uint64 or float64, or returns an invalid value if s is empty
+func parseReflectBound(s string, t reflect.Type) (reflect.Value, error) {
+	if s == "" {
+		return reflect.Value{}, nil
+	}
+	var v any
+	var err error
+	switch t.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err = strconv.ParseInt(s, 0, t.Bits())
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err = strconv.ParseUint(s, 0, t.Bits())
+	case reflect.Float32, reflect.Float64:
+		v, err = strconv.ParseFloat(s, t.Bits())
+	default:
+		return reflect.Value{}, fmt.Errorf("min and max are only supported on integer and floating-point fields, not %s", t)
+	}
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("%s is not a constant representable as %s", s, t)
+	}
+	return reflect.ValueOf(v), nil
+}
+
+// check returns an *ApplyError wrapping ErrOutOfRange if v, the new value of
+// the field, leaves the bounds
+func (b *reflectBounds) check(entity, field string, v reflect.Value) error {
+	if b.min.IsValid() {
+		if err := checkReflectBound(entity, field, v, b.min, CheckMin[int64], CheckMin[uint64], CheckMin[float64]); err != nil {
+			return err
+		}
+	}
+	if b.max.IsValid() {
+		if err := checkReflectBound(entity, field, v, b.max, CheckMax[int64], CheckMax[uint64], CheckMax[float64]); err != nil {
+			return err
+		}
+	}
+	if b.maxLen > 0 {
+		return CheckMaxLen(entity, field, v.Len(), b.maxLen)
+	}
+	return nil
+}
+
+// checkReflectBound checks v against bound with the check for its kind
+func checkReflectBound(entity, field string, v, bound reflect.Value,
+	checkInt func(string, string, int64, int64) error,
+	checkUint func(string, string, uint64, uint64) error,
+	checkFloat func(string, string, float64, float64) error) error {
+	switch {
+	case v.CanInt():
+		return checkInt(entity, field, v.Int(), bound.Int())
+	case v.CanUint():
+		return checkUint(entity, field, v.Uint(), bound.Uint())
+	}
+	return checkFloat(entity, field, v.Float(), bound.Float())
+}
+
+// validateReflectValue returns an error if values of t cannot be encoded by
+// BinaryWriter or as slices and maps of such values
+func validateReflectValue(t reflect.Type) error {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String:
+		return nil
+	case reflect.Slice:
+		return validateReflectValue(t.Elem())
+	case reflect.Map:
+		if err := validateReflectValue(t.Key()); err != nil {
+			return err
+		}
+		return validateReflectValue(t.Elem())
+	case reflect.Int, reflect.Uint:
+		return fmt.Errorf("unsupported type %s: use a sized integer type such as int32 or int64", t)
+	}
+	return fmt.Errorf("unsupported type %s", t)
+}
+
+// registeredReflectCodec returns the codec registered under name, which must
+// encode values of t
+func registeredReflectCodec(name string, t reflect.Type) (*reflectCodec, error) {
+	codecs.RLock()
+	c, ok := codecs.byName[name]
+	codecs.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("codec %q is not registered", name)
+	}
+
+	cv := reflect.ValueOf(c)
+	equal := cv.MethodByName("Equal")
+	if equal.Type().In(0) != t {
+		return nil, fmt.Errorf("codec %q does not encode %s", name, t)
+	}
+	clone, encode, decode := cv.MethodByName("Clone"), cv.MethodByName("Encode"), cv.MethodByName("Decode")
+	return &reflectCodec{
+		equal: func(a, b reflect.Value) bool {
+			return equal.Call([]reflect.Value{a, b})[0].Bool()
+		},
+		clone: func(v reflect.Value) reflect.Value {
+			return clone.Call([]reflect.Value{v})[0]
+		},
+		encode: func(bw *BinaryWriter, v reflect.Value) error {
+			return callError(encode.Call([]reflect.Value{reflect.ValueOf(bw), v})[0])
+		},
+		decode: func(br *BinaryReader) (reflect.Value, error) {
+			out := decode.Call([]reflect.Value{reflect.ValueOf(br)})
+			return out[0], callError(out[1])
+		},
+	}, nil
+}
+
+// marshalerReflectCodec returns a codec calling the DeltaMarshaler and
+// DeltaUnmarshaler methods of t, if t declares Equal, Clone and Encode and
+// *t declares Decode
+func marshalerReflectCodec(t reflect.Type) (*reflectCodec, bool) {
+	pt := reflect.PointerTo(t)
+	bwType, brType := reflect.TypeOf((*BinaryWriter)(nil)), reflect.TypeOf((*BinaryReader)(nil))
+	errType := reflect.TypeOf((*error)(nil)).Elem()
+
+	equal, ok1 := t.MethodByName("Equal")
+	clone, ok2 := t.MethodByName("Clone")
+	encode, ok3 := t.MethodByName("Encode")
+	decode, ok4 := pt.MethodByName("Decode")
+	if !ok1 || !ok2 || !ok3 || !ok4 ||
+		equal.Type.NumIn() != 2 || equal.Type.In(1) != t || equal.Type.NumOut() != 1 || equal.Type.Out(0).Kind() != reflect.Bool ||
+		clone.Type.NumIn() != 1 || clone.Type.NumOut() != 1 || clone.Type.Out(0) != t ||
+		encode.Type.NumIn() != 2 || encode.Type.In(1) != bwType || encode.Type.NumOut() != 1 || encode.Type.Out(0) != errType ||
+		decode.Type.NumIn() != 2 || decode.Type.In(1) != brType || decode.Type.NumOut() != 1 || decode.Type.Out(0) != errType {
+		return nil, false
+	}
+
+	return &reflectCodec{
+		equal: func(a, b reflect.Value) bool {
+			return a.MethodByName("Equal").Call([]reflect.Value{b})[0].Bool()
+		},
+		clone: func(v reflect.Value) reflect.Value {
+			return v.MethodByName("Clone").Call(nil)[0]
+		},
+		encode: func(bw *BinaryWriter, v reflect.Value) error {
+			return callError(v.MethodByName("Encode").Call([]reflect.Value{reflect.ValueOf(bw)})[0])
+		},
+		decode: func(br *BinaryReader) (reflect.Value, error) {
+			v := reflect.New(t)
+			err := callError(v.MethodByName("Decode").Call([]reflect.Value{reflect.ValueOf(br)})[0])
+			return v.Elem(), err
+		},
+	}, true
+}
+
+// callError returns the error held by the result of a reflected call
+func callError(v reflect.Value) error {
+	if v.IsNil() {
+		return nil
+	}
+	return v.Interface().(error)
+}
+
+// structValue returns v, a struct or a non-nil pointer to one, as an
+// addressable struct value
+func structValue(v any) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.Kind() == reflect.Pointer && !rv.IsNil() && rv.Elem().Kind() == reflect.Struct:
+		return rv.Elem(), nil
+	case rv.Kind() == reflect.Struct:
+		cp := reflect.New(rv.Type()).Elem()
+		cp.Set(rv)
+		return cp, nil
+	}
+	return reflect.Value{}, fmt.Errorf("delta: %T is not a struct or non-nil pointer to a struct", v)
+}
+
+// fieldOf returns the field at index of the addressable struct v, settable
+// even when it is an unexported field tagged delta:"include"
+func fieldOf(v reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		v = v.Field(i)
+		if !v.CanSet() {
+			v = reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+		}
+	}
+	return v
+}
+
+// copyOf returns a settable copy of v
+func copyOf(v reflect.Value) reflect.Value {
+	cp := reflect.New(v.Type()).Elem()
+	cp.Set(v)
+	return cp
+}
+
+func (s *reflectStruct) newDelta() *ReflectDelta {
+	return &ReflectDelta{s: s, values: make([]any, len(s.fields))}
+}
+
+// idOf returns the identity of the addressable struct v
+func (s *reflectStruct) idOf(v reflect.Value) any {
+	return fieldOf(v, s.id).Interface()
+}
+
+// diff returns the changes that turn the addressable struct to into from
+func (s *reflectStruct) diff(from, to reflect.Value) *ReflectDelta {
+	d := s.newDelta()
+	for i, f := range s.fields {
+		d.values[i] = f.diff(fieldOf(from, f.index), fieldOf(to, f.index))
+	}
+	return d
+}
+
+// apply applies d to the addressable struct e
+func (s *reflectStruct) apply(d *ReflectDelta, e reflect.Value) {
+	for i, f := range s.fields {
+		if d.values[i] != nil {
+			f.apply(fieldOf(e, f.index), d.values[i])
+		}
+	}
+}
+
+// validate checks the changes of d to the addressable struct e like the
+// Validate method of generated deltas
+func (s *reflectStruct) validate(d *ReflectDelta, e reflect.Value) error {
+	for i, f := range s.fields {
+		if d.values[i] == nil {
+			continue
+		}
+		if err := f.validate(s.typ.Name(), fieldOf(e, f.index), d.values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate checks the change v to the field e of entity against the bounds of
+// the field and the entities it changes
+func (f *reflectField) validate(entity string, e reflect.Value, v any) error {
+	switch f.kind {
+	case kindInterface:
+		return f.validateInterface(entity, e, v.(*InterfaceDelta))
+	case kindEntitySlice:
+		index := make(map[any]int, e.Len())
+		for i := 0; i < e.Len(); i++ {
+			index[f.elem.idOf(e.Index(i))] = i
+		}
+		return f.validateEntries(entity, v.(*collectionChange), func(k reflect.Value) (reflect.Value, bool) {
+			i, ok := index[k.Interface()]
+			if !ok {
+				return reflect.Value{}, false
+			}
+			return e.Index(i), true
+		})
+	case kindEntityMap:
+		return f.validateEntries(entity, v.(*collectionChange), func(k reflect.Value) (reflect.Value, bool) {
+			if v := e.MapIndex(k); v.IsValid() {
+				return copyOf(v), true
+			}
+			return reflect.Value{}, false
+		})
+	case kindValue:
+		if f.bounds != nil {
+			return f.bounds.check(entity, f.name, v.(reflect.Value))
+		}
+	}
+	return nil
+}
+
+// validateEntries validates the deltas of the entities changed in cc against
+// the entities lookup finds, and those of added entities against zero ones,
+// like ValidateSlice and ValidateMap
+func (f *reflectField) validateEntries(entity string, cc *collectionChange, lookup func(key reflect.Value) (reflect.Value, bool)) error {
+	for _, c := range cc.changed {
+		if e, ok := lookup(c.key); ok {
+			if err := f.elem.validate(c.d, e); err != nil {
+				return nestedApplyError(entity, f.name, c.key.Interface(), err)
+			}
+		}
+	}
+	for _, a := range cc.added {
+		if err := f.elem.validate(a.d, reflect.New(f.elem.typ).Elem()); err != nil {
+			return nestedApplyError(entity, f.name, a.key.Interface(), err)
+		}
+	}
+	return nil
+}
+
+// validateInterface validates the change to an interface field like
+// ValidateInterface, also reporting registered entities that the field
+// cannot hold
+func (f *reflectField) validateInterface(entity string, e reflect.Value, id *InterfaceDelta) error {
+	if id.TypeID == 0 {
+		return nil
+	}
+	if !id.Full {
+		return ValidateInterface(entity, f.name, e.Interface(), id)
+	}
+	created, err := NewEntity(id.TypeID)
+	if err != nil {
+		return &ApplyError{Entity: entity, Field: f.name, Err: err}
+	}
+	if !reflect.TypeOf(created).AssignableTo(f.typ) {
+		return &ApplyError{Entity: entity, Field: f.name, Err: fmt.Errorf("%w: type ID %d registers %T", ErrTypeMismatch, id.TypeID, created)}
+	}
+	return validateElement(entity, f.name, nil, created, id.Delta)
+}
+
+// diff returns the change that turns to into from, or nil if they are equal
+func (f *reflectField) diff(from, to reflect.Value) any {
+	switch f.kind {
+	case kindCodec:
+		if f.codec.equal(from, to) {
+			return nil
+		}
+		return copyOf(f.codec.clone(from))
+	case kindInterface:
+		if id := DiffInterface(from.Interface(), to.Interface()); id != nil {
+			return id
+		}
+		return nil
+	case kindSet:
+		return f.diffSet(from, to)
+	case kindEntitySlice:
+		return f.diffSlice(from, to)
+	case kindEntityMap:
+		return f.diffMap(from, to)
+	}
+	if valuesEqual(from, to) {
+		return nil
+	}
+	return cloneValue(from)
+}
+
+// apply applies the change v to the settable field e
+func (f *reflectField) apply(e reflect.Value, v any) {
+	switch f.kind {
+	case kindCodec:
+		e.Set(f.codec.clone(v.(reflect.Value)))
+	case kindInterface:
+		f.applyInterface(e, v.(*InterfaceDelta))
+	case kindSet:
+		f.applySet(e, v.(*setChange))
+	case kindEntitySlice:
+		f.applySlice(e, v.(*collectionChange))
+	case kindEntityMap:
+		f.applyMap(e, v.(*collectionChange))
+	default:
+		e.Set(cloneValue(v.(reflect.Value)))
+	}
+}
+
+// write serializes the change v like the generated delta
+func (f *reflectField) write(bw *BinaryWriter, v any) error {
+	switch f.kind {
+	case kindCodec:
+		return f.codec.encode(bw, v.(reflect.Value))
+	case kindInterface:
+		return WriteInterface(bw, v.(*InterfaceDelta))
+	case kindSet:
+		sc := v.(*setChange)
+		if err := writeValues(bw, sc.added); err != nil {
+			return err
+		}
+		return writeValues(bw, sc.removed)
+	case kindEntitySlice, kindEntityMap:
+		return writeCollectionChange(bw, v.(*collectionChange))
+	}
+	return writeValue(bw, v.(reflect.Value))
+}
+
+// read deserializes a change written by write or by the generated delta
+func (f *reflectField) read(br *BinaryReader) (any, error) {
+	switch f.kind {
+	case kindCodec:
+		v, err := f.codec.decode(br)
+		if err != nil {
+			return nil, err
+		}
+		return copyOf(v), nil
+	case kindInterface:
+		return ReadInterface(br)
+	case kindSet:
+		sc := &setChange{}
+		var err error
+		if sc.added, err = readValues(br, f.typ.Key()); err != nil {
+			return nil, err
+		}
+		if sc.removed, err = readValues(br, f.typ.Key()); err != nil {
+			return nil, err
+		}
+		return sc, nil
+	case kindEntitySlice, kindEntityMap:
+		return f.readCollectionChange(br)
+	}
+	return readValue(br, f.typ)
+}
+
+// applyInterface applies id like ApplyInterface
+func (f *reflectField) applyInterface(e reflect.Value, id *InterfaceDelta) {
+	if id.TypeID == 0 {
+		e.Set(reflect.Zero(f.typ))
+		return
+	}
+	if !id.Full {
+		if entity, ok := e.Interface().(Entity); ok {
+			id.Delta.ApplyTo(entity)
+		}
+		return
+	}
+
+	entity, err := NewEntity(id.TypeID)
+	if err != nil {
+		return
+	}
+	id.Delta.ApplyTo(entity)
+	if v := reflect.ValueOf(entity); v.Type().AssignableTo(f.typ) {
+		e.Set(v)
+	}
+}
+
+// isMember reports whether k is in the set m, like isMember
+func (f *reflectField) isMember(m, k reflect.Value) bool {
+	v := m.MapIndex(k)
+	return v.IsValid() && v.Interface() == f.member.Interface()
+}
+
+// diffSet returns the keys added to and removed from a set like DiffSet
+func (f *reflectField) diffSet(from, to reflect.Value) any {
+	sc := &setChange{}
+	iter := from.MapRange()
+	for iter.Next() {
+		if iter.Value().Interface() == f.member.Interface() && !f.isMember(to, iter.Key()) {
+			sc.added = append(sc.added, copyOf(iter.Key()))
+		}
+	}
+	iter = to.MapRange()
+	for iter.Next() {
+		if iter.Value().Interface() == f.member.Interface() && !f.isMember(from, iter.Key()) {
+			sc.removed = append(sc.removed, copyOf(iter.Key()))
+		}
+	}
+	if len(sc.added) == 0 && len(sc.removed) == 0 {
+		return nil
+	}
+	sortValues(sc.added)
+	sortValues(sc.removed)
+	return sc
+}
+
+// applySet applies sc to the set m like ApplySet
+func (f *reflectField) applySet(m reflect.Value, sc *setChange) {
+	for _, k := range sc.removed {
+		m.SetMapIndex(k, reflect.Value{})
+	}
+	if len(sc.added) > 0 && m.IsNil() {
+		m.Set(reflect.MakeMapWithSize(f.typ, len(sc.added)))
+	}
+	for _, k := range sc.added {
+		m.SetMapIndex(k, f.member)
+	}
+}
+
+// diffElement records e as added when it has no counterpart o, or its nested
+// delta from o when it changed, like diffElement
+func (f *reflectField) diffElement(cc *collectionChange, key, e, o reflect.Value) {
+	if !o.IsValid() {
+		zero := reflect.New(f.elem.typ).Elem()
+		cc.added = append(cc.added, collectionEntry{key: key, d: f.elem.diff(e, zero)})
+		return
+	}
+	if d := f.elem.diff(e, o); !d.IsEmpty() {
+		cc.changed = append(cc.changed, collectionEntry{key: key, d: d})
+	}
+}
+
+// diffSlice returns the changes to a slice of entities like DiffSlice
+func (f *reflectField) diffSlice(from, to reflect.Value) any {
+	old := make(map[any]reflect.Value, to.Len())
+	for i := 0; i < to.Len(); i++ {
+		old[f.elem.idOf(to.Index(i))] = to.Index(i)
+	}
+
+	cc := &collectionChange{}
+	sameOrder := from.Len() == to.Len()
+	for i := 0; i < from.Len(); i++ {
+		e := from.Index(i)
+		id := f.elem.idOf(e)
+		if sameOrder && f.elem.idOf(to.Index(i)) != id {
+			sameOrder = false
+		}
+		f.diffElement(cc, reflect.ValueOf(id), e, old[id])
+		delete(old, id)
+	}
+	for i := 0; i < to.Len(); i++ {
+		if id := f.elem.idOf(to.Index(i)); old[id].IsValid() {
+			cc.removed = append(cc.removed, reflect.ValueOf(id))
+		}
+	}
+
+	if !sameOrder {
+		cc.hasOrder = true
+		cc.order = make([]reflect.Value, from.Len())
+		for i := 0; i < from.Len(); i++ {
+			cc.order[i] = reflect.ValueOf(f.elem.idOf(from.Index(i)))
+		}
+	}
+	if cc.isEmpty() {
+		return nil
+	}
+	return cc
+}
+
+// applySlice applies cc to the slice of entities s like ApplySlice
+func (f *reflectField) applySlice(s reflect.Value, cc *collectionChange) {
+	index := make(map[any]int, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		index[f.elem.idOf(s.Index(i))] = i
+	}
+	for _, c := range cc.changed {
+		if i, ok := index[c.key.Interface()]; ok {
+			f.elem.apply(c.d, s.Index(i))
+		}
+	}
+	if !cc.hasOrder {
+		return
+	}
+
+	added := make(map[any]*ReflectDelta, len(cc.added))
+	for _, a := range cc.added {
+		added[a.key.Interface()] = a.d
+	}
+	result := reflect.MakeSlice(f.typ, 0, len(cc.order))
+	for _, id := range cc.order {
+		if d, ok := added[id.Interface()]; ok {
+			e := reflect.New(f.elem.typ).Elem()
+			f.elem.apply(d, e)
+			result = reflect.Append(result, e)
+		} else if i, ok := index[id.Interface()]; ok {
+			result = reflect.Append(result, s.Index(i))
+		}
+	}
+	s.Set(result)
+}
+
+// diffMap returns the changes to a map of entities like DiffMap
+func (f *reflectField) diffMap(from, to reflect.Value) any {
+	cc := &collectionChange{}
+	iter := from.MapRange()
+	for iter.Next() {
+		k := copyOf(iter.Key())
+		e := copyOf(iter.Value())
+		var o reflect.Value
+		if v := to.MapIndex(k); v.IsValid() {
+			o = copyOf(v)
+		}
+		f.diffElement(cc, k, e, o)
+	}
+	iter = to.MapRange()
+	for iter.Next() {
+		if !from.MapIndex(iter.Key()).IsValid() {
+			cc.removed = append(cc.removed, copyOf(iter.Key()))
+		}
+	}
+	sortValues(cc.removed)
+	if cc.isEmpty() {
+		return nil
+	}
+	return cc
+}
+
+// applyMap applies cc to the map of entities m like ApplyMap
+func (f *reflectField) applyMap(m reflect.Value, cc *collectionChange) {
+	for _, k := range cc.removed {
+		m.SetMapIndex(k, reflect.Value{})
+	}
+	for _, c := range cc.changed {
+		if v := m.MapIndex(c.key); v.IsValid() {
+			e := copyOf(v)
+			f.elem.apply(c.d, e)
+			m.SetMapIndex(c.key, e)
+		}
+	}
+	if len(cc.added) > 0 && m.IsNil() {
+		m.Set(reflect.MakeMapWithSize(f.typ, len(cc.added)))
+	}
+	for _, a := range cc.added {
+		e := reflect.New(f.elem.typ).Elem()
+		f.elem.apply(a.d, e)
+		m.SetMapIndex(a.key, e)
+	}
+}
+
+func (cc *collectionChange) isEmpty() bool {
+	return len(cc.added) == 0 && len(cc.changed) == 0 && len(cc.removed) == 0 && !cc.hasOrder
+}
+
+// writeCollectionChange writes cc like WriteCollection, with added and changed
+// entries in ascending key order
+func writeCollectionChange(bw *BinaryWriter, cc *collectionChange) error {
+	for _, entries := range [][]collectionEntry{cc.added, cc.changed} {
+		if err := bw.WriteVarUint32(uint32(len(entries))); err != nil {
+			return err
+		}
+		entries = slices.Clone(entries)
+		slices.SortFunc(entries, func(a, b collectionEntry) int {
+			return compareValues(a.key, b.key)
+		})
+		for _, e := range entries {
+			if err := writeValue(bw, e.key); err != nil {
+				return err
+			}
+			if err := e.d.Serialize(bw); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := writeValues(bw, cc.removed); err != nil {
+		return err
+	}
+	if err := bw.WriteBool(cc.hasOrder); err != nil {
+		return err
+	}
+	if cc.hasOrder {
+		return writeValues(bw, cc.order)
+	}
+	return nil
+}
+
+// readCollectionChange reads a collection written by WriteCollection
+func (f *reflectField) readCollectionChange(br *BinaryReader) (*collectionChange, error) {
+	keyType := f.typ.Key
+	if f.kind == kindEntitySlice {
+		keyType = func() reflect.Type { return f.elem.typ.FieldByIndex(f.elem.id).Type }
+	}
+
+	cc := &collectionChange{}
+	for _, entries := range []*[]collectionEntry{&cc.added, &cc.changed} {
+		length, err := br.ReadVarUint32()
+		if err != nil {
+			return nil, err
+		}
+		for i := uint32(0); i < length; i++ {
+			k, err := readValue(br, keyType())
+			if err != nil {
+				return nil, err
+			}
+			d := f.elem.newDelta()
+			if err := d.Deserialize(br); err != nil {
+				return nil, keyDecodeError(k.Interface(), err)
+			}
+			*entries = append(*entries, collectionEntry{key: k, d: d})
+		}
+	}
+
+	var err error
+	if cc.removed, err = readValues(br, keyType()); err != nil {
+		return nil, err
+	}
+	if cc.hasOrder, err = br.ReadBool(); err != nil || !cc.hasOrder {
+		return cc, err
+	}
+	if cc.order, err = readValues(br, keyType()); err != nil {
+		return nil, err
+	}
+	return cc, nil
+}
+
+// sortValues sorts map keys into the order SortedKeys gives
+func sortValues(keys []reflect.Value) {
+	slices.SortFunc(keys, compareValues)
+}
+
+// writeValues writes a varint count followed by each value
+func writeValues(bw *BinaryWriter, values []reflect.Value) error {
+	if err := bw.WriteVarUint32(uint32(len(values))); err != nil {
+		return err
+	}
+	for _, v := range values {
+		if err := writeValue(bw, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readValues reads values written by writeValues
+func readValues(br *BinaryReader, t reflect.Type) ([]reflect.Value, error) {
+	length, err := br.ReadVarUint32()
+	if err != nil || length == 0 {
+		return nil, err
+	}
+	values := make([]reflect.Value, length)
+	for i := range values {
+		if values[i], err = readValue(br, t); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// writeValue writes v with the BinaryWriter method for its kind, or as a slice
+// or map of such values
+func writeValue(bw *BinaryWriter, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Bool:
+		return bw.WriteBool(v.Bool())
+	case reflect.Int8:
+		return bw.WriteInt8(int8(v.Int()))
+	case reflect.Int16:
+		return bw.WriteInt16(int16(v.Int()))
+	case reflect.Int32:
+		return bw.WriteInt32(int32(v.Int()))
+	case reflect.Int64:
+		return bw.WriteInt64(v.Int())
+	case reflect.Uint8:
+		return bw.WriteUint8(uint8(v.Uint()))
+	case reflect.Uint16:
+		return bw.WriteUint16(uint16(v.Uint()))
+	case reflect.Uint32:
+		return bw.WriteUint32(uint32(v.Uint()))
+	case reflect.Uint64:
+		return bw.WriteUint64(v.Uint())
+	case reflect.Float32:
+		return bw.WriteFloat32(float32(v.Float()))
+	case reflect.Float64:
+		return bw.WriteFloat64(v.Float())
+	case reflect.String:
+		return bw.WriteString(v.String())
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return bw.WriteBytes(v.Bytes())
+		}
+		if err := bw.WriteVarUint32(uint32(v.Len())); err != nil {
+			return err
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := writeValue(bw, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if err := bw.WriteVarUint32(uint32(v.Len())); err != nil {
+			return err
+		}
+		keys := v.MapKeys()
+		sortValues(keys)
+		for _, k := range keys {
+			if err := writeValue(bw, k); err != nil {
+				return err
+			}
+			if err := writeValue(bw, v.MapIndex(k)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("delta: cannot encode %s", v.Type())
+}
+
+// readValue reads a value of type t written by writeValue
+func readValue(br *BinaryReader, t reflect.Type) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	var err error
+	switch t.Kind() {
+	case reflect.Bool:
+		var b bool
+		b, err = br.ReadBool()
+		v.SetBool(b)
+	case reflect.Int8:
+		var i int8
+		i, err = br.ReadInt8()
+		v.SetInt(int64(i))
+	case reflect.Int16:
+		var i int16
+		i, err = br.ReadInt16()
+		v.SetInt(int64(i))
+	case reflect.Int32:
+		var i int32
+		i, err = br.ReadInt32()
+		v.SetInt(int64(i))
+	case reflect.Int64:
+		var i int64
+		i, err = br.ReadInt64()
+		v.SetInt(i)
+	case reflect.Uint8:
+		var u uint8
+		u, err = br.ReadUint8()
+		v.SetUint(uint64(u))
+	case reflect.Uint16:
+		var u uint16
+		u, err = br.ReadUint16()
+		v.SetUint(uint64(u))
+	case reflect.Uint32:
+		var u uint32
+		u, err = br.ReadUint32()
+		v.SetUint(uint64(u))
+	case reflect.Uint64:
+		var u uint64
+		u, err = br.ReadUint64()
+		v.SetUint(u)
+	case reflect.Float32:
+		var f float32
+		f, err = br.ReadFloat32()
+		v.SetFloat(float64(f))
+	case reflect.Float64:
+		var f float64
+		f, err = br.ReadFloat64()
+		v.SetFloat(f)
+	case reflect.String:
+		var s string
+		s, err = br.ReadString()
+		v.SetString(s)
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			var b []byte
+			b, err = br.ReadBytes()
+			v.SetBytes(b)
+			break
+		}
+		var length uint32
+		if length, err = br.ReadVarUint32(); err != nil {
+			break
+		}
+		v.Set(reflect.MakeSlice(t, int(length), int(length)))
+		for i := 0; i < int(length); i++ {
+			var elem reflect.Value
+			if elem, err = readValue(br, t.Elem()); err != nil {
+				break
+			}
+			v.Index(i).Set(elem)
+		}
+	case reflect.Map:
+		var length uint32
+		if length, err = br.ReadVarUint32(); err != nil {
+			break
+		}
+		v.Set(reflect.MakeMap(t))
+		for i := uint32(0); i < length; i++ {
+			var key, elem reflect.Value
+			if key, err = readValue(br, t.Key()); err != nil {
+				break
+			}
+			if elem, err = readValue(br, t.Elem()); err != nil {
+				break
+			}
+			v.SetMapIndex(key, elem)
+		}
+	default:
+		err = fmt.Errorf("delta: cannot decode %s", t)
+	}
+	return v, err
+}
+
+// valuesEqual compares values of a kindValue field like the generated code:
+// collections by length and elements, so nil and empty are equal
+func valuesEqual(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !valuesEqual(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+		iter := a.MapRange()
+		for iter.Next() {
+			bv := b.MapIndex(iter.Key())
+			if !bv.IsValid() || !valuesEqual(iter.Value(), bv) {
+				return false
+			}
+		}
+		return true
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float()
+	}
+	return a.Interface() == b.Interface()
+}
+
+// cloneValue returns a deep copy of a kindValue field, preserving nil
+func cloneValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		cp := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			cp.Index(i).Set(cloneValue(v.Index(i)))
+		}
+		return cp
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		cp := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			cp.SetMapIndex(iter.Key(), cloneValue(iter.Value()))
+		}
+		return cp
+	}
+	return copyOf(v)
+}
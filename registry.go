@@ -0,0 +1,75 @@
+package delta
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// registeredType holds the constructors of an entity type registered under a
+// type ID
+type registeredType struct {
+	newEntity func() Entity
+	newDelta  func() Delta
+}
+
+var registry = struct {
+	sync.RWMutex
+	byID   map[uint32]registeredType
+	byType map[reflect.Type]uint32
+}{
+	byID:   make(map[uint32]registeredType),
+	byType: make(map[reflect.Type]uint32),
+}
+
+// RegisterType registers an entity type under id, so it can be identified on
+// the wire and constructed when decoding. Generated code registers entities
+// annotated with a type ID, e.g. "// delta:entity typeid=3". The ID 0 is
+// reserved for nil values, and registering an ID or type twice panics.
+func RegisterType(id uint32, newEntity func() Entity, newDelta func() Delta) {
+	if id == 0 {
+		panic("delta: type ID 0 is reserved")
+	}
+	typ := reflect.TypeOf(newEntity())
+
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.byID[id]; ok {
+		panic(fmt.Sprintf("delta: type ID %d registered twice", id))
+	}
+	if other, ok := registry.byType[typ]; ok {
+		panic(fmt.Sprintf("delta: type %s already registered with ID %d", typ, other))
+	}
+	registry.byID[id] = registeredType{newEntity: newEntity, newDelta: newDelta}
+	registry.byType[typ] = id
+}
+
+// TypeIDOf returns the type ID e's concrete type was registered under
+func TypeIDOf(e Entity) (uint32, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	id, ok := registry.byType[reflect.TypeOf(e)]
+	return id, ok
+}
+
+// NewEntity returns a new zero entity of the type registered under id
+func NewEntity(id uint32) (Entity, error) {
+	registry.RLock()
+	t, ok := registry.byID[id]
+	registry.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("delta: unknown type ID %d", id)
+	}
+	return t.newEntity(), nil
+}
+
+// NewDelta returns a new empty delta for the type registered under id
+func NewDelta(id uint32) (Delta, error) {
+	registry.RLock()
+	t, ok := registry.byID[id]
+	registry.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("delta: unknown type ID %d", id)
+	}
+	return t.newDelta(), nil
+}
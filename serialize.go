@@ -1,10 +1,15 @@
 package delta
 
 import (
+	"cmp"
 	"encoding/binary"
-	"errors"
+	"fmt"
 	"io"
 	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"unsafe"
 )
 
 type BinaryWriter struct {
@@ -15,6 +20,12 @@ func NewBinaryWriter(w io.Writer) *BinaryWriter {
 	return &BinaryWriter{w: w}
 }
 
+// Write writes p to the underlying writer, so nested deltas can serialize
+// through the same BinaryWriter
+func (bw *BinaryWriter) Write(p []byte) (int, error) {
+	return bw.w.Write(p)
+}
+
 func (bw *BinaryWriter) WriteByte(b byte) error {
 	_, err := bw.w.Write([]byte{b})
 	return err
@@ -120,17 +131,77 @@ func (bw *BinaryWriter) WriteVarUint32(v uint32) error {
 	return bw.WriteByte(byte(v))
 }
 
+// BinaryReader reads the values written by BinaryWriter, tracking how many
+// bytes it has read. Its read errors are *DecodeError values carrying the
+// offset of the value that could not be read.
 type BinaryReader struct {
-	r io.Reader
+	r      io.Reader
+	offset int64
+	mode   DecodeMode
 }
 
+// NewBinaryReader returns a reader of r. When r is already a BinaryReader it
+// is returned as is, so nested deltas report offsets from the same start.
 func NewBinaryReader(r io.Reader) *BinaryReader {
+	if br, ok := r.(*BinaryReader); ok {
+		return br
+	}
 	return &BinaryReader{r: r}
 }
 
+// Read reads from the underlying reader, so nested deltas can deserialize
+// through the same BinaryReader
+func (br *BinaryReader) Read(p []byte) (int, error) {
+	n, err := br.r.Read(p)
+	br.offset += int64(n)
+	return n, err
+}
+
+// Offset returns the number of bytes read so far
+func (br *BinaryReader) Offset() int64 {
+	return br.offset
+}
+
+// SetMode sets how deltas read through br treat unknown presence bits. The
+// default is IgnoreTrailing.
+func (br *BinaryReader) SetMode(mode DecodeMode) {
+	br.mode = mode
+}
+
+// Mode returns the decode mode of br
+func (br *BinaryReader) Mode() DecodeMode {
+	return br.mode
+}
+
+// ReadFieldMask reads the presence mask of a delta of an entity with the given
+// number of fields. Bits set beyond them are an error in Strict mode, and in
+// IgnoreTrailing mode unless the mask starts the input.
+func (br *BinaryReader) ReadFieldMask(fields int) (uint64, error) {
+	start := br.offset
+	mask, err := br.ReadUint64()
+	if err != nil || fields >= 64 || (br.mode == IgnoreTrailing && start == 0) {
+		return mask, err
+	}
+	if unknown := mask &^ (1<<fields - 1); unknown != 0 {
+		return mask, &DecodeError{Offset: start, Err: fmt.Errorf("%w: %#x", ErrUnknownFields, unknown)}
+	}
+	return mask, nil
+}
+
+// readFull fills buf, reporting a failure at the offset where it started
+func (br *BinaryReader) readFull(buf []byte) error {
+	start := br.offset
+	n, err := io.ReadFull(br.r, buf)
+	br.offset += int64(n)
+	if err != nil {
+		return &DecodeError{Offset: start, Err: err}
+	}
+	return nil
+}
+
 func (br *BinaryReader) ReadByte() (byte, error) {
 	buf := make([]byte, 1)
-	_, err := io.ReadFull(br.r, buf)
+	err := br.readFull(buf)
 	return buf[0], err
 }
 
@@ -146,7 +217,7 @@ func (br *BinaryReader) ReadInt8() (int8, error) {
 
 func (br *BinaryReader) ReadInt16() (int16, error) {
 	buf := make([]byte, 2)
-	_, err := io.ReadFull(br.r, buf)
+	err := br.readFull(buf)
 	if err != nil {
 		return 0, err
 	}
@@ -155,7 +226,7 @@ func (br *BinaryReader) ReadInt16() (int16, error) {
 
 func (br *BinaryReader) ReadInt32() (int32, error) {
 	buf := make([]byte, 4)
-	_, err := io.ReadFull(br.r, buf)
+	err := br.readFull(buf)
 	if err != nil {
 		return 0, err
 	}
@@ -164,7 +235,7 @@ func (br *BinaryReader) ReadInt32() (int32, error) {
 
 func (br *BinaryReader) ReadInt64() (int64, error) {
 	buf := make([]byte, 8)
-	_, err := io.ReadFull(br.r, buf)
+	err := br.readFull(buf)
 	if err != nil {
 		return 0, err
 	}
@@ -177,7 +248,7 @@ func (br *BinaryReader) ReadUint8() (uint8, error) {
 
 func (br *BinaryReader) ReadUint16() (uint16, error) {
 	buf := make([]byte, 2)
-	_, err := io.ReadFull(br.r, buf)
+	err := br.readFull(buf)
 	if err != nil {
 		return 0, err
 	}
@@ -186,7 +257,7 @@ func (br *BinaryReader) ReadUint16() (uint16, error) {
 
 func (br *BinaryReader) ReadUint32() (uint32, error) {
 	buf := make([]byte, 4)
-	_, err := io.ReadFull(br.r, buf)
+	err := br.readFull(buf)
 	if err != nil {
 		return 0, err
 	}
@@ -195,7 +266,7 @@ func (br *BinaryReader) ReadUint32() (uint32, error) {
 
 func (br *BinaryReader) ReadUint64() (uint64, error) {
 	buf := make([]byte, 8)
-	_, err := io.ReadFull(br.r, buf)
+	err := br.readFull(buf)
 	if err != nil {
 		return 0, err
 	}
@@ -204,7 +275,7 @@ func (br *BinaryReader) ReadUint64() (uint64, error) {
 
 func (br *BinaryReader) ReadFloat32() (float32, error) {
 	buf := make([]byte, 4)
-	_, err := io.ReadFull(br.r, buf)
+	err := br.readFull(buf)
 	if err != nil {
 		return 0, err
 	}
@@ -213,7 +284,7 @@ func (br *BinaryReader) ReadFloat32() (float32, error) {
 
 func (br *BinaryReader) ReadFloat64() (float64, error) {
 	buf := make([]byte, 8)
-	_, err := io.ReadFull(br.r, buf)
+	err := br.readFull(buf)
 	if err != nil {
 		return 0, err
 	}
@@ -226,7 +297,7 @@ func (br *BinaryReader) ReadString() (string, error) {
 		return "", err
 	}
 	buf := make([]byte, length)
-	_, err = io.ReadFull(br.r, buf)
+	err = br.readFull(buf)
 	return string(buf), err
 }
 
@@ -236,11 +307,12 @@ func (br *BinaryReader) ReadBytes() ([]byte, error) {
 		return nil, err
 	}
 	buf := make([]byte, length)
-	_, err = io.ReadFull(br.r, buf)
+	err = br.readFull(buf)
 	return buf, err
 }
 
 func (br *BinaryReader) ReadVarUint32() (uint32, error) {
+	start := br.offset
 	var result uint32
 	var shift uint
 	for {
@@ -254,8 +326,249 @@ func (br *BinaryReader) ReadVarUint32() (uint32, error) {
 		}
 		shift += 7
 		if shift >= 32 {
-			return 0, errors.New("varint overflow")
+			return 0, &DecodeError{Offset: start, Err: ErrVarintOverflow}
 		}
 	}
 	return result, nil
 }
+
+// WriteSlice writes the length of s as a varint followed by each element
+// written with writeElem, the same layout generated code uses for slice fields
+func WriteSlice[T any](bw *BinaryWriter, s []T, writeElem func(T) error) error {
+	if err := bw.WriteVarUint32(uint32(len(s))); err != nil {
+		return err
+	}
+	for _, v := range s {
+		if err := writeElem(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadSlice reads a slice written by WriteSlice
+func ReadSlice[T any](br *BinaryReader, readElem func() (T, error)) ([]T, error) {
+	length, err := br.ReadVarUint32()
+	if err != nil {
+		return nil, err
+	}
+	s := make([]T, length)
+	for i := range s {
+		if s[i], err = readElem(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// WriteMap writes the length of m as a varint followed by each key and value,
+// the same layout generated code uses for map fields
+func WriteMap[K comparable, V any](bw *BinaryWriter, m map[K]V, writeKey func(K) error, writeValue func(V) error) error {
+	if err := bw.WriteVarUint32(uint32(len(m))); err != nil {
+		return err
+	}
+	for _, k := range SortedKeys(m) {
+		if err := writeKey(k); err != nil {
+			return err
+		}
+		if err := writeValue(m[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadMap reads a map written by WriteMap
+func ReadMap[K comparable, V any](br *BinaryReader, readKey func() (K, error), readValue func() (V, error)) (map[K]V, error) {
+	length, err := br.ReadVarUint32()
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[K]V)
+	for i := uint32(0); i < length; i++ {
+		k, err := readKey()
+		if err != nil {
+			return nil, err
+		}
+		v, err := readValue()
+		if err != nil {
+			return nil, err
+		}
+		m[k] = v
+	}
+	return m, nil
+}
+
+// SortedKeys returns the keys of m in ascending order. Maps are written in
+// this order, so a delta always serializes to the same bytes.
+func SortedKeys[K comparable, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sortKeys(keys)
+	return keys
+}
+
+// sortKeys sorts map keys collected in iteration order into ascending order.
+// Keys of a primitive underlying type are sorted as that type with cmp.Compare,
+// bools with false first; only keys of other kinds are compared by reflection.
+func sortKeys[K comparable](keys []K) {
+	if len(keys) < 2 {
+		return
+	}
+	switch reflect.TypeFor[K]().Kind() {
+	case reflect.Bool:
+		slices.SortFunc(underlying[bool](keys), compareBools)
+	case reflect.Int:
+		slices.Sort(underlying[int](keys))
+	case reflect.Int8:
+		slices.Sort(underlying[int8](keys))
+	case reflect.Int16:
+		slices.Sort(underlying[int16](keys))
+	case reflect.Int32:
+		slices.Sort(underlying[int32](keys))
+	case reflect.Int64:
+		slices.Sort(underlying[int64](keys))
+	case reflect.Uint:
+		slices.Sort(underlying[uint](keys))
+	case reflect.Uint8:
+		slices.Sort(underlying[uint8](keys))
+	case reflect.Uint16:
+		slices.Sort(underlying[uint16](keys))
+	case reflect.Uint32:
+		slices.Sort(underlying[uint32](keys))
+	case reflect.Uint64:
+		slices.Sort(underlying[uint64](keys))
+	case reflect.Uintptr:
+		slices.Sort(underlying[uintptr](keys))
+	case reflect.Float32:
+		slices.Sort(underlying[float32](keys))
+	case reflect.Float64:
+		slices.Sort(underlying[float64](keys))
+	case reflect.String:
+		slices.Sort(underlying[string](keys))
+	default:
+		slices.SortFunc(keys, func(a, b K) int {
+			return compareValues(reflect.ValueOf(a), reflect.ValueOf(b))
+		})
+	}
+}
+
+// underlying returns keys as a slice of their underlying type U, sharing its
+// elements, so that sorting it sorts keys
+func underlying[U, K any](keys []K) []U {
+	return unsafe.Slice((*U)(unsafe.Pointer(unsafe.SliceData(keys))), len(keys))
+}
+
+// compareBools orders false before true
+func compareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case b:
+		return -1
+	}
+	return 1
+}
+
+// compareValues orders map keys by their underlying numeric, string or bool
+// value, falling back to their printed form for other kinds. The reflective
+// codec sorts keys with it, as does sortKeys for keys of other kinds.
+func compareValues(a, b reflect.Value) int {
+	if !a.IsValid() || !b.IsValid() || a.Type() != b.Type() {
+		return strings.Compare(printKey(a), printKey(b))
+	}
+	switch a.Kind() {
+	case reflect.Bool:
+		return compareBools(a.Bool(), b.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(a.Float(), b.Float())
+	case reflect.String:
+		return strings.Compare(a.String(), b.String())
+	}
+	return strings.Compare(printKey(a), printKey(b))
+}
+
+// printKey returns the type and value of a map key, or of a nil interface key
+func printKey(v reflect.Value) string {
+	if !v.IsValid() {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%s %#v", v.Type(), v.Interface())
+}
+
+// Primitive is the constraint satisfied by the types BinaryWriter encodes
+// directly. Generic entities constrain their type parameters with it, or with
+// a union of some of these types.
+type Primitive interface {
+	bool | int8 | int16 | int32 | int64 | uint8 | uint16 | uint32 | uint64 | float32 | float64 | string
+}
+
+// WriteValue writes v with the BinaryWriter method for its type, so generic
+// entities encode type parameters exactly like the concrete types
+func WriteValue[T Primitive](bw *BinaryWriter, v T) error {
+	switch x := any(v).(type) {
+	case bool:
+		return bw.WriteBool(x)
+	case int8:
+		return bw.WriteInt8(x)
+	case int16:
+		return bw.WriteInt16(x)
+	case int32:
+		return bw.WriteInt32(x)
+	case int64:
+		return bw.WriteInt64(x)
+	case uint8:
+		return bw.WriteUint8(x)
+	case uint16:
+		return bw.WriteUint16(x)
+	case uint32:
+		return bw.WriteUint32(x)
+	case uint64:
+		return bw.WriteUint64(x)
+	case float32:
+		return bw.WriteFloat32(x)
+	case float64:
+		return bw.WriteFloat64(x)
+	default:
+		return bw.WriteString(any(v).(string))
+	}
+}
+
+// ReadValue reads a value written by WriteValue
+func ReadValue[T Primitive](br *BinaryReader) (T, error) {
+	var v T
+	var err error
+	switch p := any(&v).(type) {
+	case *bool:
+		*p, err = br.ReadBool()
+	case *int8:
+		*p, err = br.ReadInt8()
+	case *int16:
+		*p, err = br.ReadInt16()
+	case *int32:
+		*p, err = br.ReadInt32()
+	case *int64:
+		*p, err = br.ReadInt64()
+	case *uint8:
+		*p, err = br.ReadUint8()
+	case *uint16:
+		*p, err = br.ReadUint16()
+	case *uint32:
+		*p, err = br.ReadUint32()
+	case *uint64:
+		*p, err = br.ReadUint64()
+	case *float32:
+		*p, err = br.ReadFloat32()
+	case *float64:
+		*p, err = br.ReadFloat64()
+	case *string:
+		*p, err = br.ReadString()
+	}
+	return v, err
+}
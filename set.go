@@ -0,0 +1,79 @@
+package delta
+
+// SetDelta describes the keys added to and removed from a set-shaped map, such
+// as map[K]struct{} or map[K]bool, so a set is never resent in full
+type SetDelta[K comparable] struct {
+	Added   []K
+	Removed []K
+}
+
+// DiffSet returns the changes that turn the set to into from, or nil if there
+// are none. A key is a member of the set if its value equals member, so false
+// entries of a map[K]bool are treated as absent. Keys are listed in ascending
+// order.
+func DiffSet[K comparable, V comparable](from, to map[K]V, member V) *SetDelta[K] {
+	sd := &SetDelta[K]{}
+	for k, v := range from {
+		if v == member && !isMember(to, k, member) {
+			sd.Added = append(sd.Added, k)
+		}
+	}
+	for k, v := range to {
+		if v == member && !isMember(from, k, member) {
+			sd.Removed = append(sd.Removed, k)
+		}
+	}
+	if len(sd.Added) == 0 && len(sd.Removed) == 0 {
+		return nil
+	}
+	sortKeys(sd.Added)
+	sortKeys(sd.Removed)
+	return sd
+}
+
+// isMember reports whether k is present in m with the member value. The
+// presence check matters for map[K]struct{}, where the zero value is a member.
+func isMember[K comparable, V comparable](m map[K]V, k K, member V) bool {
+	v, ok := m[k]
+	return ok && v == member
+}
+
+// ApplySet applies the changes in sd to the set m, allocating the map if keys
+// are added to a nil map
+func ApplySet[K comparable, V comparable](m *map[K]V, sd *SetDelta[K], member V) {
+	if sd == nil {
+		return
+	}
+
+	for _, k := range sd.Removed {
+		delete(*m, k)
+	}
+	if len(sd.Added) > 0 && *m == nil {
+		*m = make(map[K]V, len(sd.Added))
+	}
+	for _, k := range sd.Added {
+		(*m)[k] = member
+	}
+}
+
+// WriteSet writes the added and removed keys of sd, each preceded by a varint
+// count
+func WriteSet[K comparable](bw *BinaryWriter, sd *SetDelta[K], writeKey func(K) error) error {
+	if err := writeKeys(bw, sd.Added, writeKey); err != nil {
+		return err
+	}
+	return writeKeys(bw, sd.Removed, writeKey)
+}
+
+// ReadSet reads a set delta written by WriteSet
+func ReadSet[K comparable](br *BinaryReader, readKey func() (K, error)) (*SetDelta[K], error) {
+	added, err := readKeys(br, readKey)
+	if err != nil {
+		return nil, err
+	}
+	removed, err := readKeys(br, readKey)
+	if err != nil {
+		return nil, err
+	}
+	return &SetDelta[K]{Added: added, Removed: removed}, nil
+}
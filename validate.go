@@ -0,0 +1,190 @@
+package delta
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrTypeMismatch is returned when a delta is applied to an entity of
+	// another type
+	ErrTypeMismatch = errors.New("delta type does not match the entity")
+
+	// ErrIDMismatch is returned by Route when a full state creates an entity
+	// with an ID other than the one in its envelope
+	ErrIDMismatch = errors.New("delta is for another entity")
+
+	// ErrOutOfRange is returned when a delta would set a field outside the
+	// bounds given by its min, max or maxlen tags
+	ErrOutOfRange = errors.New("value out of range")
+)
+
+// ValidatingDelta is implemented by generated deltas, which can check that
+// they apply cleanly to an entity before changing it
+type ValidatingDelta interface {
+	ElementDelta
+
+	// Validate returns an *ApplyError if the delta cannot be applied to e,
+	// without changing e
+	Validate(e Entity) error
+}
+
+// CheckedEntity is implemented by generated entities, which can report deltas
+// they cannot apply instead of ignoring them
+type CheckedEntity interface {
+	Entity
+	ApplyDeltaChecked(d Delta) error
+}
+
+// ApplyError is returned when a delta cannot be applied to an entity. Err
+// wraps ErrTypeMismatch, ErrOutOfRange, or the error looking up the type of an
+// interface field.
+type ApplyError struct {
+	Entity string // type of the entity the delta was applied to
+	Field  string // path of the offending field, such as Players[3].Health, or empty
+	Err    error
+}
+
+func (e *ApplyError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("delta: %s: %v", e.Entity, e.Err)
+	}
+	return fmt.Sprintf("delta: %s.%s: %v", e.Entity, e.Field, e.Err)
+}
+
+func (e *ApplyError) Unwrap() error {
+	return e.Err
+}
+
+// TypeMismatch returns the error for an entity of type entity given a delta,
+// or its delta given an entity, of another type
+func TypeMismatch(entity string, other any) error {
+	return &ApplyError{Entity: entity, Err: fmt.Errorf("%w: got %T", ErrTypeMismatch, other)}
+}
+
+// CheckMin returns an error wrapping ErrOutOfRange if v is less than min
+func CheckMin[T cmp.Ordered](entity, field string, v, min T) error {
+	if v >= min {
+		return nil
+	}
+	return &ApplyError{Entity: entity, Field: field, Err: fmt.Errorf("%w: %v is below the minimum %v", ErrOutOfRange, v, min)}
+}
+
+// CheckMax returns an error wrapping ErrOutOfRange if v is greater than max
+func CheckMax[T cmp.Ordered](entity, field string, v, max T) error {
+	if v <= max {
+		return nil
+	}
+	return &ApplyError{Entity: entity, Field: field, Err: fmt.Errorf("%w: %v is above the maximum %v", ErrOutOfRange, v, max)}
+}
+
+// CheckMaxLen returns an error wrapping ErrOutOfRange if length is greater
+// than max
+func CheckMaxLen(entity, field string, length, max int) error {
+	if length <= max {
+		return nil
+	}
+	return &ApplyError{Entity: entity, Field: field, Err: fmt.Errorf("%w: length %d is above the maximum %d", ErrOutOfRange, length, max)}
+}
+
+// ValidateSlice validates the nested deltas of cd against the entities of s
+// they will be applied to, or against a zero entity for added ones
+func ValidateSlice[E any, PE EntityPtr[E, K], K comparable, D ElementDelta](entity, field string, s []E, cd *CollectionDelta[K, D]) error {
+	if cd == nil {
+		return nil
+	}
+	index := make(map[K]int, len(s))
+	for i := range s {
+		index[PE(&s[i]).GetID()] = i
+	}
+
+	for k, d := range cd.Changed {
+		if i, ok := index[k]; ok {
+			if err := validateElement(entity, field, k, PE(&s[i]), d); err != nil {
+				return err
+			}
+		}
+	}
+	for k, d := range cd.Added {
+		var zero E
+		if err := validateElement(entity, field, k, PE(&zero), d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateMap validates the nested deltas of cd against the entities of m
+// they will be applied to, or against a zero entity for added ones
+func ValidateMap[K comparable, E any, PE EntityPtr[E, ID], ID comparable, D ElementDelta](entity, field string, m map[K]E, cd *CollectionDelta[K, D]) error {
+	if cd == nil {
+		return nil
+	}
+	for k, d := range cd.Changed {
+		if e, ok := m[k]; ok {
+			if err := validateElement(entity, field, k, PE(&e), d); err != nil {
+				return err
+			}
+		}
+	}
+	for k, d := range cd.Added {
+		var zero E
+		if err := validateElement(entity, field, k, PE(&zero), d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateInterface validates the nested delta of an interface field against
+// the entity held by current, or a new entity of the registered type when the
+// value is replaced
+func ValidateInterface[T any](entity, field string, current T, id *InterfaceDelta) error {
+	if id == nil || id.TypeID == 0 {
+		return nil
+	}
+
+	target, ok := any(current).(Entity)
+	if id.Full {
+		e, err := NewEntity(id.TypeID)
+		if err != nil {
+			return &ApplyError{Entity: entity, Field: field, Err: err}
+		}
+		if _, ok := e.(T); !ok {
+			return &ApplyError{Entity: entity, Field: field, Err: fmt.Errorf("%w: type ID %d registers %T", ErrTypeMismatch, id.TypeID, e)}
+		}
+		target = e
+	} else if !ok {
+		return &ApplyError{Entity: entity, Field: field, Err: fmt.Errorf("%w: field holds no entity to change", ErrTypeMismatch)}
+	}
+	return validateElement(entity, field, nil, target, id.Delta)
+}
+
+// validateElement validates d against e, reporting errors at field[key]
+// within entity
+func validateElement(entity, field string, key any, e Entity, d Delta) error {
+	vd, ok := d.(ValidatingDelta)
+	if !ok {
+		return nil
+	}
+	return nestedApplyError(entity, field, key, vd.Validate(e))
+}
+
+// nestedApplyError returns err, the error validating a nested entity, as an
+// error of entity at field[key], or of field when key is nil
+func nestedApplyError(entity, field string, key any, err error) error {
+	var ae *ApplyError
+	if !errors.As(err, &ae) {
+		return err
+	}
+
+	path := field
+	if key != nil {
+		path = fmt.Sprintf("%s[%v]", field, key)
+	}
+	if ae.Field != "" {
+		path += "." + ae.Field
+	}
+	return &ApplyError{Entity: entity, Field: path, Err: ae.Err}
+}